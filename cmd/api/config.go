@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const configUsage = `usage: api config <command>
+
+commands:
+  print [--redacted]   print the settings in effect as a config file,
+                       with passwords, secrets and tokens hidden if asked`
+
+// runConfig runs the config subcommand.
+func runConfig(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", configUsage)
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "print":
+		redact := false
+		for _, arg := range args {
+			if arg != "--redacted" {
+				return fmt.Errorf("unknown flag %q\n%s", arg, configUsage)
+			}
+			redact = true
+		}
+
+		out, err := yaml.Marshal(cfg.Settings(redact))
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, configUsage)
+	}
+}
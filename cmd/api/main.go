@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/kjj1998/task-management-system/internal/config"
 	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/server"
+	"github.com/kjj1998/task-management-system/internal/tracing"
 )
 
 func main() {
@@ -15,7 +23,24 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	logger := logger.NewLogger(cfg.Environment)
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("config: %v", err)
+		}
+		return
+	}
+
+	logger, logLevel, err := logger.New(os.Stdout, cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, logger, os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
 
 	if cfg.IsDevelopment() {
 		logger.Info("Running in development environment")
@@ -23,6 +48,64 @@ func main() {
 		logger.Info("Running in production environment")
 	}
 
-	server := server.NewTaskManagementSystemServer(cfg, logger)
-	log.Fatal(http.ListenAndServe(":"+cfg.Server.Port, server))
+	// The first SIGINT or SIGTERM starts a graceful shutdown; a second one
+	// kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	app, err := server.NewTaskManagementSystemServer(ctx, cfg, logger, logLevel)
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+
+	go reloadOnHangup(ctx, app, cfg, logger)
+
+	httpServer := &http.Server{
+		Addr:              cfg.ServerAddress(),
+		Handler:           app,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("server listening", slog.String("address", httpServer.Addr))
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		app.Close()
+		log.Fatalf("Server stopped: %v", err)
+	case <-ctx.Done():
+		stop()
+	}
+
+	app.BeginShutdown()
+	logger.Info("shutting down, no longer ready", slog.Duration("delay", cfg.Server.ShutdownDelay))
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	logger.Info("draining in-flight requests", slog.Duration("timeout", cfg.Server.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("requests were still running when the shutdown timeout passed", slog.String("error", err.Error()))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", slog.String("error", err.Error()))
+	}
+	if err := app.Close(); err != nil {
+		logger.Error("failed to release server resources", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("server stopped")
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/migrate"
+)
+
+const migrateUsage = `usage: api migrate <command>
+
+commands:
+  up [N]           apply all pending migrations, or the next N
+  down [N]         revert the last migration, or the last N
+  goto VERSION     migrate up or down to VERSION (0 reverts everything)
+  force VERSION    record VERSION as applied without running anything,
+                   after repairing a failed migration by hand
+  status           show the schema version and pending migrations`
+
+// runMigrate runs the migrate subcommand against the database configured
+// for the server.
+func runMigrate(cfg *config.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", migrateUsage)
+	}
+	if cfg.Storage != config.StorageDatabase {
+		return fmt.Errorf("STORAGE=%s has no schema to migrate", cfg.Storage)
+	}
+
+	if err := database.ConnectFromConfig(cfg.Database, logger); err != nil {
+		return err
+	}
+	defer database.Close(logger)
+
+	migrator, err := migrate.New(database.GetDb(), cfg.Database.Driver, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	command, args := args[0], args[1:]
+	switch command {
+	case "up":
+		if len(args) == 0 {
+			return migrator.Up(ctx)
+		}
+		n, err := parseSteps(args)
+		if err != nil {
+			return err
+		}
+		return migrator.Steps(ctx, n)
+	case "down":
+		n := 1
+		if len(args) > 0 {
+			if n, err = parseSteps(args); err != nil {
+				return err
+			}
+		}
+		return migrator.Steps(ctx, -n)
+	case "goto", "force":
+		if len(args) != 1 {
+			return fmt.Errorf("%s needs a VERSION\n%s", command, migrateUsage)
+		}
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("VERSION must be a non-negative integer: %w", err)
+		}
+		if command == "force" {
+			return migrator.Force(ctx, uint(version))
+		}
+		return migrator.Goto(ctx, uint(version))
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(status)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, migrateUsage)
+	}
+}
+
+func parseSteps(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("too many arguments\n%s", migrateUsage)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("N must be a positive integer")
+	}
+
+	return n, nil
+}
+
+func printStatus(status *migrate.Status) {
+	fmt.Printf("version: %d", status.Version)
+	if status.Dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\n", migration.Version, migration.Name, state)
+	}
+	w.Flush()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/server"
+)
+
+// reloadOnHangup reloads the configuration each time the process receives
+// SIGHUP, until ctx is done, and applies the settings that can change
+// while the server runs. A configuration that fails to load is reported
+// and the running one kept; one that is applied becomes what the next
+// reload is compared against.
+func reloadOnHangup(ctx context.Context, app *server.TaskManagementSystemServer, running *config.Config, logger *slog.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+
+		next, err := config.Load()
+		if err != nil {
+			logger.Error("failed to reload configuration, keeping the running one", slog.String("error", err.Error()))
+			continue
+		}
+		if err := app.Reload(next); err != nil {
+			logger.Error("failed to apply reloaded configuration", slog.String("error", err.Error()))
+			continue
+		}
+
+		logger.Info("configuration reloaded",
+			slog.String("log_level", next.Logging.Level),
+			slog.Any("cors_allowed_origins", next.CORS.AllowedOrigins),
+			slog.Float64("rate_limit_rps", next.RateLimit.RequestsPerSecond),
+			slog.Int("rate_limit_burst", next.RateLimit.Burst),
+		)
+		if changed := running.RestartRequired(next); len(changed) > 0 {
+			logger.Warn("changed settings take effect after a restart", slog.Any("settings", changed))
+		}
+		running = next
+	}
+}
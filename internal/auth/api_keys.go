@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix marks bearer tokens that are personal API keys rather than
+// JWT access tokens.
+const APIKeyPrefix = "tms_"
+
+const apiKeyDisplayLength = 8
+
+// NewAPIKey returns a new random API key, the short prefix shown to users to
+// identify it, and the hash that is stored in its place.
+func NewAPIKey() (string, string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return key, key[:len(APIKeyPrefix)+apiKeyDisplayLength], HashToken(key), nil
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHashing(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse battery")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "correct horse battery", hash)
+
+	assert.NoError(t, auth.CheckPassword(hash, "correct horse battery"))
+	assert.ErrorIs(t, auth.CheckPassword(hash, "wrong password"), auth.ErrPasswordMismatch)
+
+	_, err = auth.HashPassword(string(make([]byte, auth.MaxPasswordLength+1)))
+	assert.Error(t, err)
+}
+
+func TestAccessTokens(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	tokens := auth.NewTokenManager(secret, "test-issuer", time.Minute)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		token, expiresAt, err := tokens.IssueAccessToken("1244ABC", "john@email.com")
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+
+		identity, err := tokens.ParseAccessToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "1244ABC", identity.UserID)
+		assert.Equal(t, "john@email.com", identity.Email)
+	})
+
+	t.Run("WrongSecret", func(t *testing.T) {
+		other := auth.NewTokenManager("ffffffffffffffffffffffffffffffff", "test-issuer", time.Minute)
+		token, _, err := other.IssueAccessToken("1244ABC", "john@email.com")
+		assert.NoError(t, err)
+
+		_, err = tokens.ParseAccessToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("WrongIssuer", func(t *testing.T) {
+		other := auth.NewTokenManager(secret, "other-issuer", time.Minute)
+		token, _, err := other.IssueAccessToken("1244ABC", "john@email.com")
+		assert.NoError(t, err)
+
+		_, err = tokens.ParseAccessToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := auth.NewTokenManager(secret, "test-issuer", -time.Minute)
+		token, _, err := expired.IssueAccessToken("1244ABC", "john@email.com")
+		assert.NoError(t, err)
+
+		_, err = tokens.ParseAccessToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("Garbage", func(t *testing.T) {
+		_, err := tokens.ParseAccessToken("not-a-jwt")
+		assert.Error(t, err)
+	})
+}
+
+func TestRefreshTokens(t *testing.T) {
+	token, hash, err := auth.NewRefreshToken()
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, auth.HashToken(token))
+
+	other, _, err := auth.NewRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestAPIKeys(t *testing.T) {
+	key, prefix, hash, err := auth.NewAPIKey()
+	assert.NoError(t, err)
+	assert.True(t, auth.IsAPIKey(key))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, prefix, len(auth.APIKeyPrefix)+8)
+	assert.Equal(t, hash, auth.HashToken(key))
+
+	other, _, _, err := auth.NewAPIKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	assert.False(t, auth.IsAPIKey("eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+}
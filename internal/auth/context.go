@@ -0,0 +1,31 @@
+package auth
+
+import "context"
+
+type contextKey struct{}
+
+type Method string
+
+const (
+	MethodAccessToken Method = "access_token"
+	MethodAPIKey      Method = "api_key"
+)
+
+// Identity is the authenticated caller of a request. ReadOnly is set for
+// API keys that were minted with the read scope.
+type Identity struct {
+	UserID   string
+	Email    string
+	Method   Method
+	APIKeyID string
+	ReadOnly bool
+}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(*Identity)
+	return identity, ok && identity != nil
+}
@@ -2,57 +2,341 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// The defaults for credentials only suit development, and are refused in
+// production.
+const (
+	defaultJWTSecret      = "dev-jwt-secret-change-me"
+	defaultDBPassword     = "taskpass"
+	defaultDBRootPassword = "rootpass"
+)
+
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+)
+
+// sqliteScheme prefixes a DB_DSN that names a SQLite database file, as in
+// sqlite:///var/lib/taskapi/tasks.db or sqlite:tasks.db.
+const sqliteScheme = "sqlite:"
+
+// defaultDBPorts are the ports each driver's server listens on by default.
+var defaultDBPorts = map[string]string{
+	DriverMySQL:    "3306",
+	DriverPostgres: "5432",
+}
+
 type Config struct {
 	Environment string
+	Storage     string
 	Server      ServerConfig
 	Database    DatabaseConfig
 	Logging     LoggingConfig
+	Tracing     TracingConfig
+	Auth        AuthConfig
+	CORS        CORSConfig
+	RateLimit   RateLimitConfig
+	Admin       AdminConfig
+	Workflow    WorkflowConfig
+
+	// settings are the resolved values of the settings, by the name of
+	// their environment variable.
+	settings map[string]string
 }
 
 type ServerConfig struct {
-	Port string
-	Host string
+	Port              string
+	Host              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownDelay is how long the server keeps serving after it starts
+	// failing readiness probes, so load balancers stop routing to it before
+	// it stops accepting connections.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout is how long in-flight requests get to finish once
+	// the server is asked to stop.
+	ShutdownTimeout time.Duration
+	// ReadinessTimeout bounds the dependency checks of a readiness probe.
+	ReadinessTimeout time.Duration
 }
 
 type DatabaseConfig struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
-	Name     string
-	RootPass string
+	Driver string
+	// Path is the SQLite database file. It is only used by the sqlite driver,
+	// which needs no server and ignores the connection settings below.
+	Path         string
+	User         string
+	Password     string
+	Host         string
+	Port         string
+	Name         string
+	RootPass     string
+	QueryTimeout time.Duration
+	// MigrateOnStartup applies pending migrations before the server starts
+	// serving requests.
+	MigrateOnStartup bool
+	// ConnectAttempts is how many times the server tries to reach the
+	// database at startup, waiting ConnectBackoff after the first failure
+	// and twice as long after each one that follows.
+	ConnectAttempts int
+	ConnectBackoff  time.Duration
 }
 
 type LoggingConfig struct {
-	Level string
+	// Level is the least severe level logged: debug, info, warn or error.
+	// It defaults to debug in development and info elsewhere, and can be
+	// changed while the server runs.
+	Level  string
+	Format string
+	// DebugSampleRatio is the fraction of requests whose debug records are
+	// logged. Records are kept or dropped a whole trace at a time.
+	DebugSampleRatio float64
+}
+
+type TracingConfig struct {
+	// Exporter is where finished spans are sent: nowhere, to stdout, or to
+	// an OTLP collector over HTTP.
+	Exporter    string
+	ServiceName string
+	// OTLPEndpoint is the collector's URL. When it is empty, the standard
+	// OTEL_EXPORTER_OTLP_* variables apply.
+	OTLPEndpoint string
+	// SampleRatio is the fraction of new traces that are recorded. Traces
+	// started by a caller keep the caller's sampling decision.
+	SampleRatio float64
+}
+
+type CORSConfig struct {
+	// AllowedOrigins are the origins browsers may call the API from, or
+	// "*" for any.
+	AllowedOrigins []string
+}
+
+// RateLimitConfig limits the requests each client address may make. A
+// RequestsPerSecond of 0 turns the limit off.
+type RateLimitConfig struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// AdminConfig protects the admin endpoints. They are disabled while Token
+// is empty.
+type AdminConfig struct {
+	Token string
+}
+
+// WorkflowConfig adjusts the status transitions tasks may make.
+type WorkflowConfig struct {
+	// AllowReopenWithoutAction lets a status edit through PUT or PATCH move
+	// a completed task back to pending, not just the reopen action.
+	AllowReopenWithoutAction bool
+}
+
+type AuthConfig struct {
+	JWTSecret       string
+	JWTIssuer       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 func Load() (*Config, error) {
-	env := getEnvWithDefault("ENV", "dev")
-	
+	src, err := newSource(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return nil, err
+	}
+
+	env := src.get("ENV", "dev")
+
+	accessTokenTTL, err := src.duration("ACCESS_TOKEN_TTL", "15m")
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenTTL, err := src.duration("REFRESH_TOKEN_TTL", "720h")
+	if err != nil {
+		return nil, err
+	}
+
+	queryTimeout, err := src.duration("DB_QUERY_TIMEOUT", "5s")
+	if err != nil {
+		return nil, err
+	}
+	connectBackoff, err := src.duration("DB_CONNECT_BACKOFF", "1s")
+	if err != nil {
+		return nil, err
+	}
+	connectAttempts, err := strconv.Atoi(src.get("DB_CONNECT_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("DB_CONNECT_ATTEMPTS must be a valid integer: %w", err)
+	}
+
+	readTimeout, err := src.duration("SERVER_READ_TIMEOUT", "15s")
+	if err != nil {
+		return nil, err
+	}
+	readHeaderTimeout, err := src.duration("SERVER_READ_HEADER_TIMEOUT", "5s")
+	if err != nil {
+		return nil, err
+	}
+	writeTimeout, err := src.duration("SERVER_WRITE_TIMEOUT", "30s")
+	if err != nil {
+		return nil, err
+	}
+	idleTimeout, err := src.duration("SERVER_IDLE_TIMEOUT", "120s")
+	if err != nil {
+		return nil, err
+	}
+	shutdownDelay, err := src.duration("SERVER_SHUTDOWN_DELAY", "5s")
+	if err != nil {
+		return nil, err
+	}
+	shutdownTimeout, err := src.duration("SERVER_SHUTDOWN_TIMEOUT", "30s")
+	if err != nil {
+		return nil, err
+	}
+	readinessTimeout, err := src.duration("READINESS_TIMEOUT", "2s")
+	if err != nil {
+		return nil, err
+	}
+
+	migrateOnStartup, err := strconv.ParseBool(src.get("DB_MIGRATE_ON_STARTUP", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("DB_MIGRATE_ON_STARTUP must be a boolean: %w", err)
+	}
+
+	defaultLogLevel := "info"
+	if env == "dev" {
+		defaultLogLevel = "debug"
+	}
+	debugSampleRatio, err := strconv.ParseFloat(src.get("LOG_DEBUG_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("LOG_DEBUG_SAMPLE_RATIO must be a number: %w", err)
+	}
+
+	sampleRatio, err := strconv.ParseFloat(src.get("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("TRACING_SAMPLE_RATIO must be a number: %w", err)
+	}
+
+	rateLimitRPS, err := strconv.ParseFloat(src.get("RATE_LIMIT_RPS", "0"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_RPS must be a number: %w", err)
+	}
+	rateLimitBurst, err := strconv.Atoi(src.get("RATE_LIMIT_BURST", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_BURST must be a valid integer: %w", err)
+	}
+
+	allowReopenWithoutAction, err := strconv.ParseBool(src.get("WORKFLOW_ALLOW_REOPEN_WITHOUT_ACTION", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("WORKFLOW_ALLOW_REOPEN_WITHOUT_ACTION must be a boolean: %w", err)
+	}
+
+	// DB_NAME is read first, as a config file cannot set both DB and the
+	// other DB_ settings nested under db.
+	dbName, ok := src.lookup("DB_NAME")
+	if !ok {
+		dbName = src.get("DB", "taskapi")
+	}
+
+	driver := src.get("DB_DRIVER", DriverMySQL)
+	var sqlitePath string
+	if dsn, ok := src.lookup("DB_DSN"); ok {
+		if !strings.HasPrefix(dsn, sqliteScheme) {
+			return nil, fmt.Errorf("DB_DSN must start with %q", sqliteScheme)
+		}
+		driver = DriverSQLite
+		sqlitePath = strings.TrimPrefix(strings.TrimPrefix(dsn, sqliteScheme), "//")
+	}
+
 	config := &Config{
 		Environment: env,
+		Storage:     src.get("STORAGE", StorageDatabase),
 		Server: ServerConfig{
-			Port: getEnvWithDefault("SERVER_PORT", "8080"),
-			Host: getEnvWithDefault("SERVER_HOST", "0.0.0.0"),
+			Port:              src.get("SERVER_PORT", "8080"),
+			Host:              src.get("SERVER_HOST", "0.0.0.0"),
+			ReadTimeout:       readTimeout,
+			ReadHeaderTimeout: readHeaderTimeout,
+			WriteTimeout:      writeTimeout,
+			IdleTimeout:       idleTimeout,
+			ShutdownDelay:     shutdownDelay,
+			ShutdownTimeout:   shutdownTimeout,
+			ReadinessTimeout:  readinessTimeout,
 		},
 		Database: DatabaseConfig{
-			User:     getEnvWithDefault("DB_USER", "taskuser"),
-			Password: getEnvWithDefault("DB_PASS", "taskpass"),
-			Host:     getEnvWithDefault("DB_HOST", "localhost"),
-			Port:     getEnvWithDefault("DB_PORT", "3306"),
-			Name:     getEnvWithDefault("DB", "taskapi"),
-			RootPass: getEnvWithDefault("DB_ROOT_PASS", "rootpass"),
+			Driver:           driver,
+			Path:             sqlitePath,
+			User:             src.get("DB_USER", "taskuser"),
+			Password:         src.get("DB_PASS", defaultDBPassword),
+			Host:             src.get("DB_HOST", "localhost"),
+			Port:             src.get("DB_PORT", defaultDBPorts[driver]),
+			Name:             dbName,
+			RootPass:         src.get("DB_ROOT_PASS", defaultDBRootPassword),
+			QueryTimeout:     queryTimeout,
+			MigrateOnStartup: migrateOnStartup,
+			ConnectAttempts:  connectAttempts,
+			ConnectBackoff:   connectBackoff,
 		},
 		Logging: LoggingConfig{
-			Level: getEnvWithDefault("LOG_LEVEL", "info"),
+			Level:            src.get("LOG_LEVEL", defaultLogLevel),
+			Format:           src.get("LOG_FORMAT", LogFormatJSON),
+			DebugSampleRatio: debugSampleRatio,
+		},
+		Tracing: TracingConfig{
+			Exporter:     src.get("TRACING_EXPORTER", TracingExporterNone),
+			ServiceName:  src.get("TRACING_SERVICE_NAME", "task-management-system"),
+			OTLPEndpoint: src.get("TRACING_OTLP_ENDPOINT", ""),
+			SampleRatio:  sampleRatio,
 		},
+		Auth: AuthConfig{
+			JWTSecret:       src.get("JWT_SECRET", defaultJWTSecret),
+			JWTIssuer:       src.get("JWT_ISSUER", "task-management-system"),
+			AccessTokenTTL:  accessTokenTTL,
+			RefreshTokenTTL: refreshTokenTTL,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: splitList(src.get("CORS_ALLOWED_ORIGINS", "*")),
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond: rateLimitRPS,
+			Burst:             rateLimitBurst,
+		},
+		Admin: AdminConfig{
+			Token: src.get("ADMIN_TOKEN", ""),
+		},
+		Workflow: WorkflowConfig{
+			AllowReopenWithoutAction: allowReopenWithoutAction,
+		},
+		settings: src.resolved,
 	}
 
+	if err := src.check(); err != nil {
+		return nil, err
+	}
 	if err := config.validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -61,25 +345,120 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
-	if c.Database.User == "" {
+	if c.Storage != StorageDatabase && c.Storage != StorageMemory {
+		return fmt.Errorf("STORAGE must be %q or %q", StorageDatabase, StorageMemory)
+	}
+	if c.IsProduction() && c.Storage == StorageMemory {
+		return fmt.Errorf("STORAGE=%s is not supported in production", StorageMemory)
+	}
+
+	switch c.Database.Driver {
+	case DriverSQLite:
+		if c.Database.Path == "" {
+			return fmt.Errorf("DB_DSN naming the database file is required for %s", DriverSQLite)
+		}
+	case DriverMySQL, DriverPostgres:
+		if err := c.Database.validateServer(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("DB_DRIVER must be %q, %q or %q", DriverMySQL, DriverPostgres, DriverSQLite)
+	}
+
+	if c.Database.QueryTimeout <= 0 {
+		return fmt.Errorf("DB_QUERY_TIMEOUT must be positive")
+	}
+	if c.Database.ConnectAttempts < 1 {
+		return fmt.Errorf("DB_CONNECT_ATTEMPTS must be at least 1")
+	}
+	if c.Database.ConnectBackoff <= 0 {
+		return fmt.Errorf("DB_CONNECT_BACKOFF must be positive")
+	}
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		return fmt.Errorf("SERVER_PORT must be a valid integer: %w", err)
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.ReadHeaderTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.IdleTimeout <= 0 || c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SERVER_READ_TIMEOUT, SERVER_READ_HEADER_TIMEOUT, SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT and SERVER_SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.Server.ShutdownDelay < 0 {
+		return fmt.Errorf("SERVER_SHUTDOWN_DELAY must not be negative")
+	}
+	if c.Server.ReadinessTimeout <= 0 {
+		return fmt.Errorf("READINESS_TIMEOUT must be positive")
+	}
+	if c.Server.WriteTimeout <= c.Database.QueryTimeout {
+		return fmt.Errorf("SERVER_WRITE_TIMEOUT must be longer than DB_QUERY_TIMEOUT")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		return fmt.Errorf("LOG_LEVEL must be debug, info, warn or error: %w", err)
+	}
+	if c.Logging.Format != LogFormatJSON && c.Logging.Format != LogFormatText {
+		return fmt.Errorf("LOG_FORMAT must be %q or %q", LogFormatJSON, LogFormatText)
+	}
+	if c.Logging.DebugSampleRatio < 0 || c.Logging.DebugSampleRatio > 1 {
+		return fmt.Errorf("LOG_DEBUG_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be %q, %q or %q", TracingExporterNone, TracingExporterStdout, TracingExporterOTLP)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if len(c.Auth.JWTSecret) < 32 && c.Auth.JWTSecret != defaultJWTSecret {
+		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
+	}
+	if c.IsProduction() && c.Auth.JWTSecret == defaultJWTSecret {
+		return fmt.Errorf("JWT_SECRET is required in production")
+	}
+	if c.IsProduction() && c.Database.Driver != DriverSQLite && c.Database.Password == defaultDBPassword {
+		return fmt.Errorf("DB_PASS must be changed from its default in production")
+	}
+	if c.IsProduction() && c.Database.Driver == DriverMySQL && c.Database.RootPass == defaultDBRootPassword {
+		return fmt.Errorf("DB_ROOT_PASS must be changed from its default in production")
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("ACCESS_TOKEN_TTL and REFRESH_TOKEN_TTL must be positive")
+	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS must not be negative")
+	}
+	if c.RateLimit.Burst < 1 {
+		return fmt.Errorf("RATE_LIMIT_BURST must be at least 1")
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS must name at least one origin, or *")
+	}
+	if c.Admin.Token != "" && len(c.Admin.Token) < 32 {
+		return fmt.Errorf("ADMIN_TOKEN must be at least 32 characters")
+	}
+
+	return nil
+}
+
+// validateServer checks the settings for connecting to a database server.
+func (d *DatabaseConfig) validateServer() error {
+	if d.User == "" {
 		return fmt.Errorf("DB_USER is required")
 	}
-	if c.Database.Password == "" {
+	if d.Password == "" {
 		return fmt.Errorf("DB_PASS is required")
 	}
-	if c.Database.Host == "" {
+	if d.Host == "" {
 		return fmt.Errorf("DB_HOST is required")
 	}
-	if c.Database.Name == "" {
-		return fmt.Errorf("DB is required")
+	if d.Name == "" {
+		return fmt.Errorf("DB_NAME or DB is required")
 	}
 
-	if _, err := strconv.Atoi(c.Database.Port); err != nil {
+	if _, err := strconv.Atoi(d.Port); err != nil {
 		return fmt.Errorf("DB_PORT must be a valid integer: %w", err)
 	}
-	if _, err := strconv.Atoi(c.Server.Port); err != nil {
-		return fmt.Errorf("SERVER_PORT must be a valid integer: %w", err)
-	}
 
 	return nil
 }
@@ -96,9 +475,14 @@ func (c *Config) ServerAddress() string {
 	return c.Server.Host + ":" + c.Server.Port
 }
 
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// splitList splits a comma-separated setting, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	return defaultValue
-}
\ No newline at end of file
+
+	return items
+}
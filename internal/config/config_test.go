@@ -0,0 +1,152 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jwtSecret = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+server:
+  port: 9090
+db:
+  name: tasks
+  pass: from-file
+log:
+  level: warn
+cors:
+  allowed_origins: [https://app.example.com, https://admin.example.com]
+workflow:
+  allow_reopen_without_action: true
+RATE_LIMIT_RPS: 5
+`,
+		"config.toml": `
+RATE_LIMIT_RPS = 5
+
+[server]
+port = 9090
+
+[db]
+name = "tasks"
+pass = "from-file"
+
+[log]
+level = "warn"
+
+[cors]
+allowed_origins = ["https://app.example.com", "https://admin.example.com"]
+
+[workflow]
+allow_reopen_without_action = true
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", writeFile(t, name, content))
+			t.Setenv("SERVER_PORT", "7070")
+
+			cfg, err := config.Load()
+			require.NoError(t, err)
+
+			assert.Equal(t, "7070", cfg.Server.Port, "the environment overrides the file")
+			assert.Equal(t, "tasks", cfg.Database.Name)
+			assert.Equal(t, "from-file", cfg.Database.Password)
+			assert.Equal(t, "warn", cfg.Logging.Level)
+			assert.Equal(t, []string{"https://app.example.com", "https://admin.example.com"}, cfg.CORS.AllowedOrigins)
+			assert.Equal(t, 5.0, cfg.RateLimit.RequestsPerSecond)
+			assert.True(t, cfg.Workflow.AllowReopenWithoutAction)
+			assert.Equal(t, "localhost", cfg.Database.Host, "unset settings keep their default")
+		})
+	}
+}
+
+func TestLoadRejectsUnknownSettings(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "srever:\n  port: 9090\n"))
+
+	_, err := config.Load()
+	assert.ErrorContains(t, err, "SREVER_PORT")
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	t.Setenv("DB_PASS_FILE", writeFile(t, "db_pass", "s3cret\n"))
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "jwt_secret_file: "+writeFile(t, "jwt", jwtSecret)+"\n"))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, jwtSecret, cfg.Auth.JWTSecret)
+
+	t.Setenv("DB_PASS_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = config.Load()
+	assert.ErrorContains(t, err, "DB_PASS_FILE")
+}
+
+func TestLoadRefusesDefaultCredentialsInProduction(t *testing.T) {
+	t.Setenv("ENV", "prod")
+	t.Setenv("JWT_SECRET", jwtSecret)
+
+	_, err := config.Load()
+	assert.ErrorContains(t, err, "DB_PASS")
+
+	t.Setenv("DB_PASS", "a-real-password")
+	_, err = config.Load()
+	assert.ErrorContains(t, err, "DB_ROOT_PASS")
+
+	t.Setenv("DB_DRIVER", "postgres")
+	_, err = config.Load()
+	assert.NoError(t, err, "only MySQL uses the root password")
+
+	t.Setenv("DB_DRIVER", "mysql")
+	t.Setenv("DB_ROOT_PASS", "a-real-root-password")
+	_, err = config.Load()
+	assert.NoError(t, err)
+}
+
+func TestSettings(t *testing.T) {
+	t.Setenv("JWT_SECRET", jwtSecret)
+	t.Setenv("DB_PASS", "s3cret")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "s3cret", cfg.Settings(false)["DB_PASS"])
+	redacted := cfg.Settings(true)
+	assert.Equal(t, "[REDACTED]", redacted["DB_PASS"])
+	assert.Equal(t, "[REDACTED]", redacted["JWT_SECRET"])
+	assert.Equal(t, "8080", redacted["SERVER_PORT"])
+}
+
+func TestRestartRequired(t *testing.T) {
+	running, err := config.Load()
+	require.NoError(t, err)
+
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("RATE_LIMIT_RPS", "10")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	reloaded, err := config.Load()
+	require.NoError(t, err)
+	assert.Empty(t, running.RestartRequired(reloaded))
+
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("TRACING_OTLP_ENDPOINT", "http://collector:4318")
+	reloaded, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SERVER_PORT", "TRACING_OTLP_ENDPOINT"}, running.RestartRequired(reloaded))
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// reloadableSettings take effect when the running server reloads its
+// configuration. All others need a restart.
+var reloadableSettings = map[string]bool{
+	"LOG_LEVEL":            true,
+	"CORS_ALLOWED_ORIGINS": true,
+	"RATE_LIMIT_RPS":       true,
+	"RATE_LIMIT_BURST":     true,
+}
+
+func isSecret(key string) bool {
+	return strings.HasSuffix(key, "_PASS") || strings.HasSuffix(key, "_SECRET") || strings.HasSuffix(key, "_TOKEN")
+}
+
+// Settings returns the value of every setting, by the name of its
+// environment variable, in a form a config file accepts. With redact set,
+// passwords, secrets and tokens are replaced by a placeholder.
+func (c *Config) Settings(redact bool) map[string]string {
+	settings := make(map[string]string, len(c.settings))
+	for key, value := range c.settings {
+		if redact && isSecret(key) {
+			value = redacted
+		}
+		settings[key] = value
+	}
+
+	return settings
+}
+
+// RestartRequired returns the settings that differ in next and do not take
+// effect until the server restarts.
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	for key := range c.settings {
+		if !reloadableSettings[key] && next.settings[key] != c.settings[key] {
+			changed = append(changed, key)
+		}
+	}
+	for key := range next.settings {
+		if _, ok := c.settings[key]; !ok && !reloadableSettings[key] {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed
+}
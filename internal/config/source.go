@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileSuffix marks a setting whose value is read from the file it names,
+// as Docker and Kubernetes mount secrets.
+const fileSuffix = "_FILE"
+
+// source resolves settings by the name of their environment variable. The
+// environment comes first, then the config file, then the default. In
+// either place KEY_FILE can stand in for KEY.
+type source struct {
+	file map[string]string
+	// resolved records the value each setting was given, for printing and
+	// for comparing one load with the next.
+	resolved map[string]string
+	// seen records every setting asked for, to catch unknown keys in the
+	// config file.
+	seen map[string]bool
+	err  error
+}
+
+func newSource(path string) (*source, error) {
+	s := &source{file: map[string]string{}, resolved: map[string]string{}, seen: map[string]bool{}}
+	if path == "" {
+		return s, nil
+	}
+
+	file, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s.file = file
+
+	return s, nil
+}
+
+// lookup returns the value set for key, if any. An empty value counts as
+// unset.
+func (s *source) lookup(key string) (string, bool) {
+	s.seen[key] = true
+
+	value, err := s.find(key)
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return "", false
+	}
+	if value == "" {
+		return "", false
+	}
+
+	s.resolved[key] = value
+	return value, true
+}
+
+func (s *source) find(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	if path := os.Getenv(key + fileSuffix); path != "" {
+		return readSecret(key, path)
+	}
+	if value := s.file[key]; value != "" {
+		return value, nil
+	}
+	if path := s.file[key+fileSuffix]; path != "" {
+		return readSecret(key, path)
+	}
+
+	return "", nil
+}
+
+func (s *source) get(key, defaultValue string) string {
+	if value, ok := s.lookup(key); ok {
+		return value
+	}
+
+	if defaultValue != "" {
+		s.resolved[key] = defaultValue
+	}
+	return defaultValue
+}
+
+func (s *source) duration(key, defaultValue string) (time.Duration, error) {
+	d, err := time.ParseDuration(s.get(key, defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a valid duration: %w", key, err)
+	}
+
+	return d, nil
+}
+
+// check reports the first secret file that could not be read, and any key
+// in the config file that names no setting.
+func (s *source) check() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	var unknown []string
+	for key := range s.file {
+		if !s.seen[key] && !s.seen[strings.TrimSuffix(key, fileSuffix)] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown settings in config file: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+func readSecret(key, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s%s: %w", key, fileSuffix, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// readConfigFile reads a YAML or TOML config file into settings named as
+// their environment variables. Keys are matched case-insensitively, and
+// nested tables are joined with underscores, so
+//
+//	server:
+//	  port: 8080
+//
+// sets SERVER_PORT. Lists are joined with commas.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	tree := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	settings := map[string]string{}
+	if err := flatten("", tree, settings); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return settings, nil
+}
+
+func flatten(prefix string, tree map[string]any, settings map[string]string) error {
+	for key, value := range tree {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(name, v, settings); err != nil {
+				return err
+			}
+			continue
+		case nil:
+			continue
+		}
+
+		if _, ok := settings[name]; ok {
+			return fmt.Errorf("%s is set more than once", name)
+		}
+		if list, ok := value.([]any); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			settings[name] = strings.Join(items, ",")
+		} else {
+			settings[name] = fmt.Sprint(value)
+		}
+	}
+
+	return nil
+}
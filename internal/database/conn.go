@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/kjj1998/task-management-system/internal/database")
+
+// Queryer runs statements. It is satisfied by both *sql.DB and *sql.Tx.
+type Queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Tx is a transaction opened by a repository method.
+type Tx interface {
+	Queryer
+	Commit() error
+	Rollback() error
+}
+
+// Conn is the handle repositories run their statements on. It is either the
+// connection pool, or a transaction shared by every repository taking part
+// in a unit of work.
+type Conn interface {
+	Queryer
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
+}
+
+type poolConn struct {
+	traced
+	db *sql.DB
+}
+
+// NewConn returns a Conn on the connection pool. Each transaction a
+// repository method begins on it is a real database transaction.
+func NewConn(db *sql.DB) Conn {
+	return poolConn{traced: traced{db}, db: db}
+}
+
+func (c poolConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := c.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracedTx{traced: traced{tx}, tx: tx}, nil
+}
+
+type txConn struct {
+	traced
+}
+
+// NewTxConn returns a Conn bound to tx. Transactions begun on it join tx, so
+// their Commit and Rollback are left to whoever owns tx.
+func NewTxConn(tx *sql.Tx) Conn {
+	return txConn{traced{tx}}
+}
+
+func (c txConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	return joinedTx{Queryer: c.traced}, nil
+}
+
+type joinedTx struct {
+	Queryer
+}
+
+func (joinedTx) Commit() error {
+	return nil
+}
+
+func (joinedTx) Rollback() error {
+	return nil
+}
+
+type tracedTx struct {
+	traced
+	tx *sql.Tx
+}
+
+func (t tracedTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t tracedTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// traced runs each statement in a span of its own, named after the kind of
+// statement it is.
+type traced struct {
+	q Queryer
+}
+
+func (t traced) ExecContext(ctx context.Context, query string, args ...any) (_ sql.Result, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { tracing.End(span, err) }()
+
+	return t.q.ExecContext(ctx, query, args...)
+}
+
+func (t traced) QueryContext(ctx context.Context, query string, args ...any) (_ *sql.Rows, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { tracing.End(span, err) }()
+
+	return t.q.QueryContext(ctx, query, args...)
+}
+
+func (t traced) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+	row := t.q.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+
+	return row
+}
+
+// startSpan starts the span of a statement. Only the statement text is
+// recorded, never its arguments.
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", query),
+		),
+	)
+}
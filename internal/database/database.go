@@ -1,15 +1,71 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/kjj1998/task-management-system/internal/config"
+	_ "modernc.org/sqlite"
 )
 
 var DB *sql.DB
 
+// pingTimeout bounds how long opening a connection waits for the server to
+// answer, so an unreachable host fails an attempt instead of hanging it.
+const pingTimeout = 5 * time.Second
+
+// maxConnectBackoff caps the wait between connection attempts.
+const maxConnectBackoff = 30 * time.Second
+
+// ConnectFromConfig opens the connection pool on the database cfg names.
+func ConnectFromConfig(cfg config.DatabaseConfig, logger *slog.Logger) error {
+	switch cfg.Driver {
+	case config.DriverSQLite:
+		return ConnectSQLite(cfg.Path, logger)
+	case config.DriverPostgres:
+		return ConnectPostgres(cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, logger)
+	default:
+		return Connect(cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, logger)
+	}
+}
+
+// ConnectWithRetry calls ConnectFromConfig until it succeeds, waiting
+// cfg.ConnectBackoff after the first failure and twice as long after each
+// one that follows. It gives up after cfg.ConnectAttempts attempts, or when
+// ctx is done.
+func ConnectWithRetry(ctx context.Context, cfg config.DatabaseConfig, logger *slog.Logger) error {
+	backoff := cfg.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		err := ConnectFromConfig(cfg, logger)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.ConnectAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn("database connection failed, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up connecting to the database: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
 func Connect(user, password, host, port, dbName string, logger *slog.Logger) error {
 	// Capture connection properties
 	cfg := mysql.NewConfig()
@@ -19,8 +75,43 @@ func Connect(user, password, host, port, dbName string, logger *slog.Logger) err
 	cfg.Addr = host + ":" + port
 	cfg.DBName = dbName
 	cfg.Params = map[string]string{"parseTime": "true"}
+	// Report matched rather than changed rows so an UPDATE that leaves a row
+	// unchanged is not mistaken for a missing row.
+	cfg.ClientFoundRows = true
+
+	return open("mysql", cfg.FormatDSN(), user, host, port, dbName, logger)
+}
+
+// ConnectPostgres opens the connection pool on a PostgreSQL server through
+// the pgx driver.
+func ConnectPostgres(user, password, host, port, dbName string, logger *slog.Logger) error {
+	dsn := (&url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(user, password),
+		Host:   net.JoinHostPort(host, port),
+		Path:   dbName,
+	}).String()
 
+	return open("pgx", dsn, user, host, port, dbName, logger)
+}
+
+// ConnectSQLite opens the database file at path, creating it if needed.
+// Every connection enforces foreign keys, shares the file through WAL mode,
+// waits for locks held by other connections and begins its transactions
+// with the write lock, so concurrent units of work queue instead of failing.
+func ConnectSQLite(path string, logger *slog.Logger) error {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+
+	return open("sqlite", path+"?"+params.Encode(), "", "", "", path, logger)
+}
+
+func open(driverName, dsn, user, host, port, dbName string, logger *slog.Logger) error {
 	logger.Info("attempting database connection",
+		slog.String("driver", driverName),
 		slog.String("host", host),
 		slog.String("port", port),
 		slog.String("database", dbName),
@@ -28,8 +119,7 @@ func Connect(user, password, host, port, dbName string, logger *slog.Logger) err
 	)
 
 	// Get a database handle
-	var err error
-	db, err := sql.Open("mysql", cfg.FormatDSN())
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		logger.Error("failed to open database connection",
 			slog.String("error", err.Error()),
@@ -39,8 +129,12 @@ func Connect(user, password, host, port, dbName string, logger *slog.Logger) err
 		return fmt.Errorf("failed to open db: %w", err)
 	}
 
-	pingErr := db.Ping()
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	pingErr := db.PingContext(ctx)
 	if pingErr != nil {
+		db.Close()
 		logger.Error("failed to ping database",
 			slog.String("error", pingErr.Error()),
 			slog.String("host", host),
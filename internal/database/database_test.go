@@ -0,0 +1,62 @@
+package database_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachable names a PostgreSQL server on a port nothing listens on.
+var unreachable = config.DatabaseConfig{
+	Driver:   config.DriverPostgres,
+	User:     "testuser",
+	Password: "testpass",
+	Host:     "127.0.0.1",
+	Port:     "1",
+	Name:     "taskapi",
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	logger := logger.NewLogger("test")
+
+	t.Run("GivesUpAfterAttempts", func(t *testing.T) {
+		cfg := unreachable
+		cfg.ConnectAttempts = 3
+		cfg.ConnectBackoff = 10 * time.Millisecond
+
+		start := time.Now()
+		err := database.ConnectWithRetry(context.Background(), cfg, logger)
+		assert.ErrorContains(t, err, "giving up after 3 attempts")
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "waits 10ms, then 20ms")
+	})
+
+	t.Run("StopsWhenCancelled", func(t *testing.T) {
+		cfg := unreachable
+		cfg.ConnectAttempts = 100
+		cfg.ConnectBackoff = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := database.ConnectWithRetry(ctx, cfg, logger)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Connects", func(t *testing.T) {
+		cfg := config.DatabaseConfig{
+			Driver:          config.DriverSQLite,
+			Path:            filepath.Join(t.TempDir(), "taskapi.db"),
+			ConnectAttempts: 1,
+		}
+
+		require.NoError(t, database.ConnectWithRetry(context.Background(), cfg, logger))
+		assert.NoError(t, database.Close(logger))
+	})
+}
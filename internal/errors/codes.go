@@ -0,0 +1,138 @@
+package errors
+
+import "strings"
+
+// Error codes identify the kind of failure to clients independently of the
+// message, which is free to change. They are part of the API: a released
+// code is never renamed or given a different meaning. docs/errors.md
+// describes each of them.
+const (
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeDatabase           = "DATABASE_ERROR"
+	CodeDatabaseTimeout    = "DATABASE_TIMEOUT"
+	CodeRequestCancelled   = "REQUEST_CANCELLED"
+	CodeConcurrentUpdate   = "CONCURRENT_UPDATE"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+	CodeBadRequest            = "BAD_REQUEST"
+	CodeMalformedBody         = "MALFORMED_BODY"
+	CodeValidationFailed      = "VALIDATION_FAILED"
+	CodeInvalidQueryParameter = "INVALID_QUERY_PARAMETER"
+	CodeUnsupportedMediaType  = "UNSUPPORTED_MEDIA_TYPE"
+	CodeInvalidInput          = "INVALID_INPUT"
+	CodeInvalidReference      = "INVALID_REFERENCE"
+	CodePayloadTooLarge       = "PAYLOAD_TOO_LARGE"
+	CodeValueTooLong          = "VALUE_TOO_LONG"
+
+	CodeAuthenticationRequired = "AUTHENTICATION_REQUIRED"
+	CodeInvalidCredentials     = "INVALID_CREDENTIALS"
+	CodeInvalidAccessToken     = "INVALID_ACCESS_TOKEN"
+	CodeInvalidRefreshToken    = "INVALID_REFRESH_TOKEN"
+	CodeInvalidAPIKey          = "INVALID_API_KEY"
+	CodeForbidden              = "FORBIDDEN"
+	CodeReadOnlyAPIKey         = "READ_ONLY_API_KEY"
+
+	CodeResourceNotFound   = "RESOURCE_NOT_FOUND"
+	CodeTaskNotFound       = "TASK_NOT_FOUND"
+	CodeCategoryNotFound   = "CATEGORY_NOT_FOUND"
+	CodeAPIKeyNotFound     = "API_KEY_NOT_FOUND"
+	CodeUserNotFound       = "USER_NOT_FOUND"
+	CodeDependencyNotFound = "DEPENDENCY_NOT_FOUND"
+
+	CodeResourceConflict        = "RESOURCE_CONFLICT"
+	CodeResourceInUse           = "RESOURCE_IN_USE"
+	CodeCategoryNameTaken       = "CATEGORY_NAME_TAKEN"
+	CodeAPIKeyNameTaken         = "API_KEY_NAME_TAKEN"
+	CodeEmailTaken              = "EMAIL_TAKEN"
+	CodeInvalidStatusTransition = "INVALID_STATUS_TRANSITION"
+	CodeDependencyExists        = "DEPENDENCY_EXISTS"
+	CodeDependencyCycle         = "DEPENDENCY_CYCLE"
+	CodeTaskBlocked             = "TASK_BLOCKED"
+
+	CodeRateLimited = "RATE_LIMITED"
+)
+
+// titles are the short, fixed summaries of each code, used as the title of
+// problem details.
+var titles = map[string]string{
+	CodeInternal:           "Internal server error",
+	CodeDatabase:           "Database error",
+	CodeDatabaseTimeout:    "Database timeout",
+	CodeRequestCancelled:   "Request cancelled",
+	CodeConcurrentUpdate:   "Concurrent update",
+	CodeServiceUnavailable: "Service unavailable",
+
+	CodeBadRequest:            "Bad request",
+	CodeMalformedBody:         "Malformed request body",
+	CodeValidationFailed:      "Validation failed",
+	CodeInvalidQueryParameter: "Invalid query parameter",
+	CodeUnsupportedMediaType:  "Unsupported media type",
+	CodeInvalidInput:          "Invalid input value",
+	CodeInvalidReference:      "Invalid reference",
+	CodePayloadTooLarge:       "Payload too large",
+	CodeValueTooLong:          "Value too long",
+
+	CodeAuthenticationRequired: "Authentication required",
+	CodeInvalidCredentials:     "Invalid credentials",
+	CodeInvalidAccessToken:     "Invalid access token",
+	CodeInvalidRefreshToken:    "Invalid refresh token",
+	CodeInvalidAPIKey:          "Invalid API key",
+	CodeForbidden:              "Forbidden",
+	CodeReadOnlyAPIKey:         "Read-only API key",
+
+	CodeResourceNotFound:   "Resource not found",
+	CodeTaskNotFound:       "Task not found",
+	CodeCategoryNotFound:   "Category not found",
+	CodeAPIKeyNotFound:     "API key not found",
+	CodeUserNotFound:       "User not found",
+	CodeDependencyNotFound: "Dependency not found",
+
+	CodeResourceConflict:        "Resource conflict",
+	CodeResourceInUse:           "Resource in use",
+	CodeCategoryNameTaken:       "Category name taken",
+	CodeAPIKeyNameTaken:         "API key name taken",
+	CodeEmailTaken:              "Email taken",
+	CodeInvalidStatusTransition: "Invalid status transition",
+	CodeDependencyExists:        "Dependency exists",
+	CodeDependencyCycle:         "Dependency cycle",
+	CodeTaskBlocked:             "Task blocked",
+
+	CodeRateLimited: "Rate limited",
+}
+
+// problemTypeBase is where the problem type of each code is documented. The
+// anchor of a code is the code in lower case.
+const problemTypeBase = "https://github.com/kjj1998/task-management-system/blob/main/docs/errors.md#"
+
+// Title returns the summary of the problem code identifies.
+func Title(code string) string {
+	if title, ok := titles[code]; ok {
+		return title
+	}
+
+	return titles[CodeInternal]
+}
+
+// ProblemType returns the URI identifying the problem type of code.
+func ProblemType(code string) string {
+	return problemTypeBase + strings.ToLower(code)
+}
+
+// notFoundCode picks the not-found code for the resource a repository
+// operation, named as it is for HandleDatabaseError, looks up.
+func notFoundCode(operation string) string {
+	switch {
+	case strings.Contains(operation, "Dependenc"):
+		return CodeDependencyNotFound
+	case strings.Contains(operation, "Task"):
+		return CodeTaskNotFound
+	case strings.Contains(operation, "Categor"):
+		return CodeCategoryNotFound
+	case strings.Contains(operation, "APIKey"):
+		return CodeAPIKeyNotFound
+	case strings.Contains(operation, "User"):
+		return CodeUserNotFound
+	default:
+		return CodeResourceNotFound
+	}
+}
@@ -1,8 +1,51 @@
 package errors
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
 	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+)
+
+const (
+	mysqlErrDuplicateEntry     = 1062
+	mysqlErrLockWaitTimeout    = 1205
+	mysqlErrDeadlock           = 1213
+	mysqlErrDataTooLong        = 1406
+	mysqlErrRowIsReferenced    = 1451
+	mysqlErrNoReferencedRow    = 1452
+	mysqlErrQueryTimeout       = 3024
+	mysqlErrTooManyConnections = 1040
+
+	pgErrUniqueViolation           = "23505"
+	pgErrForeignKeyViolation       = "23503"
+	pgErrStringDataRightTruncation = "22001"
+	pgErrInvalidTextRepresentation = "22P02"
+	pgErrSerializationFailure      = "40001"
+	pgErrDeadlockDetected          = "40P01"
+	pgErrQueryCanceled             = "57014"
+	pgErrTooManyConnections        = "53300"
+	pgErrClassConnectionException  = "08"
+
+	sqliteErrBusy                 = 5
+	sqliteErrLocked               = 6
+	sqliteErrTooBig               = 18
+	sqliteErrConstraintForeignKey = 787
+	sqliteErrConstraintUnique     = 2067
+	sqliteErrConstraintPrimaryKey = 1555
+
+	UniqueUserCategoryKey = "unique_user_category"
+	UniqueUserEmailKey    = "users.email"
+	UniqueUserEmailIndex  = "unique_user_email"
+	UniqueUserAPIKeyName  = "unique_user_api_key_name"
+	UniqueTaskDependency  = "task_dependencies"
 )
 
 type DatabaseErrorHandler struct{}
@@ -11,18 +54,189 @@ func NewDatabaseErrorHandler() *DatabaseErrorHandler {
 	return &DatabaseErrorHandler{}
 }
 
+// HandleDatabaseError turns an error returned by the database during
+// operation into the AppError reported to the client. The driver error stays
+// wrapped, prefixed with the operation, so it is logged with the response.
 func (d *DatabaseErrorHandler) HandleDatabaseError(operation string, err error) *AppError {
+	appErr := d.classify(operation, err)
+	appErr.Operation = operation
+	return appErr
+}
+
+func (d *DatabaseErrorHandler) classify(operation string, err error) *AppError {
+	var mysqlErr *mysql.MySQLError
+	var pgErr *pgconn.PgError
+	var sqliteErr *sqlite.Error
+	var netErr net.Error
+	wrapped := fmt.Errorf("%s: %w", operation, err)
+
 	switch {
-	case err == sql.ErrNoRows:
-		return NewNotFoundError("Resource not found", err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewDatabaseError("Request timeout", wrapped).WithCode(CodeDatabaseTimeout)
 
-	case strings.Contains(err.Error(), "connection"):
-		return NewDatabaseError("Service temporarily unavailable", nil)
+	case errors.Is(err, context.Canceled):
+		return NewDatabaseError("Request cancelled", wrapped).WithCode(CodeRequestCancelled)
 
-	case strings.Contains(err.Error(), "timeout"):
-		return NewDatabaseError("Request timeout", nil)
+	case errors.Is(err, sql.ErrNoRows):
+		return NewNotFoundError("Resource not found", err).WithCode(notFoundCode(operation))
+
+	case errors.As(err, &mysqlErr):
+		return d.handleMySQLError(operation, mysqlErr, wrapped)
+
+	case errors.As(err, &pgErr):
+		return d.handlePostgresError(operation, pgErr, wrapped)
+
+	case errors.As(err, &sqliteErr):
+		return d.handleSQLiteError(operation, sqliteErr, wrapped)
+
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return NewDatabaseError("Request timeout", wrapped).WithCode(CodeDatabaseTimeout)
+
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn), errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return NewDatabaseError("Service temporarily unavailable", wrapped).WithCode(CodeServiceUnavailable)
 
 	default:
-		return NewDatabaseError("Database operation failed", nil)
+		return NewDatabaseError("Database operation failed", wrapped)
 	}
 }
+
+// HandleUniqueViolation reports a write that would have duplicated a unique
+// key. constraint is the name of the key, or a driver message naming it.
+func (d *DatabaseErrorHandler) HandleUniqueViolation(operation string, constraint string, err error) *AppError {
+	var appErr *AppError
+	switch {
+	case strings.Contains(constraint, UniqueUserCategoryKey):
+		appErr = NewConflictError("A category with this name already exists", err).WithCode(CodeCategoryNameTaken)
+	case strings.Contains(constraint, UniqueUserAPIKeyName):
+		appErr = NewConflictError("An API key with this name already exists", err).WithCode(CodeAPIKeyNameTaken)
+	case strings.Contains(constraint, UniqueTaskDependency):
+		appErr = NewConflictError("The task is already blocked by this task", err).WithCode(CodeDependencyExists)
+	case strings.Contains(constraint, UniqueUserEmailKey), strings.Contains(constraint, UniqueUserEmailIndex):
+		appErr = NewConflictError("An account with this email already exists", err).WithCode(CodeEmailTaken)
+	default:
+		appErr = NewConflictError("Resource already exists", err)
+	}
+	appErr.Operation = operation
+
+	return appErr
+}
+
+// handleForeignKeyViolation reports a write that broke a foreign key: either
+// it referenced a row that does not exist, or it deleted a row that is
+// still referenced.
+func (d *DatabaseErrorHandler) handleForeignKeyViolation(stillReferenced bool, err error) *AppError {
+	if stillReferenced {
+		return NewConflictError("Resource is still in use by other resources", err).WithCode(CodeResourceInUse)
+	}
+
+	return NewInvalidReferenceError("A referenced resource does not exist", err)
+}
+
+// handleMySQLError maps the error number of a MySQL error to its response.
+func (d *DatabaseErrorHandler) handleMySQLError(operation string, mysqlErr *mysql.MySQLError, err error) *AppError {
+	switch mysqlErr.Number {
+	case mysqlErrDeadlock, mysqlErrLockWaitTimeout:
+		return NewRetryableError("Request conflicted with a concurrent update, please retry", err)
+
+	case mysqlErrDuplicateEntry:
+		return d.HandleUniqueViolation(operation, mysqlErr.Message, err)
+
+	case mysqlErrNoReferencedRow, mysqlErrRowIsReferenced:
+		return d.handleForeignKeyViolation(mysqlErr.Number == mysqlErrRowIsReferenced, err)
+
+	case mysqlErrDataTooLong:
+		return NewPayloadTooLargeError("A value is longer than its field allows", err).WithCode(CodeValueTooLong)
+
+	case mysqlErrQueryTimeout:
+		return NewDatabaseError("Request timeout", err).WithCode(CodeDatabaseTimeout)
+
+	case mysqlErrTooManyConnections:
+		return NewDatabaseError("Service temporarily unavailable", err).WithCode(CodeServiceUnavailable)
+
+	default:
+		return NewDatabaseError("Database operation failed", err)
+	}
+}
+
+// handlePostgresError maps the SQLSTATE code of a PostgreSQL error to the
+// same responses the MySQL errors get.
+func (d *DatabaseErrorHandler) handlePostgresError(operation string, pgErr *pgconn.PgError, err error) *AppError {
+	switch {
+	case pgErr.Code == pgErrDeadlockDetected || pgErr.Code == pgErrSerializationFailure:
+		return NewRetryableError("Request conflicted with a concurrent update, please retry", err)
+
+	case pgErr.Code == pgErrUniqueViolation:
+		return d.HandleUniqueViolation(operation, pgErr.ConstraintName, err)
+
+	case pgErr.Code == pgErrForeignKeyViolation:
+		return d.handleForeignKeyViolation(strings.Contains(pgErr.Detail, "is still referenced"), err)
+
+	case pgErr.Code == pgErrStringDataRightTruncation:
+		return NewPayloadTooLargeError("A value is longer than its field allows", err).WithCode(CodeValueTooLong)
+
+	case pgErr.Code == pgErrInvalidTextRepresentation:
+		return NewBadRequestError("Invalid input value", err).WithCode(CodeInvalidInput)
+
+	case pgErr.Code == pgErrQueryCanceled:
+		return NewDatabaseError("Request timeout", err).WithCode(CodeDatabaseTimeout)
+
+	case pgErr.Code == pgErrTooManyConnections || strings.HasPrefix(pgErr.Code, pgErrClassConnectionException):
+		return NewDatabaseError("Service temporarily unavailable", err).WithCode(CodeServiceUnavailable)
+
+	default:
+		return NewDatabaseError("Database operation failed", err)
+	}
+}
+
+// handleSQLiteError maps the extended result code of a SQLite error to the
+// same responses the MySQL errors get. The message of a unique constraint
+// failure names the index or the columns it was on. A foreign key failure
+// does not say which side of the key broke it, so deletes are taken to have
+// removed a row that is still referenced.
+func (d *DatabaseErrorHandler) handleSQLiteError(operation string, sqliteErr *sqlite.Error, err error) *AppError {
+	switch {
+	case isSQLiteBusy(sqliteErr):
+		return NewRetryableError("Request conflicted with a concurrent update, please retry", err)
+
+	case sqliteErr.Code() == sqliteErrConstraintUnique || sqliteErr.Code() == sqliteErrConstraintPrimaryKey:
+		return d.HandleUniqueViolation(operation, sqliteErr.Error(), err)
+
+	case sqliteErr.Code() == sqliteErrConstraintForeignKey:
+		return d.handleForeignKeyViolation(strings.HasPrefix(operation, "Delete"), err)
+
+	case sqliteErr.Code()&0xff == sqliteErrTooBig:
+		return NewPayloadTooLargeError("A value is longer than its field allows", err).WithCode(CodeValueTooLong)
+
+	default:
+		return NewDatabaseError("Database operation failed", err)
+	}
+}
+
+// isSQLiteBusy reports whether SQLite gave up waiting for a lock held by
+// another connection.
+func isSQLiteBusy(sqliteErr *sqlite.Error) bool {
+	primary := sqliteErr.Code() & 0xff
+	return primary == sqliteErrBusy || primary == sqliteErrLocked
+}
+
+// IsDeadlock reports whether err was caused by the database rolling back a
+// transaction to break a deadlock, by PostgreSQL failing to serialise it or
+// by SQLite timing out on a lock. The transaction can safely be retried.
+func IsDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgErrDeadlockDetected || pgErr.Code == pgErrSerializationFailure
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return isSQLiteBusy(sqliteErr)
+	}
+
+	return false
+}
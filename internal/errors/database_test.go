@@ -0,0 +1,132 @@
+package errors_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleDatabaseErrorContext(t *testing.T) {
+	handler := errors.NewDatabaseErrorHandler()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	appErr := handler.HandleDatabaseError("GetTaskByID", fmt.Errorf("query failed: %w", ctx.Err()))
+	assert.Equal(t, "Request timeout", appErr.Message)
+	assert.ErrorIs(t, appErr.Err, context.DeadlineExceeded)
+
+	appErr = handler.HandleDatabaseError("GetTaskByID", context.Canceled)
+	assert.Equal(t, "Request cancelled", appErr.Message)
+
+	appErr = handler.HandleDatabaseError("GetTaskByID", sql.ErrNoRows)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	assert.Equal(t, errors.CodeTaskNotFound, appErr.Code)
+}
+
+func TestHandleDatabaseErrorNotFoundCode(t *testing.T) {
+	handler := errors.NewDatabaseErrorHandler()
+
+	tests := map[string]string{
+		"GetTaskByID":           errors.CodeTaskNotFound,
+		"GetAllTasksForUser":    errors.CodeTaskNotFound,
+		"UpdateCategory":        errors.CodeCategoryNotFound,
+		"GetAPIKeyByID":         errors.CodeAPIKeyNotFound,
+		"GetUserByEmail":        errors.CodeUserNotFound,
+		"GetRefreshTokenByHash": errors.CodeResourceNotFound,
+	}
+
+	for operation, code := range tests {
+		appErr := handler.HandleDatabaseError(operation, sql.ErrNoRows)
+		assert.Equal(t, code, appErr.Code, operation)
+		assert.Equal(t, "Resource not found", appErr.Message, operation)
+	}
+}
+
+func TestHandleDatabaseErrorPostgres(t *testing.T) {
+	handler := errors.NewDatabaseErrorHandler()
+
+	tests := []struct {
+		name       string
+		err        *pgconn.PgError
+		statusCode int
+		message    string
+		code       string
+	}{
+		{"DuplicateEmail", &pgconn.PgError{Code: "23505", ConstraintName: "unique_user_email"}, http.StatusConflict, "An account with this email already exists", errors.CodeEmailTaken},
+		{"DuplicateCategory", &pgconn.PgError{Code: "23505", ConstraintName: "unique_user_category"}, http.StatusConflict, "A category with this name already exists", errors.CodeCategoryNameTaken},
+		{"MissingCategory", &pgconn.PgError{Code: "23503", Detail: `Key (category_id)=(42) is not present in table "categories".`}, http.StatusUnprocessableEntity, "A referenced resource does not exist", errors.CodeInvalidReference},
+		{"CategoryInUse", &pgconn.PgError{Code: "23503", Detail: `Key (id)=(42) is still referenced from table "tasks".`}, http.StatusConflict, "Resource is still in use by other resources", errors.CodeResourceInUse},
+		{"ValueTooLong", &pgconn.PgError{Code: "22001"}, http.StatusRequestEntityTooLarge, "A value is longer than its field allows", errors.CodeValueTooLong},
+		{"InvalidUUID", &pgconn.PgError{Code: "22P02"}, http.StatusBadRequest, "Invalid input value", errors.CodeInvalidInput},
+		{"QueryCanceled", &pgconn.PgError{Code: "57014"}, http.StatusInternalServerError, "Request timeout", errors.CodeDatabaseTimeout},
+		{"ConnectionFailure", &pgconn.PgError{Code: "08006"}, http.StatusInternalServerError, "Service temporarily unavailable", errors.CodeServiceUnavailable},
+		{"Deadlock", &pgconn.PgError{Code: "40P01"}, http.StatusServiceUnavailable, "Request conflicted with a concurrent update, please retry", errors.CodeConcurrentUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := handler.HandleDatabaseError("CreateUser", fmt.Errorf("insert failed: %w", tt.err))
+			assert.Equal(t, tt.statusCode, appErr.StatusCode)
+			assert.Equal(t, tt.message, appErr.Message)
+			assert.Equal(t, tt.code, appErr.Code)
+		})
+	}
+
+	assert.True(t, errors.IsDeadlock(handler.HandleDatabaseError("UpdateTask", &pgconn.PgError{Code: "40001"})))
+	assert.False(t, errors.IsDeadlock(handler.HandleDatabaseError("UpdateTask", &pgconn.PgError{Code: "23505"})))
+}
+
+func TestHandleDatabaseErrorMySQL(t *testing.T) {
+	handler := errors.NewDatabaseErrorHandler()
+
+	tests := []struct {
+		name       string
+		err        *mysql.MySQLError
+		errorType  errors.ErrorType
+		statusCode int
+		code       string
+	}{
+		{"DuplicateCategory", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'categories.unique_user_category'"}, errors.ErrorTypeConflict, http.StatusConflict, errors.CodeCategoryNameTaken},
+		{"DuplicateDependency", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a-b' for key 'task_dependencies.PRIMARY'"}, errors.ErrorTypeConflict, http.StatusConflict, errors.CodeDependencyExists},
+		{"MissingCategory", &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails"}, errors.ErrorTypeInvalidReference, http.StatusUnprocessableEntity, errors.CodeInvalidReference},
+		{"CategoryInUse", &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: a foreign key constraint fails"}, errors.ErrorTypeConflict, http.StatusConflict, errors.CodeResourceInUse},
+		{"DataTooLong", &mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title' at row 1"}, errors.ErrorTypePayloadTooLarge, http.StatusRequestEntityTooLarge, errors.CodeValueTooLong},
+		{"Deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, errors.ErrorTypeRetryable, http.StatusServiceUnavailable, errors.CodeConcurrentUpdate},
+		{"LockWaitTimeout", &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, errors.ErrorTypeRetryable, http.StatusServiceUnavailable, errors.CodeConcurrentUpdate},
+		{"Unknown", &mysql.MySQLError{Number: 1054, Message: "Unknown column 'x' in 'field list'"}, errors.ErrorTypeDatabase, http.StatusInternalServerError, errors.CodeDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := handler.HandleDatabaseError("UpdateTask", fmt.Errorf("exec failed: %w", tt.err))
+			assert.Equal(t, tt.errorType, appErr.Type)
+			assert.Equal(t, tt.statusCode, appErr.StatusCode)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Equal(t, "UpdateTask", appErr.Operation)
+			assert.ErrorIs(t, appErr, tt.err, "the driver error stays wrapped")
+			assert.Contains(t, appErr.Error(), "UpdateTask: ")
+		})
+	}
+
+	assert.True(t, errors.IsDeadlock(handler.HandleDatabaseError("UpdateTask", &mysql.MySQLError{Number: 1213})))
+}
+
+func TestHandleDatabaseErrorConnection(t *testing.T) {
+	handler := errors.NewDatabaseErrorHandler()
+
+	appErr := handler.HandleDatabaseError("ListTasks", fmt.Errorf("query failed: %w", mysql.ErrInvalidConn))
+	assert.Equal(t, errors.CodeServiceUnavailable, appErr.Code)
+	assert.ErrorIs(t, appErr, mysql.ErrInvalidConn)
+
+	appErr = handler.HandleDatabaseError("ListTasks", fmt.Errorf("no connection to report"))
+	assert.Equal(t, errors.CodeDatabase, appErr.Code, "messages are not matched on")
+}
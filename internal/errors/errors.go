@@ -3,15 +3,26 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/models"
 )
 
 type ErrorType string
 
 const (
-	ErrorTypeDatabase   ErrorType = "DATABASE_ERROR"
-	ErrorTypeInternal   ErrorType = "INTERNAL_ERROR"
-	ErrorTypeNotFound   ErrorType = "NOT_FOUND"
-	ErrorTypeBadRequest ErrorType = "BAD_REQUEST"
+	ErrorTypeDatabase          ErrorType = "DATABASE_ERROR"
+	ErrorTypeInternal          ErrorType = "INTERNAL_ERROR"
+	ErrorTypeNotFound          ErrorType = "NOT_FOUND"
+	ErrorTypeBadRequest        ErrorType = "BAD_REQUEST"
+	ErrorTypeUnauthorized      ErrorType = "UNAUTHORIZED"
+	ErrorTypeForbidden         ErrorType = "FORBIDDEN"
+	ErrorTypeConflict          ErrorType = "CONFLICT"
+	ErrorTypeInvalidTransition ErrorType = "INVALID_STATUS_TRANSITION"
+	ErrorTypeTooManyRequests   ErrorType = "TOO_MANY_REQUESTS"
+	ErrorTypeInvalidReference  ErrorType = "INVALID_REFERENCE"
+	ErrorTypePayloadTooLarge   ErrorType = "PAYLOAD_TOO_LARGE"
+	ErrorTypeRetryable         ErrorType = "RETRYABLE"
 )
 
 type AppError struct {
@@ -21,6 +32,13 @@ type AppError struct {
 	Details    string    `json:"details,omitempty"`
 	StatusCode int       `json:"-"`
 	Err        error     `json:"-"`
+
+	// Fields lists the fields of the request that failed validation.
+	Fields []models.FieldError `json:"fields,omitempty"`
+
+	// Operation names the repository operation that failed, for errors
+	// returned by the database.
+	Operation string `json:"-"`
 }
 
 func (e *AppError) Error() string {
@@ -30,10 +48,22 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode replaces the code e was given by its constructor with a more
+// specific one from the catalog.
+func (e *AppError) WithCode(code string) *AppError {
+	e.Code = code
+	return e
+}
+
 func NewDatabaseError(message string, err error) *AppError {
 	return &AppError{
 		Type:       ErrorTypeDatabase,
 		Message:    message,
+		Code:       CodeDatabase,
 		StatusCode: http.StatusInternalServerError,
 		Err:        err,
 	}
@@ -43,6 +73,7 @@ func NewNotFoundError(message string, err error) *AppError {
 	return &AppError{
 		Type:       ErrorTypeNotFound,
 		Message:    message,
+		Code:       CodeResourceNotFound,
 		StatusCode: http.StatusNotFound,
 		Err:        err,
 	}
@@ -52,6 +83,7 @@ func NewInternalError(message string, err error) *AppError {
 	return &AppError{
 		Type:       ErrorTypeInternal,
 		Message:    message,
+		Code:       CodeInternal,
 		StatusCode: http.StatusInternalServerError,
 		Err:        err,
 	}
@@ -61,7 +93,116 @@ func NewBadRequestError(message string, err error) *AppError {
 	return &AppError{
 		Type:       ErrorTypeBadRequest,
 		Message:    message,
+		Code:       CodeBadRequest,
 		StatusCode: http.StatusBadRequest,
 		Err:        err,
 	}
 }
+
+func NewUnauthorizedError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnauthorized,
+		Message:    message,
+		Code:       CodeAuthenticationRequired,
+		StatusCode: http.StatusUnauthorized,
+		Err:        err,
+	}
+}
+
+func NewForbiddenError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeForbidden,
+		Message:    message,
+		Code:       CodeForbidden,
+		StatusCode: http.StatusForbidden,
+		Err:        err,
+	}
+}
+
+func NewConflictError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeConflict,
+		Message:    message,
+		Code:       CodeResourceConflict,
+		StatusCode: http.StatusConflict,
+		Err:        err,
+	}
+}
+
+// NewInvalidTransitionError reports a status change that the task workflow
+// does not allow from the task's current status.
+func NewInvalidTransitionError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeInvalidTransition,
+		Message:    message,
+		Code:       CodeInvalidStatusTransition,
+		StatusCode: http.StatusConflict,
+		Err:        err,
+	}
+}
+
+// NewTooManyRequestsError reports a client that has made more requests than
+// the rate limit allows.
+func NewTooManyRequestsError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeTooManyRequests,
+		Message:    message,
+		Code:       CodeRateLimited,
+		StatusCode: http.StatusTooManyRequests,
+		Err:        err,
+	}
+}
+
+// NewInvalidReferenceError reports a write naming a related resource, such
+// as a category, that does not exist.
+func NewInvalidReferenceError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeInvalidReference,
+		Message:    message,
+		Code:       CodeInvalidReference,
+		StatusCode: http.StatusUnprocessableEntity,
+		Err:        err,
+	}
+}
+
+// NewPayloadTooLargeError reports a request carrying more data than can be
+// stored.
+func NewPayloadTooLargeError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypePayloadTooLarge,
+		Message:    message,
+		Code:       CodePayloadTooLarge,
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Err:        err,
+	}
+}
+
+// NewRetryableError reports a failure that did not change anything and is
+// likely to succeed if the request is sent again.
+func NewRetryableError(message string, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeRetryable,
+		Message:    message,
+		Code:       CodeConcurrentUpdate,
+		StatusCode: http.StatusServiceUnavailable,
+		Err:        err,
+	}
+}
+
+// NewValidationError reports a request whose fields failed validation,
+// listing why each of them was rejected.
+func NewValidationError(message string, fields ...models.FieldError) *AppError {
+	reasons := make([]string, len(fields))
+	for i, field := range fields {
+		reasons[i] = field.Field + ": " + field.Message
+	}
+
+	return &AppError{
+		Type:       ErrorTypeBadRequest,
+		Message:    message,
+		Code:       CodeValidationFailed,
+		StatusCode: http.StatusBadRequest,
+		Err:        fmt.Errorf("invalid fields: %s", strings.Join(reasons, "; ")),
+		Fields:     fields,
+	}
+}
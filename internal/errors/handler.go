@@ -4,47 +4,108 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/requestid"
+	"github.com/kjj1998/task-management-system/internal/tracing"
 )
 
-func HandleError(w http.ResponseWriter, err error, logger *slog.Logger) {
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// HandleError writes err to w. Clients that accept application/problem+json
+// get RFC 7807 problem details; all others get the usual BaseResponse.
+func HandleError(w http.ResponseWriter, r *http.Request, err error, baseLogger *slog.Logger) {
+	logger := logger.FromContext(r.Context(), baseLogger)
 	var appErr *AppError
 
 	if !errors.As(err, &appErr) {
 		appErr = NewInternalError("An unexpected error occurred", err)
 	}
+	code := appErr.Code
+	if code == "" {
+		code = CodeInternal
+	}
 
+	if appErr.Operation != "" {
+		logger = logger.With(slog.String("operation", appErr.Operation))
+	}
 	if appErr.StatusCode >= 500 {
-		logger.Error("server error occurred",
-			slog.String("error", appErr.Err.Error()),
+		logger.ErrorContext(r.Context(), "server error occurred",
+			slog.String("error", appErr.Error()),
 			slog.String("details", appErr.Details),
 			slog.Int("status_code", appErr.StatusCode),
-			slog.String("error_code", appErr.Code),
+			slog.String("error_code", code),
 		)
 	} else {
-		logger.Warn("client error occurred",
-			slog.String("error", appErr.Err.Error()),
+		logger.WarnContext(r.Context(), "client error occurred",
+			slog.String("error", appErr.Error()),
 			slog.Int("status_code", appErr.StatusCode),
-			slog.String("error_code", appErr.Code),
+			slog.String("error_code", code),
 		)
 	}
 
-	errorInfo := &models.ErrorInfo{
-		Code:    appErr.Code,
-		Message: appErr.Message,
-		Details: appErr.Details,
+	var response any
+	contentType := "application/json"
+	if acceptsProblem(r) {
+		contentType = ProblemContentType
+		response = &models.ProblemDetails{
+			Type:      ProblemType(code),
+			Title:     Title(code),
+			Status:    appErr.StatusCode,
+			Detail:    appErr.Message,
+			Instance:  r.URL.Path,
+			Code:      code,
+			RequestID: requestid.FromContext(r.Context()),
+			TraceID:   tracing.TraceID(r.Context()),
+			Errors:    appErr.Fields,
+		}
+	} else {
+		errorResponse := models.NewErrorResponse(appErr.Message, &models.ErrorInfo{
+			Code:    code,
+			Message: appErr.Message,
+			Details: appErr.Details,
+			Fields:  appErr.Fields,
+		})
+		errorResponse.RequestID = requestid.FromContext(r.Context())
+		errorResponse.TraceID = tracing.TraceID(r.Context())
+		response = errorResponse
 	}
 
-	response := models.NewErrorResponse("An error occurred", errorInfo)
-
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	if appErr.Type == ErrorTypeRetryable {
+		w.Header().Set("Retry-After", "1")
+	}
 	w.WriteHeader(appErr.StatusCode)
 
 	if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
-		logger.Error("failed to encode error response", slog.String("encode_error",
+		logger.ErrorContext(r.Context(), "failed to encode error response", slog.String("encode_error",
 			encodeErr.Error()))
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
+
+// acceptsProblem reports whether the Accept header of r lists
+// application/problem+json without refusing it with q=0.
+func acceptsProblem(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mediaType != ProblemContentType {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+				continue
+			}
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handle(t *testing.T, accept string, err error) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodPost, "/tasks/42", nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	errors.HandleError(recorder, request, err, slog.New(slog.DiscardHandler))
+
+	return recorder
+}
+
+func TestHandleErrorBaseResponse(t *testing.T) {
+	recorder := handle(t, "", errors.NewBadRequestError("Task ID is required", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", recorder.Header().Get("Vary"))
+
+	var response models.BaseResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	assert.False(t, response.Success)
+	assert.Equal(t, "Task ID is required", response.Message)
+	assert.Equal(t, errors.CodeBadRequest, response.Error.Code)
+}
+
+func TestHandleErrorProblemDetails(t *testing.T) {
+	err := errors.NewValidationError("Task title is required",
+		models.FieldError{Field: "title", Message: "must not be empty"},
+	)
+	recorder := handle(t, "application/problem+json, application/json;q=0.5", err)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, errors.ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem models.ProblemDetails
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+	assert.Equal(t, errors.ProblemType(errors.CodeValidationFailed), problem.Type)
+	assert.Equal(t, "Validation failed", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "Task title is required", problem.Detail)
+	assert.Equal(t, "/tasks/42", problem.Instance)
+	assert.Equal(t, errors.CodeValidationFailed, problem.Code)
+	assert.Equal(t, []models.FieldError{{Field: "title", Message: "must not be empty"}}, problem.Errors)
+}
+
+func TestHandleErrorNegotiation(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/json", "application/json"},
+		{"application/problem+json", errors.ProblemContentType},
+		{"application/problem+json; q=0", "application/json"},
+		{"text/html, application/problem+json;q=0.9", errors.ProblemContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			recorder := handle(t, tt.accept, errors.NewNotFoundError("Resource not found", nil))
+			assert.Equal(t, tt.contentType, recorder.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestHandleErrorUnexpected(t *testing.T) {
+	recorder := handle(t, errors.ProblemContentType, fmt.Errorf("boom"))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	var problem models.ProblemDetails
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+	assert.Equal(t, errors.CodeInternal, problem.Code)
+	assert.Equal(t, "An unexpected error occurred", problem.Detail)
+	assert.NotContains(t, problem.Detail, "boom")
+}
+
+func TestHandleErrorRetryable(t *testing.T) {
+	recorder := handle(t, "", errors.NewRetryableError("Request conflicted with a concurrent update, please retry", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/validation"
+)
+
+type AdminHandlers struct {
+	logLevel *slog.LevelVar
+	logger   *slog.Logger
+}
+
+func NewAdminHandler(logLevel *slog.LevelVar, logger *slog.Logger) *AdminHandlers {
+	return &AdminHandlers{logLevel: logLevel, logger: logger}
+}
+
+type logLevelBody struct {
+	Level *slog.Level `json:"level"`
+}
+
+// HandleLogLevel reports the level the server logs at, and changes it on
+// PUT with a body such as {"level": "debug"}. The change lasts until the
+// server restarts.
+func (h *AdminHandlers) HandleLogLevel(w http.ResponseWriter, r *http.Request) {
+	message := "Log level retrieved successfully"
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		body, err := readRequestBody(w, r, h.logger)
+		if err != nil {
+			errors.HandleError(w, r, err, h.logger)
+			return
+		}
+
+		var request logLevelBody
+		err = validation.Decode(body, &request)
+		if err != nil {
+			err = fmt.Errorf("invalid log level request: %w", err)
+		} else if request.Level == nil {
+			err = fmt.Errorf("log level request has no level")
+		}
+		if err != nil {
+			validationError := errors.NewBadRequestError("Level must be debug, info, warn or error", err).WithCode(errors.CodeValidationFailed)
+			errors.HandleError(w, r, validationError, h.logger)
+			return
+		}
+
+		previous := h.logLevel.Level()
+		h.logLevel.Set(*request.Level)
+		message = "Log level updated successfully"
+		logger.FromContext(r.Context(), h.logger).WarnContext(r.Context(), "log level changed",
+			slog.String("from", previous.String()),
+			slog.String("to", request.Level.String()),
+		)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	level := h.logLevel.Level()
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse(message, logLevelBody{Level: &level})
+	err := writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
@@ -0,0 +1,40 @@
+package handlers_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/handlers"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleLogLevel(t *testing.T) {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelInfo)
+	admin := handlers.NewAdminHandler(level, logger.NewLogger("test"))
+	ctx := context.Background()
+
+	recorder, resp := serve(t, admin.HandleLogLevel, ctx, http.MethodGet, "/admin/log-level", "", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"level":"INFO"}`, string(resp.Data))
+
+	recorder, resp = serve(t, admin.HandleLogLevel, ctx, http.MethodPut, "/admin/log-level", "application/json", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"level":"DEBUG"}`, string(resp.Data))
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	recorder, _ = serve(t, admin.HandleLogLevel, ctx, http.MethodPut, "/admin/log-level", "application/json", `{"level":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "an invalid level leaves the level unchanged")
+
+	recorder, _ = serve(t, admin.HandleLogLevel, ctx, http.MethodPut, "/admin/log-level", "application/json", `{}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "a missing level leaves the level unchanged")
+
+	recorder, _ = serve(t, admin.HandleLogLevel, ctx, http.MethodPut, "/admin/log-level", "application/json", `{"level":"error","for":"1h"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, level.Level(), "unknown fields are rejected")
+}
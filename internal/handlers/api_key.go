@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/services"
+)
+
+type APIKeyHandlers struct {
+	apiKeyService *services.APIKeyService
+	logger        *slog.Logger
+}
+
+func NewAPIKeysHandler(apiKeyService *services.APIKeyService, logger *slog.Logger) *APIKeyHandlers {
+	return &APIKeyHandlers{apiKeyService: apiKeyService, logger: logger}
+}
+
+func (h *APIKeyHandlers) HandleSingleAPIKey(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		h.RevokeAPIKey(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *APIKeyHandlers) HandleAPIKeys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAPIKeys(w, r)
+	case http.MethodPost:
+		h.CreateAPIKey(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *APIKeyHandlers) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeyService.ListAPIKeys(r.Context())
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("API keys retrieved successfully", keys)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *APIKeyHandlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request services.CreateAPIKeyRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	createdKey, err := h.apiKeyService.CreateAPIKey(r.Context(), request)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Location", fmt.Sprintf("/api-keys/%s", createdKey.ID))
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.NewSuccessResponse("API key created successfully, store the key now as it will not be shown again", createdKey)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *APIKeyHandlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID, subresource := splitResourcePath(r.URL.Path, "api-keys")
+	if subresource != "" {
+		http.NotFound(w, r)
+		return
+	}
+	if keyID == "" {
+		validationError := errors.NewBadRequestError("API key ID is required", fmt.Errorf("missing api key id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	err := h.apiKeyService.RevokeAPIKey(r.Context(), keyID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("API key revoked successfully", nil)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/services"
+)
+
+type AuthHandlers struct {
+	authService *services.AuthService
+	logger      *slog.Logger
+}
+
+func NewAuthHandler(authService *services.AuthService, logger *slog.Logger) *AuthHandlers {
+	return &AuthHandlers{authService: authService, logger: logger}
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request services.RegisterRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	user, err := h.authService.Register(r.Context(), request)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.NewSuccessResponse("User registered successfully", user)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request loginRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	tokens, err := h.authService.Login(r.Context(), request.Email, request.Password)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	h.writeTokens(w, r, "Login successful", tokens)
+}
+
+func (h *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request refreshTokenRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), request.RefreshToken)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	h.writeTokens(w, r, "Token refreshed successfully", tokens)
+}
+
+func (h *AuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request refreshTokenRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), request.RefreshToken); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Logged out successfully", nil)
+	err := writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *AuthHandlers) writeTokens(w http.ResponseWriter, r *http.Request, message string, tokens *services.TokenPair) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	response := models.NewSuccessResponse(message, tokens)
+	err := writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
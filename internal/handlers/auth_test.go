@@ -0,0 +1,40 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/handlers"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/services"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthRejectsUnknownFields(t *testing.T) {
+	logger := logger.NewLogger("test")
+	taskStore := store.NewMemoryTaskStore(memory.NewDatabase(), errors.NewDatabaseErrorHandler(), logger)
+	tokenManager := auth.NewTokenManager("test-secret", "test", 15*time.Minute)
+	authHandler := handlers.NewAuthHandler(services.NewAuthService(taskStore, tokenManager, time.Hour, logger), logger)
+	ctx := context.Background()
+
+	recorder, resp := serve(t, authHandler.Register, ctx, http.MethodPost, "/auth/register", "application/json",
+		`{"email":"john@email.com","password":"correct-horse-battery","firstName":"John","lastName":"Doe","role":"admin"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, errors.CodeValidationFailed, resp.Error.Code)
+	}
+
+	recorder, _ = serve(t, authHandler.Login, ctx, http.MethodPost, "/auth/login", "application/json",
+		`{"email":"john@email.com","password":"correct-horse-battery","remember":true}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, _ = serve(t, authHandler.Refresh, ctx, http.MethodPost, "/auth/refresh", "application/json",
+		`{"refreshToken":"token","accessToken":"token"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
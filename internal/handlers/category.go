@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/services"
+)
+
+type CategoryHandlers struct {
+	categoryService *services.CategoryService
+	taskService     *services.TaskService
+	logger          *slog.Logger
+}
+
+func NewCategoriesHandler(categoryService *services.CategoryService, taskService *services.TaskService, logger *slog.Logger) *CategoryHandlers {
+	return &CategoryHandlers{categoryService: categoryService, taskService: taskService, logger: logger}
+}
+
+func extractCategoryID(path string) string {
+	categoryID, _ := splitResourcePath(path, "categories")
+
+	return categoryID
+}
+
+func (h *CategoryHandlers) HandleSingleCategory(w http.ResponseWriter, r *http.Request) {
+	_, subresource := splitResourcePath(r.URL.Path, "categories")
+	switch subresource {
+	case "":
+	case "tasks":
+		h.HandleCategoryTasks(w, r)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.GetCategoryByID(w, r)
+	case http.MethodPut:
+		h.UpdateCategory(w, r)
+	case http.MethodDelete:
+		h.DeleteCategory(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandlers) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetCategories(w, r)
+	case http.MethodPost:
+		h.CreateCategory(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandlers) HandleCategoryTasks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetCategoryTasks(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategoryHandlers) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
+	categoryID := extractCategoryID(r.URL.Path)
+	if categoryID == "" {
+		validationError := errors.NewBadRequestError("Category ID is required", fmt.Errorf("missing category id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(r.Context(), categoryID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Category retrieved successfully", category)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *CategoryHandlers) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categoryService.ListCategories(r.Context())
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Categories retrieved successfully", categories)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *CategoryHandlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var request services.CategoryRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	createdCategory, err := h.categoryService.CreateCategory(r.Context(), request)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/categories/%s", createdCategory.ID))
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.NewSuccessResponse("Category created successfully", createdCategory)
+
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *CategoryHandlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := extractCategoryID(r.URL.Path)
+	if categoryID == "" {
+		validationError := errors.NewBadRequestError("Category ID is required", fmt.Errorf("missing category id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	var request services.CategoryRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	updatedCategory, err := h.categoryService.UpdateCategory(r.Context(), categoryID, request)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Category updated successfully", updatedCategory)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *CategoryHandlers) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID := extractCategoryID(r.URL.Path)
+	if categoryID == "" {
+		validationError := errors.NewBadRequestError("Category ID is required", fmt.Errorf("missing category id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	err := h.categoryService.DeleteCategory(r.Context(), categoryID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Category deleted successfully", nil)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+// GetCategoryTasks lists the tasks in a category. It accepts the same
+// filtering, sorting and pagination parameters as GET /tasks.
+func (h *CategoryHandlers) GetCategoryTasks(w http.ResponseWriter, r *http.Request) {
+	categoryID := extractCategoryID(r.URL.Path)
+	if categoryID == "" {
+		validationError := errors.NewBadRequestError("Category ID is required", fmt.Errorf("missing category id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(r.Context(), categoryID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	query, err := parseTaskQuery(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+	query.Filter.CategoryIDs = []string{category.ID}
+
+	page, err := h.taskService.ListTasks(r.Context(), query)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewPaginatedResponse("Tasks retrieved successfully", page.Tasks, newPageMeta(query, page))
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
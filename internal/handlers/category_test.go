@@ -0,0 +1,69 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	var categoryID string
+
+	t.Run("CreateCategory", func(t *testing.T) {
+		recorder, body := serve(t, env.category.HandleCategories, env.owner, http.MethodPost, "/categories", "application/json", `{"name":"routine"}`)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		category := decodeData[models.DBCategory](t, body)
+		assert.Equal(t, "#007bff", category.Color)
+		assert.Equal(t, "/categories/"+category.ID, recorder.Header().Get("Location"))
+		categoryID = category.ID
+	})
+
+	t.Run("CreateDuplicateCategory", func(t *testing.T) {
+		recorder, _ := serve(t, env.category.HandleCategories, env.owner, http.MethodPost, "/categories", "application/json", `{"name":"Routine"}`)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("CreateCategoryWithInvalidColor", func(t *testing.T) {
+		recorder, _ := serve(t, env.category.HandleCategories, env.owner, http.MethodPost, "/categories", "application/json", `{"name":"errands","color":"blue"}`)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("ListCategories", func(t *testing.T) {
+		recorder, body := serve(t, env.category.HandleCategories, env.owner, http.MethodGet, "/categories", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, decodeData[[]models.DBCategory](t, body), 1)
+
+		recorder, body = serve(t, env.category.HandleCategories, env.intruder, http.MethodGet, "/categories", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, decodeData[[]models.DBCategory](t, body))
+	})
+
+	t.Run("GetCategoryTasks", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"title":"Sweep Floor","priority":"medium","categoryID":"`+categoryID+`"}`)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder, body := serve(t, env.category.HandleSingleCategory, env.owner, http.MethodGet, "/categories/"+categoryID+"/tasks", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, decodeData[[]models.DBTask](t, body), 1)
+
+		recorder, _ = serve(t, env.category.HandleSingleCategory, env.intruder, http.MethodGet, "/categories/"+categoryID+"/tasks", "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("DeleteCategoryKeepsTasks", func(t *testing.T) {
+		recorder, _ := serve(t, env.category.HandleSingleCategory, env.owner, http.MethodDelete, "/categories/"+categoryID, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		tasks := decodeData[[]models.DBTask](t, body)
+		require.Len(t, tasks, 1)
+		assert.Empty(t, tasks[0].CategoryID)
+	})
+}
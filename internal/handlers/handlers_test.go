@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/handlers"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/services"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv struct {
+	store    *store.DatabaseTaskStore
+	tasks    *handlers.TaskHandlers
+	category *handlers.CategoryHandlers
+	owner    context.Context
+	intruder context.Context
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	logger := logger.NewLogger("test")
+	taskStore := store.NewMemoryTaskStore(memory.NewDatabase(), errors.NewDatabaseErrorHandler(), logger)
+	taskService := services.NewTaskService(taskStore, services.NewTaskWorkflow(services.DefaultWorkflowConfig()))
+	categoryService := services.NewCategoryService(taskStore)
+
+	identity := func(email string) context.Context {
+		user, err := taskStore.UserRepository.Create(context.Background(), &models.DBUser{Email: email, FirstName: "Test", LastName: "User"})
+		require.NoError(t, err)
+		return auth.WithIdentity(context.Background(), &auth.Identity{UserID: user.ID, Email: email})
+	}
+
+	return &testEnv{
+		store:    taskStore,
+		tasks:    handlers.NewTasksHandler(taskService, logger),
+		category: handlers.NewCategoriesHandler(categoryService, taskService, logger),
+		owner:    identity("john@email.com"),
+		intruder: identity("mallory@email.com"),
+	}
+}
+
+type response struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    json.RawMessage   `json:"data"`
+	Meta    *models.Meta      `json:"meta"`
+	Error   *models.ErrorInfo `json:"error"`
+}
+
+// serve sends a request as the caller in ctx and decodes the response body.
+func serve(t *testing.T, handler http.HandlerFunc, ctx context.Context, method, target, contentType, body string) (*httptest.ResponseRecorder, response) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	request := httptest.NewRequest(method, target, reader).WithContext(ctx)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	var decoded response
+	if strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &decoded))
+	}
+	return recorder, decoded
+}
+
+func decodeData[T any](t *testing.T, r response) T {
+	t.Helper()
+
+	var data T
+	require.NoError(t, json.Unmarshal(r.Data, &data))
+	return data
+}
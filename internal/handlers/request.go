@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/validation"
+)
+
+// maxRequestBodyBytes is the largest request body read. No request needs
+// more than a fraction of it.
+const maxRequestBodyBytes = 1 << 20
+
+func readRequestBody(w http.ResponseWriter, r *http.Request, logger *slog.Logger) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			return nil, errors.NewPayloadTooLargeError(fmt.Sprintf("Request body must be at most %d bytes", maxBytesErr.Limit), err)
+		}
+		return nil, errors.NewBadRequestError("Error reading request body", err).WithCode(errors.CodeMalformedBody)
+	}
+	defer func() {
+		if closeErr := r.Body.Close(); closeErr != nil {
+			logger.Warn("failed to close request body", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	return body, nil
+}
+
+// decodeRequestBody reads the request body and decodes it strictly into dst.
+func decodeRequestBody(w http.ResponseWriter, r *http.Request, dst any, logger *slog.Logger) error {
+	body, err := readRequestBody(w, r, logger)
+	if err != nil {
+		return err
+	}
+
+	return validation.Decode(body, dst)
+}
+
+// splitResourcePath splits /{collection}/{id}/{subresource} into the
+// resource ID and the optional subresource name.
+func splitResourcePath(path string, collection string) (string, string) {
+	rest := strings.TrimPrefix(path, "/"+collection+"/")
+	rest = strings.TrimSuffix(rest, "/")
+	id, subresource, _ := strings.Cut(rest, "/")
+
+	return id, subresource
+}
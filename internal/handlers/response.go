@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/requestid"
+	"github.com/kjj1998/task-management-system/internal/tracing"
+)
+
+// writeResponse encodes response as the body, tagged with the IDs of the
+// request and of its trace.
+func writeResponse(w http.ResponseWriter, r *http.Request, response *models.BaseResponse) error {
+	response.RequestID = requestid.FromContext(r.Context())
+	response.TraceID = tracing.TraceID(r.Context())
+
+	return json.NewEncoder(w).Encode(response)
+}
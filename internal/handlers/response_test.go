@@ -0,0 +1,74 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/handlers"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+func TestResponseTraceID(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+		Exporter:    config.TracingExporterNone,
+		ServiceName: "test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { shutdown(context.Background()) })
+
+	admin := handlers.NewAdminHandler(new(slog.LevelVar), logger.NewLogger("test"))
+	handler := otelhttp.NewHandler(http.HandlerFunc(admin.HandleLogLevel), "test")
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	send := func(method, accept, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+		if accept != "" {
+			request.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		recorder := send(http.MethodGet, "", "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var body models.BaseResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, traceID, body.TraceID)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		recorder := send(http.MethodPut, "", `{"level":"loud"}`)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body models.BaseResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, traceID, body.TraceID)
+	})
+
+	t.Run("ProblemDetails", func(t *testing.T) {
+		recorder := send(http.MethodPut, errors.ProblemContentType, `{"level":"loud"}`)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body models.ProblemDetails
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.Equal(t, traceID, body.TraceID)
+	})
+}
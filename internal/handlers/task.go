@@ -1,12 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kjj1998/task-management-system/internal/errors"
 	"github.com/kjj1998/task-management-system/internal/models"
@@ -23,16 +22,42 @@ func NewTasksHandler(taskService *services.TaskService, logger *slog.Logger) *Ta
 }
 
 func extractTaskID(path string) string {
-	taskID := strings.TrimPrefix(path, "/tasks/")
-	taskID = strings.TrimSuffix(taskID, "/")
+	taskID, _ := splitResourcePath(path, "tasks")
 
 	return taskID
 }
 
 func (h *TaskHandlers) HandleSingleTask(w http.ResponseWriter, r *http.Request) {
+	_, subresource := splitResourcePath(r.URL.Path, "tasks")
+	switch subresource {
+	case "":
+	case "transitions":
+		h.HandleTaskTransitions(w, r)
+		return
+	case "subtree":
+		h.HandleTaskSubtree(w, r)
+		return
+	case "dependencies":
+		h.HandleTaskDependencies(w, r)
+		return
+	default:
+		if strings.HasPrefix(subresource, "dependencies/") {
+			h.HandleTaskDependency(w, r)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.GetTaskByID(w, r)
+	case http.MethodPut:
+		h.UpdateTask(w, r)
+	case http.MethodPatch:
+		h.PatchTask(w, r)
+	case http.MethodDelete:
+		h.DeleteTask(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
@@ -41,22 +66,22 @@ func (h *TaskHandlers) HandleSingleTask(w http.ResponseWriter, r *http.Request)
 func (h *TaskHandlers) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 	taskID := extractTaskID(r.URL.Path)
 	if taskID == "" {
-		validationError := errors.NewBadRequestError("Task ID is required", nil)
-		errors.HandleError(w, validationError, h.logger)
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
 		return
 	}
 
-	task, err := h.taskService.GetTask(w, taskID)
+	task, err := h.taskService.GetTask(r.Context(), taskID)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	response := models.NewSuccessResponse("Task retrieved successfully", task)
-	err = json.NewEncoder(w).Encode(response)
+	err = writeResponse(w, r, response)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 	}
 }
 
@@ -72,62 +97,350 @@ func (h *TaskHandlers) HandleTasks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TaskHandlers) GetTasks(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("userId")
-	if userID == "" {
-		validationError := errors.NewBadRequestError("User ID parameter is required", nil)
-		errors.HandleError(w, validationError, h.logger)
+	query, err := parseTaskQuery(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
 		return
 	}
 
-	tasks, err := h.taskService.GetTasksByUserID(userID)
+	page, err := h.taskService.ListTasks(r.Context(), query)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	response := models.NewSuccessResponse("Tasks retrieved successfully", tasks)
-	err = json.NewEncoder(w).Encode(response)
+	response := models.NewPaginatedResponse("Tasks retrieved successfully", page.Tasks, newPageMeta(query, page))
+	err = writeResponse(w, r, response)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 	}
 }
 
 func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+	var request services.TaskRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	createdTask, err := h.taskService.CreateTask(r.Context(), request)
 	if err != nil {
-		parsingError := errors.NewBadRequestError("Error reading request body", nil)
-		errors.HandleError(w, parsingError, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 		return
 	}
-	defer func() {
-		if closeErr := r.Body.Close(); closeErr != nil {
-			h.logger.Warn("failed to close request body", slog.String("error", closeErr.Error()))
-		}
-	}()
 
-	var task models.DBTask
-	err = json.Unmarshal(body, &task)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%s", createdTask.ID))
+	w.WriteHeader(http.StatusCreated)
+
+	response := models.NewSuccessResponse("Task created successfully", createdTask)
+
+	err = writeResponse(w, r, response)
 	if err != nil {
-		parsingError := errors.NewBadRequestError("Error parsing json body", nil)
-		errors.HandleError(w, parsingError, h.logger)
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
 		return
 	}
 
-	createdTask, err := h.taskService.CreateTask(task)
+	var request services.TaskRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	updatedTask, err := h.taskService.UpdateTask(r.Context(), taskID, request)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Location", fmt.Sprintf("/tasks/%s", createdTask.ID))
+	response := models.NewSuccessResponse("Task updated successfully", updatedTask)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+// PatchTask accepts a JSON Merge Patch (RFC 7396) document. Both
+// application/merge-patch+json and plain application/json are accepted.
+func (h *TaskHandlers) PatchTask(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, "application/json") {
+		unsupportedError := errors.NewBadRequestError("Content-Type must be application/merge-patch+json", fmt.Errorf("unsupported content type %q", contentType)).WithCode(errors.CodeUnsupportedMediaType)
+		unsupportedError.StatusCode = http.StatusUnsupportedMediaType
+		errors.HandleError(w, r, unsupportedError, h.logger)
+		return
+	}
+
+	body, err := readRequestBody(w, r, h.logger)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	patchedTask, err := h.taskService.PatchTask(r.Context(), taskID, body)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task updated successfully", patchedTask)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	policy := services.SubtaskPolicy(r.URL.Query().Get("subtasks"))
+	switch policy {
+	case "":
+		policy = services.DeleteSubtasks
+	case services.DeleteSubtasks, services.PromoteSubtasks:
+	default:
+		validationError := errors.NewBadRequestError(
+			fmt.Sprintf("Invalid subtasks, expected %s or %s", services.DeleteSubtasks, services.PromoteSubtasks),
+			fmt.Errorf("invalid subtask policy %q", policy),
+		).WithCode(errors.CodeInvalidQueryParameter)
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	err := h.taskService.DeleteTask(r.Context(), taskID, policy)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task deleted successfully", nil)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) HandleTaskSubtree(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTaskSubtree(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTaskSubtree returns the task with all of its subtasks nested under it.
+func (h *TaskHandlers) GetTaskSubtree(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	tree, err := h.taskService.GetTaskSubtree(r.Context(), taskID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task subtree retrieved successfully", tree)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) HandleTaskTransitions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.TransitionTask(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type transitionRequest struct {
+	Action services.TaskAction `json:"action"`
+}
+
+// TransitionTask performs an explicit workflow action such as
+// {"action": "reopen"} on a task.
+func (h *TaskHandlers) TransitionTask(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	var request transitionRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	task, err := h.taskService.TransitionTask(r.Context(), taskID, request.Action)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task status updated successfully", task)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) HandleTaskDependencies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTaskDependencies(w, r)
+	case http.MethodPost:
+		h.AddTaskDependency(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TaskHandlers) HandleTaskDependency(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		h.RemoveTaskDependency(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTaskDependencies returns the tasks the task is blocked by and the tasks
+// it blocks.
+func (h *TaskHandlers) GetTaskDependencies(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	dependencies, err := h.taskService.GetTaskDependencies(r.Context(), taskID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task dependencies retrieved successfully", dependencies)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+// AddTaskDependency marks the task as blocked by the task in a body such as
+// {"blockedBy": "<task id>"}.
+func (h *TaskHandlers) AddTaskDependency(w http.ResponseWriter, r *http.Request) {
+	taskID := extractTaskID(r.URL.Path)
+	if taskID == "" {
+		validationError := errors.NewBadRequestError("Task ID is required", fmt.Errorf("missing task id"))
+		errors.HandleError(w, r, validationError, h.logger)
+		return
+	}
+
+	var request services.DependencyRequest
+	if err := decodeRequestBody(w, r, &request, h.logger); err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	dependency, err := h.taskService.AddDependency(r.Context(), taskID, request)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%s/dependencies/%s", dependency.TaskID, dependency.BlockedByID))
 	w.WriteHeader(http.StatusCreated)
 
-	response := models.NewSuccessResponse("Task created successfully", createdTask)
+	response := models.NewSuccessResponse("Task dependency created successfully", dependency)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+// RemoveTaskDependency handles DELETE /tasks/{id}/dependencies/{blockedByID}.
+func (h *TaskHandlers) RemoveTaskDependency(w http.ResponseWriter, r *http.Request) {
+	taskID, subresource := splitResourcePath(r.URL.Path, "tasks")
+	blockedByID := strings.TrimPrefix(subresource, "dependencies/")
+	if taskID == "" || blockedByID == "" || strings.Contains(blockedByID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	err := h.taskService.RemoveDependency(r.Context(), taskID, blockedByID)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
 
-	err = json.NewEncoder(w).Encode(response)
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task dependency deleted successfully", nil)
+	err = writeResponse(w, r, response)
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+	}
+}
+
+func (h *TaskHandlers) HandleTaskOrder(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetTaskOrder(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTaskOrder returns the caller's open tasks ordered so that every task
+// comes after the tasks it is blocked by.
+func (h *TaskHandlers) GetTaskOrder(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.taskService.OrderTasks(r.Context())
+	if err != nil {
+		errors.HandleError(w, r, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.NewSuccessResponse("Task order retrieved successfully", tasks)
+	err = writeResponse(w, r, response)
 	if err != nil {
-		errors.HandleError(w, err, h.logger)
+		errors.HandleError(w, r, err, h.logger)
 	}
 }
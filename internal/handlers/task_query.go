@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+const (
+	defaultTasksPerPage = 50
+	maxTasksPerPage     = 200
+)
+
+var defaultTaskSort = []models.TaskSort{{Field: models.SortByCreatedAt, Descending: true}}
+
+// parseTaskQuery reads the filtering, sorting and pagination parameters of
+// GET /tasks:
+//
+//	status, priority, categoryId   comma separated or repeated values
+//	dueAfter, dueBefore            RFC 3339 timestamps or YYYY-MM-DD dates
+//	createdAfter, createdBefore
+//	updatedAfter, updatedBefore
+//	overdue                        true to only return overdue open tasks
+//	ready                          true to only return open tasks with no open blockers
+//	sort                           e.g. -dueDate,priority (- for descending)
+//	page, perPage                  offset pagination
+//	cursor                         keyset pagination, from meta.next_cursor
+func parseTaskQuery(values url.Values, now time.Time) (models.TaskQuery, error) {
+	query := models.TaskQuery{}
+
+	for _, status := range splitValues(values["status"]) {
+		taskStatus := models.TaskStatus(status)
+		if !taskStatus.IsValid() {
+			return query, errors.NewBadRequestError(fmt.Sprintf("Invalid status filter %q", status), fmt.Errorf("invalid status %q", status)).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		query.Filter.Statuses = append(query.Filter.Statuses, taskStatus)
+	}
+
+	for _, priority := range splitValues(values["priority"]) {
+		taskPriority := models.TaskPriority(priority)
+		if !taskPriority.IsValid() {
+			return query, errors.NewBadRequestError(fmt.Sprintf("Invalid priority filter %q", priority), fmt.Errorf("invalid priority %q", priority)).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		query.Filter.Priorities = append(query.Filter.Priorities, taskPriority)
+	}
+
+	query.Filter.CategoryIDs = splitValues(values["categoryId"])
+
+	timeParams := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"dueAfter", &query.Filter.DueAfter},
+		{"dueBefore", &query.Filter.DueBefore},
+		{"createdAfter", &query.Filter.CreatedAfter},
+		{"createdBefore", &query.Filter.CreatedBefore},
+		{"updatedAfter", &query.Filter.UpdatedAfter},
+		{"updatedBefore", &query.Filter.UpdatedBefore},
+	}
+	for _, param := range timeParams {
+		value := values.Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			return query, errors.NewBadRequestError(fmt.Sprintf("Invalid %s, expected an RFC 3339 timestamp or YYYY-MM-DD date", param.name), err).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		*param.target = &parsed
+	}
+
+	if overdue := values.Get("overdue"); overdue != "" {
+		isOverdue, err := strconv.ParseBool(overdue)
+		if err != nil {
+			return query, errors.NewBadRequestError("Invalid overdue, expected true or false", err).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		if isOverdue {
+			query.Filter.OverdueAt = &now
+		}
+	}
+
+	if ready := values.Get("ready"); ready != "" {
+		isReady, err := strconv.ParseBool(ready)
+		if err != nil {
+			return query, errors.NewBadRequestError("Invalid ready, expected true or false", err).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		query.Filter.Ready = isReady
+	}
+
+	sort, err := parseSortParam(values.Get("sort"))
+	if err != nil {
+		return query, err
+	}
+	query.Sort = sort
+
+	perPage, err := parsePositiveIntParam(values, "perPage", defaultTasksPerPage)
+	if err != nil {
+		return query, err
+	}
+	if perPage > maxTasksPerPage {
+		perPage = maxTasksPerPage
+	}
+	query.Limit = perPage
+
+	if cursor := values.Get("cursor"); cursor != "" {
+		decoded, err := models.DecodeTaskCursor(cursor, query.Sort)
+		if err != nil {
+			return query, errors.NewBadRequestError("Invalid cursor", err).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		query.Cursor = decoded
+		return query, nil
+	}
+
+	page, err := parsePositiveIntParam(values, "page", 1)
+	if err != nil {
+		return query, err
+	}
+	if page-1 > math.MaxInt/perPage {
+		return query, errors.NewBadRequestError("Invalid page, too large for perPage", fmt.Errorf("page %d with perPage %d overflows the offset", page, perPage)).WithCode(errors.CodeInvalidQueryParameter)
+	}
+	query.Offset = (page - 1) * perPage
+
+	return query, nil
+}
+
+func parseSortParam(value string) ([]models.TaskSort, error) {
+	if value == "" {
+		return defaultTaskSort, nil
+	}
+
+	seen := make(map[models.TaskSortField]bool)
+	sort := make([]models.TaskSort, 0)
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		descending := strings.HasPrefix(key, "-")
+		field := models.TaskSortField(strings.TrimPrefix(key, "-"))
+		if !field.IsValid() {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Invalid sort field %q", field), fmt.Errorf("invalid sort field %q", field)).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		if seen[field] {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Duplicate sort field %q", field), fmt.Errorf("duplicate sort field %q", field)).WithCode(errors.CodeInvalidQueryParameter)
+		}
+		seen[field] = true
+		sort = append(sort, models.TaskSort{Field: field, Descending: descending})
+	}
+
+	return sort, nil
+}
+
+func parsePositiveIntParam(values url.Values, name string, defaultValue int) (int, error) {
+	value := values.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return 0, errors.NewBadRequestError(fmt.Sprintf("Invalid %s, expected a positive integer", name), fmt.Errorf("invalid %s %q", name, value)).WithCode(errors.CodeInvalidQueryParameter)
+	}
+
+	return parsed, nil
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// splitValues flattens repeated and comma separated query values.
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+func newPageMeta(query models.TaskQuery, page *models.TaskPage) *models.Meta {
+	meta := &models.Meta{
+		PerPage:    query.Limit,
+		Total:      page.Total,
+		TotalPages: (page.Total + query.Limit - 1) / query.Limit,
+		NextCursor: page.NextCursor,
+	}
+	if query.Cursor == nil {
+		meta.Page = query.Offset/query.Limit + 1
+	}
+
+	return meta
+}
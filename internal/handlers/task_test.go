@@ -0,0 +1,360 @@
+package handlers_test
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandlers(t *testing.T) {
+	env := newTestEnv(t)
+	var taskID string
+
+	t.Run("CreateTask", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"title":"Sweep Floor","description":"Sweep the floor of my room","priority":"medium"}`)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		taskID = decodeData[models.DBTask](t, body).ID
+		assert.NotEmpty(t, taskID)
+	})
+
+	t.Run("CreateMalformedTask", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json", `{"title":`)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("CreateInvalidTask", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"title":"","priority":"urgent","dueDate":"2000-01-01T00:00:00Z"}`)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.NotNil(t, body.Error)
+		assert.Equal(t, errors.CodeValidationFailed, body.Error.Code)
+		assert.Equal(t, []models.FieldError{
+			{Field: "title", Message: "is required"},
+			{Field: "priority", Message: "must be one of low, medium, high"},
+			{Field: "dueDate", Message: "must be in the future"},
+		}, body.Error.Fields)
+
+		recorder, body = serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"title":"`+strings.Repeat("a", 201)+`"}`)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, []models.FieldError{{Field: "title", Message: "must be at most 200 characters"}}, body.Error.Fields)
+	})
+
+	t.Run("CreateTaskWithServerFields", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"id":"42","title":"Sweep Floor","completedAt":"2020-01-01T00:00:00Z"}`)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, []models.FieldError{{Field: "id", Message: "is not a known field"}}, body.Error.Fields)
+	})
+
+	t.Run("CreateOversizedTask", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json",
+			`{"title":"Sweep Floor","description":"`+strings.Repeat("a", 1<<20)+`"}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	})
+
+	t.Run("GetTask", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+taskID, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		task := decodeData[models.DBTask](t, body)
+		assert.Equal(t, "Sweep Floor", task.Title)
+		assert.Equal(t, models.Pending, task.Status)
+	})
+
+	t.Run("ForeignTaskIsNotFound", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.intruder, http.MethodGet, "/tasks/"+taskID, "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.intruder, http.MethodDelete, "/tasks/"+taskID, "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("ListTasks", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks?status=pending&sort=-priority", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, decodeData[[]models.DBTask](t, body), 1)
+		require.NotNil(t, body.Meta)
+		assert.Equal(t, 1, body.Meta.Total)
+
+		recorder, _ = serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks?status=unknown", "", "")
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder, body = serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks?perPage=50&page="+strconv.Itoa(math.MaxInt/25), "", "")
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, errors.CodeInvalidQueryParameter, body.Error.Code)
+	})
+
+	t.Run("PatchTask", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+taskID, "application/merge-patch+json",
+			`{"priority":"high","description":null}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		task := decodeData[models.DBTask](t, body)
+		assert.Equal(t, models.High, task.Priority)
+		assert.Empty(t, task.Description)
+		assert.Equal(t, "Sweep Floor", task.Title)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+taskID, "text/plain", `{"priority":"low"}`)
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+
+	t.Run("TransitionTask", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+taskID+"/transitions", "application/json", `{"action":"complete"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		task := decodeData[models.DBTask](t, body)
+		assert.Equal(t, models.Completed, task.Status)
+		assert.NotNil(t, task.CompletedAt)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPut, "/tasks/"+taskID, "application/json",
+			`{"title":"Sweep Floor","priority":"high","status":"in_progress"}`)
+		assert.Equal(t, http.StatusConflict, recorder.Code, "completed tasks must be reopened explicitly")
+	})
+
+	t.Run("DeleteTask", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+taskID, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+taskID, "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleTasks, env.owner, http.MethodDelete, "/tasks", "", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
+
+func TestTaskHierarchy(t *testing.T) {
+	env := newTestEnv(t)
+
+	create := func(t *testing.T, body string) string {
+		t.Helper()
+
+		recorder, decoded := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json", body)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		return decodeData[models.DBTask](t, decoded).ID
+	}
+	get := func(t *testing.T, id string) models.DBTask {
+		t.Helper()
+
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+id, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return decodeData[models.DBTask](t, body)
+	}
+	complete := func(t *testing.T, id string) {
+		t.Helper()
+
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+id+"/transitions", "application/json", `{"action":"complete"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	rootID := create(t, `{"title":"Move house","autoComplete":true}`)
+	packID := create(t, `{"title":"Pack boxes","parentID":"`+rootID+`"}`)
+	cleanID := create(t, `{"title":"Clean flat","parentID":"`+rootID+`","autoComplete":true}`)
+	kitchenID := create(t, `{"title":"Clean kitchen","parentID":"`+cleanID+`"}`)
+
+	t.Run("Subtree", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+rootID+"/subtree", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		tree := decodeData[models.TaskTree](t, body)
+		assert.Equal(t, rootID, tree.ID)
+		assert.Equal(t, &models.SubtaskProgress{Completed: 0, Total: 2}, tree.Progress)
+		require.Len(t, tree.Subtasks, 2)
+		subtasks := map[string]models.TaskTree{}
+		for _, subtask := range tree.Subtasks {
+			subtasks[subtask.ID] = subtask
+		}
+		assert.NotNil(t, subtasks[packID].Subtasks)
+		assert.Empty(t, subtasks[packID].Subtasks)
+		require.Len(t, subtasks[cleanID].Subtasks, 1)
+		assert.Equal(t, kitchenID, subtasks[cleanID].Subtasks[0].ID)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.intruder, http.MethodGet, "/tasks/"+rootID+"/subtree", "", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("ForeignParentIsNotFound", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleTasks, env.intruder, http.MethodPost, "/tasks", "application/json", `{"title":"Snoop","parentID":"`+rootID+`"}`)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("RejectsCycles", func(t *testing.T) {
+		for _, parentID := range []string{rootID, kitchenID} {
+			recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+rootID, "application/merge-patch+json", `{"parentID":"`+parentID+`"}`)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, []models.FieldError{{Field: "parentID", Message: "must not be the task itself or one of its subtasks"}}, body.Error.Fields)
+		}
+	})
+
+	t.Run("AutoCompletesParents", func(t *testing.T) {
+		complete(t, packID)
+		assert.Equal(t, &models.SubtaskProgress{Completed: 1, Total: 2}, get(t, rootID).Progress)
+
+		complete(t, kitchenID)
+		assert.Equal(t, models.Completed, get(t, cleanID).Status)
+
+		root := get(t, rootID)
+		assert.Equal(t, models.Completed, root.Status, "completion rolls up through every auto-completing ancestor")
+		assert.Equal(t, &models.SubtaskProgress{Completed: 2, Total: 2}, root.Progress)
+	})
+
+	t.Run("EditingSubtaskKeepsReopenedParent", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+rootID+"/transitions", "application/json", `{"action":"reopen"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+packID, "application/merge-patch+json", `{"title":"Pack all boxes"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, models.Pending, get(t, rootID).Status)
+	})
+
+	t.Run("MoveTakesSubtree", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+cleanID, "application/merge-patch+json", `{"parentID":"`+packID+`"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, packID, decodeData[models.DBTask](t, body).ParentID)
+		assert.Equal(t, cleanID, get(t, kitchenID).ParentID)
+		assert.Equal(t, &models.SubtaskProgress{Completed: 1, Total: 1}, get(t, rootID).Progress)
+	})
+
+	t.Run("DeletePromotesSubtasks", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+packID+"?subtasks=promote", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		assert.Equal(t, rootID, get(t, cleanID).ParentID)
+		assert.Equal(t, cleanID, get(t, kitchenID).ParentID)
+	})
+
+	t.Run("DeleteRemovesSubtree", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+rootID+"?subtasks=everything", "", "")
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+rootID, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		for _, id := range []string{rootID, cleanID, kitchenID} {
+			recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+id, "", "")
+			assert.Equal(t, http.StatusNotFound, recorder.Code)
+		}
+	})
+}
+
+func TestTaskDependencies(t *testing.T) {
+	env := newTestEnv(t)
+
+	create := func(t *testing.T, body string) string {
+		t.Helper()
+
+		recorder, decoded := serve(t, env.tasks.HandleTasks, env.owner, http.MethodPost, "/tasks", "application/json", body)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		return decodeData[models.DBTask](t, decoded).ID
+	}
+	block := func(t *testing.T, ctx context.Context, taskID, blockedByID string) (*httptest.ResponseRecorder, response) {
+		t.Helper()
+
+		return serve(t, env.tasks.HandleSingleTask, ctx, http.MethodPost, "/tasks/"+taskID+"/dependencies", "application/json", `{"blockedBy":"`+blockedByID+`"}`)
+	}
+	ids := func(tasks []models.DBTask) []string {
+		ids := make([]string, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+
+	designID := create(t, `{"title":"Design API","priority":"low"}`)
+	buildID := create(t, `{"title":"Build API","priority":"high"}`)
+	shipID := create(t, `{"title":"Ship API","priority":"high"}`)
+	docsID := create(t, `{"title":"Write docs","priority":"medium"}`)
+
+	t.Run("AddDependencies", func(t *testing.T) {
+		recorder, body := block(t, env.owner, buildID, designID)
+		require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "/tasks/"+buildID+"/dependencies/"+designID, recorder.Header().Get("Location"))
+		assert.Equal(t, designID, decodeData[models.DBTaskDependency](t, body).BlockedByID)
+
+		recorder, _ = block(t, env.owner, shipID, buildID)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder, body = block(t, env.owner, shipID, buildID)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, errors.CodeDependencyExists, body.Error.Code)
+	})
+
+	t.Run("RejectsInvalidDependencies", func(t *testing.T) {
+		recorder, body := block(t, env.owner, designID, designID)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, []models.FieldError{{Field: "blockedBy", Message: "must not be the task itself"}}, body.Error.Fields)
+
+		recorder, _ = block(t, env.intruder, designID, buildID)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("RejectsCycles", func(t *testing.T) {
+		recorder, body := block(t, env.owner, designID, shipID)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, errors.CodeDependencyCycle, body.Error.Code)
+	})
+
+	t.Run("ListDependencies", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodGet, "/tasks/"+buildID+"/dependencies", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		dependencies := decodeData[models.TaskDependencies](t, body)
+		assert.Equal(t, []string{designID}, ids(dependencies.BlockedBy))
+		assert.Equal(t, []string{shipID}, ids(dependencies.Blocks))
+	})
+
+	t.Run("ReadyFilter", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks?ready=true", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.ElementsMatch(t, []string{designID, docsID}, ids(decodeData[[]models.DBTask](t, body)))
+
+		recorder, _ = serve(t, env.tasks.HandleTasks, env.owner, http.MethodGet, "/tasks?ready=maybe", "", "")
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleTaskOrder, env.owner, http.MethodGet, "/tasks/order", "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, []string{docsID, designID, buildID, shipID}, ids(decodeData[[]models.DBTask](t, body)),
+			"blockers come first, otherwise higher priority goes first")
+	})
+
+	t.Run("BlockedTaskCannotStart", func(t *testing.T) {
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+buildID+"/transitions", "application/json", `{"action":"start"}`)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, errors.CodeTaskBlocked, body.Error.Code)
+
+		recorder, body = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPatch, "/tasks/"+buildID, "application/merge-patch+json", `{"status":"in_progress"}`)
+		require.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, errors.CodeTaskBlocked, body.Error.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+designID+"/transitions", "application/json", `{"action":"complete"}`)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, _ = serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodPost, "/tasks/"+buildID+"/transitions", "application/json", `{"action":"start"}`)
+		assert.Equal(t, http.StatusOK, recorder.Code, "completing the blocker unblocks the task")
+	})
+
+	t.Run("RemoveDependency", func(t *testing.T) {
+		recorder, _ := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+shipID+"/dependencies/"+buildID, "", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, body := serve(t, env.tasks.HandleSingleTask, env.owner, http.MethodDelete, "/tasks/"+shipID+"/dependencies/"+buildID, "", "")
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, errors.CodeDependencyNotFound, body.Error.Code)
+	})
+}
@@ -0,0 +1,138 @@
+// Package health answers the liveness and readiness probes of whatever
+// runs the server. Liveness only says the process can serve HTTP, so a
+// database outage does not get the process restarted. Readiness runs the
+// dependency checks, and fails once shutdown begins so load balancers stop
+// routing requests to a server that is draining.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusError        = "error"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check reports whether a dependency is usable. It must return once ctx is
+// done.
+type Check func(ctx context.Context) error
+
+// ComponentReport is the outcome of one check.
+type ComponentReport struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the body of a readiness response.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentReport `json:"components,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type Checker struct {
+	timeout      time.Duration
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+// NewChecker returns a checker that gives each check timeout to pass.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a readiness check. Checks must all be added before the
+// checker starts serving probes.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Shutdown marks the server as not ready for good.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs every check concurrently and reports the outcome. The server
+// is ready when all of them pass.
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	reports := make([]ComponentReport, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reports[i] = run(ctx, check.check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Components: make(map[string]ComponentReport, len(c.checks))}
+	for i, check := range c.checks {
+		report.Components[check.name] = reports[i]
+		if reports[i].Status != StatusOK {
+			report.Status = StatusNotReady
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, check Check) ComponentReport {
+	start := time.Now()
+	err := check(ctx)
+	report := ComponentReport{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		report.Status = StatusError
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+// LivenessHandler serves /livez.
+func (c *Checker) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// ReadinessHandler serves /readyz. It answers 503 Service Unavailable
+// while the server is not ready.
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := c.Ready(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != StatusReady {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeReport(w, statusCode, report)
+}
+
+func writeReport(w http.ResponseWriter, statusCode int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(report)
+}
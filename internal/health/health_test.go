@@ -0,0 +1,88 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, handler http.HandlerFunc) (int, health.Report) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	return recorder.Code, report
+}
+
+func passing(ctx context.Context) error {
+	return nil
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("Ready", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", passing)
+		checker.Add("migrations", passing)
+
+		code, report := probe(t, checker.ReadinessHandler)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, health.StatusReady, report.Status)
+		assert.Equal(t, health.StatusOK, report.Components["database"].Status)
+		assert.Equal(t, health.StatusOK, report.Components["migrations"].Status)
+	})
+
+	t.Run("FailingCheck", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", passing)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return fmt.Errorf("schema is at version 3, expected 5")
+		})
+
+		code, report := probe(t, checker.ReadinessHandler)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.Equal(t, health.StatusOK, report.Components["database"].Status)
+		assert.Equal(t, health.StatusError, report.Components["migrations"].Status)
+		assert.Equal(t, "schema is at version 3, expected 5", report.Components["migrations"].Error)
+	})
+
+	t.Run("SlowCheckTimesOut", func(t *testing.T) {
+		checker := health.NewChecker(20 * time.Millisecond)
+		checker.Add("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		code, report := probe(t, checker.ReadinessHandler)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusError, report.Components["database"].Status)
+		assert.GreaterOrEqual(t, report.Components["database"].LatencyMS, float64(20))
+	})
+
+	t.Run("ShuttingDown", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Add("database", passing)
+		checker.Shutdown()
+
+		code, report := probe(t, checker.ReadinessHandler)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusShuttingDown, report.Status)
+
+		code, report = probe(t, checker.LivenessHandler)
+		assert.Equal(t, http.StatusOK, code, "a draining server is still alive")
+		assert.Equal(t, health.StatusOK, report.Status)
+	})
+}
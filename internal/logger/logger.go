@@ -1,22 +1,52 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+
+	"github.com/kjj1998/task-management-system/internal/config"
 )
 
-func NewLogger(env string) *slog.Logger {
+// New returns the logger described by cfg, writing to w, and the level it
+// logs at, which can be changed while it runs. Secrets and email addresses
+// in its records are redacted.
+func New(w io.Writer, cfg config.LoggingConfig) (*slog.Logger, *slog.LevelVar, error) {
+	level := new(slog.LevelVar)
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: level}
 	var handler slog.Handler
+	switch cfg.Format {
+	case config.LogFormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	handler = redactHandler{handler}
+	if cfg.DebugSampleRatio < 1 {
+		handler = newSampleHandler(handler, cfg.DebugSampleRatio)
+	}
+
+	return slog.New(traceHandler{handler}), level, nil
+}
+
+// NewLogger returns a JSON logger on stdout for env, logging debug records
+// everywhere but in production.
+func NewLogger(env string) *slog.Logger {
+	level := "debug"
+	if env == "prod" {
+		level = "info"
+	}
 
-	if env == "production" {
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-		})
-	} else {
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
-		})
+	logger, _, err := New(os.Stdout, config.LoggingConfig{Level: level, Format: config.LogFormatJSON, DebugSampleRatio: 1})
+	if err != nil {
+		panic(err)
 	}
 
-	return slog.New(handler)
+	return logger
 }
@@ -0,0 +1,120 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newLogger(t *testing.T, cfg config.LoggingConfig) (*slog.Logger, *slog.LevelVar, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+	l, level, err := logger.New(&out, cfg)
+	require.NoError(t, err)
+
+	return l, level, &out
+}
+
+func records(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var decoded []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		decoded = append(decoded, record)
+	}
+
+	return decoded
+}
+
+func TestLevel(t *testing.T) {
+	l, level, out := newLogger(t, config.LoggingConfig{Level: "warn", Format: config.LogFormatJSON, DebugSampleRatio: 1})
+
+	l.Info("dropped")
+	l.Warn("kept")
+	level.Set(slog.LevelDebug)
+	l.Debug("kept after the level changed")
+
+	logged := records(t, out)
+	require.Len(t, logged, 2)
+	assert.Equal(t, "kept", logged[0]["msg"])
+	assert.Equal(t, "kept after the level changed", logged[1]["msg"])
+}
+
+func TestTextFormat(t *testing.T) {
+	l, _, out := newLogger(t, config.LoggingConfig{Level: "info", Format: config.LogFormatText, DebugSampleRatio: 1})
+
+	l.Info("request completed", slog.Int("status", 200))
+	assert.Contains(t, out.String(), `msg="request completed" status=200`)
+}
+
+func TestInvalidLevel(t *testing.T) {
+	_, _, err := logger.New(&bytes.Buffer{}, config.LoggingConfig{Level: "verbose", Format: config.LogFormatJSON})
+	assert.Error(t, err)
+}
+
+func TestRedaction(t *testing.T) {
+	l, _, out := newLogger(t, config.LoggingConfig{Level: "debug", Format: config.LogFormatJSON, DebugSampleRatio: 1})
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer eyJhbGciOi")
+	header.Set("Accept", "application/json")
+
+	l.With(slog.String("api_key", "tms_live_123")).Info("request",
+		slog.Any("headers", header),
+		slog.String("email", "jane.doe@example.com"),
+		slog.String("refresh_token", "r-123"),
+		slog.Group("user", slog.String("password", "hunter2"), slog.String("id", "42")),
+	)
+
+	logged := records(t, out)
+	require.Len(t, logged, 1)
+	record := logged[0]
+	assert.Equal(t, "[REDACTED]", record["api_key"])
+	assert.Equal(t, "j***@example.com", record["email"])
+	assert.Equal(t, "[REDACTED]", record["refresh_token"])
+	assert.Equal(t, map[string]any{"password": "[REDACTED]", "id": "42"}, record["user"])
+	assert.Equal(t, map[string]any{
+		"Authorization": []any{"[REDACTED]"},
+		"Accept":        []any{"application/json"},
+	}, record["headers"])
+	assert.Equal(t, "Bearer eyJhbGciOi", header.Get("Authorization"), "the logged header must not be changed")
+}
+
+func TestDebugSampling(t *testing.T) {
+	l, _, out := newLogger(t, config.LoggingConfig{Level: "debug", Format: config.LogFormatJSON, DebugSampleRatio: 0.5})
+
+	inTrace := func(traceID string) context.Context {
+		id, err := trace.TraceIDFromHex(traceID)
+		require.NoError(t, err)
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: id, SpanID: trace.SpanID{1}})
+		return trace.ContextWithSpanContext(context.Background(), spanContext)
+	}
+	kept := inTrace("00000000000000000000000000000001")
+	dropped := inTrace("0000000000000000ffffffffffffffff")
+
+	l.DebugContext(kept, "kept")
+	l.DebugContext(kept, "kept too")
+	l.DebugContext(dropped, "dropped")
+	l.InfoContext(dropped, "info is never sampled")
+
+	var messages []any
+	for _, record := range records(t, out) {
+		messages = append(messages, record["msg"])
+	}
+	assert.Equal(t, []any{"kept", "kept too", "info is never sampled"}, messages)
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the attribute keys whose values are never logged,
+// compared in lower case with dashes read as underscores. Keys ending in
+// _password, _secret or _token are treated the same way.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set_cookie":    true,
+	"x_api_key":     true,
+	"api_key":       true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+}
+
+// sensitiveHeaders are the request and response headers whose values are
+// never logged.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// redactHandler replaces secrets in records with a placeholder, and masks
+// email addresses down to their first letter and domain.
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(redact(attr))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, clean)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		clean[i] = redact(attr)
+	}
+
+	return redactHandler{h.Handler.WithAttrs(clean)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redact(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	key := strings.ReplaceAll(strings.ToLower(attr.Key), "-", "_")
+	switch {
+	case sensitiveKeys[key] || strings.HasSuffix(key, "_password") || strings.HasSuffix(key, "_secret") || strings.HasSuffix(key, "_token"):
+		return slog.String(attr.Key, redacted)
+	case key == "email" || strings.HasSuffix(key, "_email"):
+		return slog.String(attr.Key, maskEmail(attr.Value.String()))
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		clean := make([]slog.Attr, len(group))
+		for i, member := range group {
+			clean[i] = redact(member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(clean...)}
+	case slog.KindAny:
+		if header, ok := attr.Value.Any().(http.Header); ok {
+			return slog.Any(attr.Key, redactHeader(header))
+		}
+	}
+
+	return attr
+}
+
+func redactHeader(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := clean[name]; ok {
+			clean[name] = []string{redacted}
+		}
+	}
+
+	return clean
+}
+
+// maskEmail keeps the first letter and the domain of address, which is
+// enough to tell users apart in a log without identifying them.
+func maskEmail(address string) string {
+	local, domain, ok := strings.Cut(address, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+
+	return local[:1] + "***@" + domain
+}
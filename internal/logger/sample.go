@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math/rand/v2"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sampleHandler drops the debug records of all but a fraction of requests.
+// The decision is made from the trace ID, as trace sampling does, so a
+// request keeps either all of its debug records or none of them.
+type sampleHandler struct {
+	slog.Handler
+	threshold uint64
+}
+
+func newSampleHandler(next slog.Handler, ratio float64) sampleHandler {
+	return sampleHandler{Handler: next, threshold: uint64(ratio * (1 << 63))}
+}
+
+func (h sampleHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelInfo && !h.sampled(ctx) {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sampleHandler{Handler: h.Handler.WithAttrs(attrs), threshold: h.threshold}
+}
+
+func (h sampleHandler) WithGroup(name string) slog.Handler {
+	return sampleHandler{Handler: h.Handler.WithGroup(name), threshold: h.threshold}
+}
+
+// sampled reports whether the records of the trace ctx belongs to are kept.
+// Records outside a trace are sampled one by one.
+func (h sampleHandler) sampled(ctx context.Context) bool {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		traceID := spanContext.TraceID()
+		return binary.BigEndian.Uint64(traceID[8:16])>>1 < h.threshold
+	}
+
+	return rand.Uint64()>>1 < h.threshold
+}
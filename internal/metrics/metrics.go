@@ -0,0 +1,125 @@
+// Package metrics collects the server's Prometheus metrics and serves them
+// in the text exposition format.
+package metrics
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "taskapi"
+
+// UnmatchedRoute labels requests that matched no route, so that scans of
+// random paths cannot grow the number of series without bound.
+const UnmatchedRoute = "unmatched"
+
+// knownMethods are the request methods given their own label value. Any
+// other method is counted as OTHER.
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	operationDuration *prometheus.HistogramVec
+	operationErrors   *prometheus.CounterVec
+}
+
+// New returns metrics registered on their own registry, along with the Go
+// runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to serve HTTP requests, by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_operation_duration_seconds",
+			Help:      "Time taken by repository operations, by operation.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		operationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_operation_errors_total",
+			Help:      "Repository operations that failed, by operation and the status code of the error.",
+		}, []string{"operation", "status"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.operationDuration,
+		m.operationErrors,
+	)
+
+	return m
+}
+
+// RegisterDB exports the connection pool statistics of db, labelled with
+// dbName.
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// RecordRequest counts a served request. route is the pattern the request
+// matched, or UnmatchedRoute.
+func (m *Metrics) RecordRequest(method, route string, statusCode int, duration time.Duration) {
+	if !knownMethods[method] {
+		method = "OTHER"
+	}
+	if route == "" {
+		route = UnmatchedRoute
+	}
+
+	m.requests.WithLabelValues(method, route, strconv.Itoa(statusCode)).Inc()
+	m.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveOperation records a repository operation, named as it is for
+// HandleDatabaseError, that took duration and returned err.
+func (m *Metrics) ObserveOperation(operation string, duration time.Duration, err error) {
+	m.operationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err == nil {
+		return
+	}
+
+	statusCode := http.StatusInternalServerError
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		statusCode = appErr.StatusCode
+	}
+	m.operationErrors.WithLabelValues(operation, strconv.Itoa(statusCode)).Inc()
+}
+
+// Handler serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
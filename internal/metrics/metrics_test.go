@@ -0,0 +1,49 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+
+	m.RecordRequest(http.MethodGet, "/api/tasks/", http.StatusOK, 20*time.Millisecond)
+	m.RecordRequest(http.MethodGet, "/api/tasks/", http.StatusOK, 30*time.Millisecond)
+	m.RecordRequest("PROPFIND", "", http.StatusNotFound, time.Millisecond)
+
+	m.ObserveOperation("GetTaskByID", 2*time.Millisecond, nil)
+	m.ObserveOperation("GetTaskByID", time.Millisecond, errors.NewNotFoundError("Resource not found", sql.ErrNoRows))
+	m.ObserveOperation("CreateTask", time.Millisecond, fmt.Errorf("connection reset"))
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `taskapi_http_requests_total{method="GET",route="/api/tasks/",status="200"} 2`)
+	assert.Contains(t, body, `taskapi_http_requests_total{method="OTHER",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `taskapi_http_request_duration_seconds_count{method="GET",route="/api/tasks/"} 2`)
+	assert.Contains(t, body, `taskapi_db_operation_duration_seconds_count{operation="GetTaskByID"} 2`)
+	assert.Contains(t, body, `taskapi_db_operation_errors_total{operation="GetTaskByID",status="404"} 1`)
+	assert.Contains(t, body, `taskapi_db_operation_errors_total{operation="CreateTask",status="500"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+)
+
+// AdminMiddleware requires "Authorization: Bearer <token>" carrying the
+// admin token.
+func AdminMiddleware(token string, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			presented, ok := bearerToken(r)
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				unauthorized(w, r, errors.NewUnauthorizedError("Admin token required", fmt.Errorf("missing or wrong admin token")), logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+)
+
+// APIKeyAuthenticator resolves a personal API key to the identity of its
+// owner.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Identity, error)
+}
+
+// AuthMiddleware requires a valid "Authorization: Bearer <token>" header and
+// stores the authenticated caller in the request context. The token is
+// either an access token or a personal API key; read-only API keys may only
+// make safe requests.
+func AuthMiddleware(tokens *auth.TokenManager, apiKeys APIKeyAuthenticator, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, r, errors.NewUnauthorizedError("Authentication required", fmt.Errorf("missing bearer token")), logger)
+				return
+			}
+
+			var identity *auth.Identity
+			var err error
+			if auth.IsAPIKey(token) {
+				identity, err = apiKeys.AuthenticateAPIKey(r.Context(), token)
+			} else {
+				identity, err = tokens.ParseAccessToken(token)
+				if err != nil {
+					err = errors.NewUnauthorizedError("Invalid or expired access token", err).WithCode(errors.CodeInvalidAccessToken)
+				}
+			}
+			if err != nil {
+				unauthorized(w, r, err, logger)
+				return
+			}
+
+			if identity.ReadOnly && !isSafeMethod(r.Method) {
+				forbidden := errors.NewForbiddenError("API key does not allow write access", fmt.Errorf("read-only api key %s used for %s", identity.APIKeyID, r.Method)).WithCode(errors.CodeReadOnlyAPIKey)
+				errors.HandleError(w, r, forbidden, logger)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error, logger *slog.Logger) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="task-management-system"`)
+	errors.HandleError(w, r, err, logger)
+}
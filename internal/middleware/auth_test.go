@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAPIKeys map[string]*auth.Identity
+
+func (f fakeAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Identity, error) {
+	identity, ok := f[key]
+	if !ok {
+		return nil, errors.NewUnauthorizedError("Invalid API key", fmt.Errorf("unknown key"))
+	}
+	return identity, nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tokens := auth.NewTokenManager("test-secret-that-is-long-enough-for-hs256", "test-issuer", time.Minute)
+	apiKeys := fakeAPIKeys{
+		"tms_read":  {UserID: "1244ABC", Method: auth.MethodAPIKey, APIKeyID: "k1", ReadOnly: true},
+		"tms_write": {UserID: "1244ABC", Method: auth.MethodAPIKey, APIKeyID: "k2"},
+	}
+
+	var caller *auth.Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ = auth.IdentityFromContext(r.Context())
+	})
+	handler := middleware.AuthMiddleware(tokens, apiKeys, logger.NewLogger("test"))(next)
+
+	accessToken, _, err := tokens.IssueAccessToken("1244ABC", "john@email.com")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		status        int
+		authMethod    auth.Method
+	}{
+		{"MissingHeader", http.MethodGet, "", http.StatusUnauthorized, ""},
+		{"AccessToken", http.MethodPost, "Bearer " + accessToken, http.StatusOK, auth.MethodAccessToken},
+		{"InvalidAccessToken", http.MethodGet, "Bearer not-a-jwt", http.StatusUnauthorized, ""},
+		{"ReadKeyCanRead", http.MethodGet, "Bearer tms_read", http.StatusOK, auth.MethodAPIKey},
+		{"ReadKeyCannotWrite", http.MethodDelete, "Bearer tms_read", http.StatusForbidden, ""},
+		{"ReadWriteKeyCanWrite", http.MethodPatch, "Bearer tms_write", http.StatusOK, auth.MethodAPIKey},
+		{"UnknownKey", http.MethodGet, "Bearer tms_unknown", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller = nil
+			request := httptest.NewRequest(tt.method, "/tasks", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			if tt.status == http.StatusOK {
+				if assert.NotNil(t, caller) {
+					assert.Equal(t, "1244ABC", caller.UserID)
+					assert.Equal(t, tt.authMethod, caller.Method)
+				}
+			} else {
+				assert.Nil(t, caller)
+			}
+		})
+	}
+}
@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 type CORSConfig struct {
 	AllowedOrigins   []string
 	AllowedMethods   []string
 	AllowedHeaders   []string
+	ExposedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
 }
@@ -16,44 +19,71 @@ type CORSConfig struct {
 func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept", "Origin", "X-Requested-With"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept", "Origin", "X-Requested-With", "X-Request-ID"},
+		ExposedHeaders:   []string{"X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           86400,
 	}
 }
 
 func CORSMiddleware(config CORSConfig) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
-
-			// Check if origin is allowed
-			if isOriginAllowed(origin, config.AllowedOrigins) {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-			}
-
-			// Set other CORS headers
-			w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
-			w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
-
-			if config.AllowCredentials {
-				w.Header().Set("Access-Control-Allow-Credentials", "true")
-			}
-
-			if config.MaxAge > 0 {
-				w.Header().Set("Access-Control-Max-Age", string(rune(config.MaxAge)))
-			}
-
-			// Handle preflight requests
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
+	return NewCORS(config).Middleware
+}
+
+// CORS applies a CORSConfig whose allowed origins can be replaced while the
+// server runs.
+type CORS struct {
+	config atomic.Pointer[CORSConfig]
+}
+
+func NewCORS(config CORSConfig) *CORS {
+	c := &CORS{}
+	c.config.Store(&config)
+	return c
+}
+
+// SetAllowedOrigins replaces the allowed origins for the requests that
+// follow.
+func (c *CORS) SetAllowedOrigins(origins []string) {
+	config := *c.config.Load()
+	config.AllowedOrigins = origins
+	c.config.Store(&config)
+}
+
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config := c.config.Load()
+		origin := r.Header.Get("Origin")
+
+		// Check if origin is allowed
+		if isOriginAllowed(origin, config.AllowedOrigins) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		// Set other CORS headers
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+		if len(config.ExposedHeaders) > 0 {
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+		}
+
+		if config.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if config.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+		}
+
+		// Handle preflight requests
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
 }
 
 func isOriginAllowed(origin string, allowedOrigins []string) bool {
@@ -0,0 +1,29 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSSetAllowedOrigins(t *testing.T) {
+	cors := middleware.NewCORS(middleware.DefaultCORSConfig())
+	handler := cors.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	allowedOrigin := func(origin string) string {
+		r := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		r.Header.Set("Origin", origin)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "https://evil.example", allowedOrigin("https://evil.example"))
+
+	cors.SetAllowedOrigins([]string{"https://app.example.com"})
+	assert.Empty(t, allowedOrigin("https://evil.example"))
+	assert.Equal(t, "https://app.example.com", allowedOrigin("https://app.example.com"))
+}
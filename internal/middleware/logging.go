@@ -4,14 +4,84 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+// RequestRecorder receives the outcome of every request LoggingMiddleware
+// sees.
+type RequestRecorder interface {
+	RecordRequest(method, route string, statusCode int, duration time.Duration)
+}
+
+// responseWriter captures the status code and size of a response, and the
+// pattern of the route that served it.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	bytes      int
+	route      string
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RoutePatternMiddleware reports the pattern of the route a ServeMux
+// matched to the LoggingMiddleware further out, prefixed with the path the
+// mux is mounted under, and names the request's span after it. It must wrap
+// the mux directly, since the mux records the pattern on the request it is
+// given.
+func RoutePatternMiddleware(prefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if r.Pattern == "" {
+				return
+			}
+			route := prefix + r.Pattern
+			if rw, ok := w.(*responseWriter); ok {
+				rw.route = route
+			}
+
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		})
+	}
+}
+
+// LoggingMiddleware logs every request and reports it to recorder, which
+// may be nil. Requests are logged with the logger in their context, falling
+// back to baseLogger.
+func LoggingMiddleware(baseLogger *slog.Logger, recorder RequestRecorder) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w}
+			logger := logger.FromContext(r.Context(), baseLogger)
 
-			logger.Debug("incoming request details",
+			logger.DebugContext(r.Context(), "incoming request details",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.String("query", r.URL.RawQuery),
@@ -20,27 +90,37 @@ func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 				slog.String("host", r.Host),
 				slog.Any("headers", r.Header),
 			)
-			logger.Info("request started",
+			logger.InfoContext(r.Context(), "request started",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("remote_addr", r.RemoteAddr),
 			)
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(rw, r)
 			duration := time.Since(start)
+			if rw.statusCode == 0 {
+				rw.statusCode = http.StatusOK
+			}
+			if recorder != nil {
+				recorder.RecordRequest(r.Method, rw.route, rw.statusCode, duration)
+			}
 
-			logger.Debug("request performance",
+			logger.DebugContext(r.Context(), "request performance",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.Duration("duration", duration),
 				slog.Int64("duration_ms", duration.Milliseconds()),
 			)
 
-			logger.Info("request completed",
+			logger.InfoContext(r.Context(), "request completed",
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
+				slog.String("route", rw.route),
+				slog.Int("status", rw.statusCode),
+				slog.Int("bytes", rw.bytes),
 				slog.Duration("duration", duration),
+				slog.Int64("duration_ms", duration.Milliseconds()),
 			)
 		})
 	}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method     string
+	route      string
+	statusCode int
+}
+
+type fakeRecorder struct {
+	requests []recordedRequest
+}
+
+func (f *fakeRecorder) RecordRequest(method, route string, statusCode int, duration time.Duration) {
+	f.requests = append(f.requests, recordedRequest{method: method, route: route, statusCode: statusCode})
+}
+
+func TestLoggingMiddlewareRecordsRequests(t *testing.T) {
+	router := http.NewServeMux()
+	router.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Task not found", http.StatusNotFound)
+	})
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {})
+
+	recorder := &fakeRecorder{}
+	apiRouter := http.StripPrefix("/api", middleware.RoutePatternMiddleware("/api")(router))
+	handler := middleware.LoggingMiddleware(logger.NewLogger("test"), recorder)(apiRouter)
+
+	for _, target := range []string{"/api/tasks/123", "/api/tasks", "/api/nothing-here", "/elsewhere"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assert.Equal(t, []recordedRequest{
+		{method: http.MethodGet, route: "/api/tasks/", statusCode: http.StatusNotFound},
+		{method: http.MethodGet, route: "/api/tasks", statusCode: http.StatusOK},
+		{method: http.MethodGet, route: "", statusCode: http.StatusNotFound},
+		{method: http.MethodGet, route: "", statusCode: http.StatusNotFound},
+	}, recorder.requests)
+}
+
+func TestLoggingMiddlewareLogsResponse(t *testing.T) {
+	var logs bytes.Buffer
+	handler := middleware.LoggingMiddleware(slog.New(slog.NewJSONHandler(&logs, nil)), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true}`))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tasks", nil))
+
+	var completed map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		if record["msg"] == "request completed" {
+			completed = record
+		}
+	}
+	require.NotNil(t, completed)
+	assert.Equal(t, float64(http.StatusCreated), completed["status"])
+	assert.Equal(t, float64(len(`{"success":true}`)), completed["bytes"])
+	assert.Contains(t, completed, "duration_ms")
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"golang.org/x/time/rate"
+)
+
+// idleClientTTL is how long a client's bucket is kept after its last
+// request. A client returning later starts with a full bucket.
+const idleClientTTL = 3 * time.Minute
+
+// RateLimiter limits the requests each client address may make with a
+// token bucket per address. Its limits can be changed while the server
+// runs.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*rateLimitedClient
+	lastSweep time.Time
+	logger    *slog.Logger
+}
+
+type rateLimitedClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter allows each client requestsPerSecond on average, in
+// bursts of up to burst requests. A requestsPerSecond of 0 allows any rate.
+func NewRateLimiter(requestsPerSecond float64, burst int, logger *slog.Logger) *RateLimiter {
+	l := &RateLimiter{clients: map[string]*rateLimitedClient{}, logger: logger}
+	l.SetLimits(requestsPerSecond, burst)
+	return l
+}
+
+// SetLimits replaces the limits. Every client starts again with a full
+// bucket.
+func (l *RateLimiter) SetLimits(requestsPerSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(requestsPerSecond)
+	l.burst = burst
+	clear(l.clients)
+}
+
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay, ok := l.reserve(clientAddress(r), time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			limited := errors.NewTooManyRequestsError("Too many requests", fmt.Errorf("rate limit exceeded, retry in %s", delay))
+			errors.HandleError(w, r, limited, l.logger)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserve takes a token from the bucket of address. When there is none it
+// reports how long until there will be.
+func (l *RateLimiter) reserve(address string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit == 0 {
+		return 0, true
+	}
+
+	if now.Sub(l.lastSweep) > idleClientTTL {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > idleClientTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[address]
+	if !ok {
+		client = &rateLimitedClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[address] = client
+	}
+	client.lastSeen = now
+
+	reservation := client.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+
+	return 0, true
+}
+
+// clientAddress is the IP address a request came from. Forwarding headers
+// are ignored, since any client can set them.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,36 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := middleware.NewRateLimiter(1, 2, logger.NewLogger("test"))
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		r.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:5000").Code)
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:5001").Code)
+	limited := request("10.0.0.1:5002")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code, "the burst is spent across connections from one address")
+	assert.Equal(t, "1", limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, request("10.0.0.2:5000").Code, "other addresses have their own bucket")
+
+	limiter.SetLimits(0, 1)
+	for range 5 {
+		assert.Equal(t, http.StatusOK, request("10.0.0.1:5003").Code, "a rate of 0 turns the limit off")
+	}
+}
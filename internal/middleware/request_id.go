@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/requestid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDMiddleware gives every request an ID, taken from its
+// X-Request-ID header when the caller sent a usable one. The ID is echoed
+// in the response header, recorded on the request's span, and stored in
+// the request context along with a logger that adds it to every record.
+func RequestIDMiddleware(baseLogger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestid.FromHeader(r.Header.Get(requestid.Header))
+			w.Header().Set(requestid.Header, id)
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request.id", id))
+
+			ctx := requestid.WithID(r.Context(), id)
+			ctx = logger.WithContext(ctx, baseLogger.With(slog.String("request_id", id)))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/kjj1998/task-management-system/internal/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	baseLogger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	var seen string
+	handler := middleware.RequestIDMiddleware(baseLogger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestid.FromContext(r.Context())
+		logger.FromContext(r.Context(), nil).Info("handled")
+	}))
+
+	serve := func(header string) *httptest.ResponseRecorder {
+		logs.Reset()
+		request := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		if header != "" {
+			request.Header.Set(requestid.Header, header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("HonoursCallerID", func(t *testing.T) {
+		recorder := serve("checkout-7f3a.42")
+
+		assert.Equal(t, "checkout-7f3a.42", seen)
+		assert.Equal(t, "checkout-7f3a.42", recorder.Header().Get(requestid.Header))
+		assert.Contains(t, logs.String(), `"request_id":"checkout-7f3a.42"`)
+	})
+
+	t.Run("GeneratesMissingID", func(t *testing.T) {
+		recorder := serve("")
+
+		assert.Len(t, seen, 36)
+		assert.Equal(t, seen, recorder.Header().Get(requestid.Header))
+	})
+
+	t.Run("ReplacesUnusableID", func(t *testing.T) {
+		recorder := serve(`bad id" "injected":"x`)
+
+		assert.Len(t, seen, 36)
+		assert.Equal(t, seen, recorder.Header().Get(requestid.Header))
+		assert.NotContains(t, logs.String(), "injected")
+	})
+}
@@ -0,0 +1,86 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+)
+
+// versionTable records the schema version. It has the layout the migrate
+// CLI used, so databases it migrated carry on from where it stopped.
+const versionTable = "schema_migrations"
+
+// lockName identifies the migration lock on MySQL, and lockID on
+// PostgreSQL. Both locks are held by a session and released when it ends,
+// so a replica that dies mid-migration cannot leave the lock behind.
+const (
+	lockName           = "taskapi_schema_migrations"
+	lockID             = int64(4809021752316125473)
+	lockTimeoutSeconds = 600
+)
+
+// dialect is what the migrator needs to know about a database.
+type dialect struct {
+	// transactionalDDL reports whether schema changes can be rolled back,
+	// in which case each migration is applied in a transaction.
+	transactionalDDL bool
+	// splitStatements reports whether the driver runs one statement per
+	// call, so scripts are split before they are run.
+	splitStatements bool
+	insertVersion   string
+	// lock blocks until conn holds the migration lock and returns the
+	// function that releases it.
+	lock func(ctx context.Context, conn *sql.Conn) (func(), error)
+}
+
+var dialects = map[string]dialect{
+	config.DriverMySQL: {
+		splitStatements: true,
+		insertVersion:   "INSERT INTO " + versionTable + " (version, dirty) VALUES (?, ?)",
+		lock:            lockMySQL,
+	},
+	config.DriverPostgres: {
+		transactionalDDL: true,
+		insertVersion:    "INSERT INTO " + versionTable + " (version, dirty) VALUES ($1, $2)",
+		lock:             lockPostgres,
+	},
+	config.DriverSQLite: {
+		transactionalDDL: true,
+		insertVersion:    "INSERT INTO " + versionTable + " (version, dirty) VALUES (?, ?)",
+		lock:             lockSQLite,
+	},
+}
+
+func lockMySQL(ctx context.Context, conn *sql.Conn) (func(), error) {
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Scan(&acquired); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if acquired.Int64 != 1 {
+		return nil, fmt.Errorf("timed out waiting for the migration lock held by another instance")
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+	}, nil
+}
+
+func lockPostgres(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+	}, nil
+}
+
+// lockSQLite takes no lock up front. Each migration runs in a transaction
+// that holds the database's write lock and checks the version before it
+// changes anything, which is enough to keep two processes from applying
+// the same migration.
+func lockSQLite(ctx context.Context, conn *sql.Conn) (func(), error) {
+	return func() {}, nil
+}
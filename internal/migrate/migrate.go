@@ -0,0 +1,377 @@
+// Package migrate applies the schema migrations embedded in the binary. The
+// schema version is recorded in the migrated database itself, and a lock
+// held for the whole run keeps replicas that start together from migrating
+// at the same time.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/migrations"
+)
+
+// DirtyError reports that a migration failed part way through on a
+// database whose schema changes cannot be rolled back. The schema has to be
+// repaired by hand and the version set with Force before migrating again.
+type DirtyError struct {
+	Version uint
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("database is dirty at version %d: repair the schema, then force the version it is at", e.Version)
+}
+
+// Status is the schema version of a database and which migrations it has.
+type Status struct {
+	Version    uint
+	Dirty      bool
+	Migrations []MigrationStatus
+}
+
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// New returns a migrator applying the embedded migrations of driver to db.
+func New(db *sql.DB, driver string, logger *slog.Logger) (*Migrator, error) {
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("migrations are not supported for driver %q", driver)
+	}
+	fsys, err := migrations.For(driver)
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: loaded,
+		logger:     logger.With(slog.String("component", "migrate")),
+	}, nil
+}
+
+// Migrations returns the embedded migrations in version order.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest returns the version the embedded migrations bring a database to.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version reads the schema version of the database without taking the
+// migration lock, so it answers even while another instance is migrating.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	return readVersion(ctx, m.db)
+}
+
+// Up applies every migration the database does not have yet. A database
+// already ahead of the binary, as during the rollback of a deploy, is left
+// as it is.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(current uint) (uint, error) {
+		latest := m.Latest()
+		if current > latest {
+			m.logger.Warn("database schema is newer than this binary",
+				slog.Uint64("version", uint64(current)),
+				slog.Uint64("latest", uint64(latest)),
+			)
+			return current, nil
+		}
+		return latest, nil
+	})
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.Steps(ctx, -1)
+}
+
+// Steps applies the next n migrations, or reverts the last -n when n is
+// negative. It stops at the first or last migration.
+func (m *Migrator) Steps(ctx context.Context, n int) error {
+	return m.run(ctx, func(current uint) (uint, error) {
+		position, err := m.position(current)
+		if err != nil {
+			return 0, err
+		}
+
+		target := min(max(position+n, -1), len(m.migrations)-1)
+		if target < 0 {
+			return 0, nil
+		}
+		return m.migrations[target].Version, nil
+	})
+}
+
+// Goto applies or reverts migrations until the database is at version. At
+// version 0 every migration is reverted.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	if _, err := m.position(version); err != nil {
+		return err
+	}
+
+	return m.run(ctx, func(current uint) (uint, error) {
+		if _, err := m.position(current); err != nil {
+			return 0, err
+		}
+		return version, nil
+	})
+}
+
+// Force records that the database is at version without running any
+// migration, which clears the dirty flag after a failed migration has been
+// repaired by hand.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if _, err := m.position(version); err != nil {
+		return err
+	}
+
+	return m.session(ctx, func(conn *sql.Conn) error {
+		if err := m.setVersion(ctx, conn, version, false); err != nil {
+			return err
+		}
+
+		m.logger.Warn("forced schema version", slog.Uint64("version", uint64(version)))
+		return nil
+	})
+}
+
+// Status reports the version of the database and which of the embedded
+// migrations it has.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	status := &Status{}
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		var err error
+		status.Version, status.Dirty, err = readVersion(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		status.Migrations = append(status.Migrations, MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= status.Version,
+		})
+	}
+
+	return status, nil
+}
+
+// session runs fn on a connection holding the migration lock, once the
+// version table exists.
+func (m *Migrator) session(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a database connection: %w", err)
+	}
+	defer conn.Close()
+
+	unlock, err := m.dialect.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	createTable := "CREATE TABLE IF NOT EXISTS " + versionTable + " (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("failed to create %s: %w", versionTable, err)
+	}
+
+	return fn(conn)
+}
+
+// run migrates the database to the version target picks from the current
+// one, one migration at a time.
+func (m *Migrator) run(ctx context.Context, target func(current uint) (uint, error)) error {
+	return m.session(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return &DirtyError{Version: current}
+		}
+
+		version, err := target(current)
+		if err != nil {
+			return err
+		}
+		if version == current {
+			m.logger.Info("database schema is up to date", slog.Uint64("version", uint64(current)))
+			return nil
+		}
+
+		for current != version {
+			next, script, name, err := m.step(current, version)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			if err := m.apply(ctx, conn, current, next, script); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", max(current, next), name, err)
+			}
+			m.logger.Info("applied migration",
+				slog.Uint64("from", uint64(current)),
+				slog.Uint64("to", uint64(next)),
+				slog.String("name", name),
+				slog.Duration("duration", time.Since(start)),
+			)
+			current = next
+		}
+
+		return nil
+	})
+}
+
+// step returns the version one migration closer to target than current,
+// and the script that gets there.
+func (m *Migrator) step(current, target uint) (uint, string, string, error) {
+	position, err := m.position(current)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	if current < target {
+		migration := m.migrations[position+1]
+		return migration.Version, migration.Up, migration.Name, nil
+	}
+
+	migration := m.migrations[position]
+	if position == 0 {
+		return 0, migration.Down, migration.Name, nil
+	}
+	return m.migrations[position-1].Version, migration.Down, migration.Name, nil
+}
+
+// apply runs script to take the database from version from to version to.
+// Where schema changes are transactional the script and the new version
+// are committed together; elsewhere the version is marked dirty until the
+// script has run.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, from, to uint, script string) error {
+	if !m.dialect.transactionalDDL {
+		if err := m.setVersion(ctx, conn, to, true); err != nil {
+			return err
+		}
+		if err := m.exec(ctx, conn, script); err != nil {
+			return err
+		}
+		return m.setVersion(ctx, conn, to, false)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, dirty, err := readVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if current != from || dirty {
+		return fmt.Errorf("schema version changed to %d by another instance", current)
+	}
+
+	if err := m.exec(ctx, tx, script); err != nil {
+		return err
+	}
+	if err := m.setVersion(ctx, tx, to, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) exec(ctx context.Context, q database.Queryer, script string) error {
+	statements := []string{script}
+	if m.dialect.splitStatements {
+		statements = splitStatements(script)
+	}
+
+	for _, statement := range statements {
+		if _, err := q.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setVersion replaces the recorded version. Version 0 is recorded as an
+// empty table unless it is dirty.
+func (m *Migrator) setVersion(ctx context.Context, q database.Queryer, version uint, dirty bool) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM "+versionTable); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if version == 0 && !dirty {
+		return nil
+	}
+
+	if _, err := q.ExecContext(ctx, m.dialect.insertVersion, int64(version), dirty); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+
+	return nil
+}
+
+func readVersion(ctx context.Context, q database.Queryer) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := q.QueryRowContext(ctx, "SELECT version, dirty FROM "+versionTable+" LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return uint(version), dirty, nil
+}
+
+// position returns the index of version in m.migrations, or -1 for
+// version 0.
+func (m *Migrator) position(version uint) (int, error) {
+	if version == 0 {
+		return -1, nil
+	}
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown schema version %d", version)
+}
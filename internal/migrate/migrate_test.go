@@ -0,0 +1,169 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteMigrator(t *testing.T) (*migrate.Migrator, *sql.DB) {
+	t.Helper()
+
+	logger := logger.NewLogger("test")
+	require.NoError(t, database.ConnectSQLite(filepath.Join(t.TempDir(), "taskapi.db"), logger))
+	db := database.GetDb()
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrate.New(db, config.DriverSQLite, logger)
+	require.NoError(t, err)
+
+	return migrator, db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count))
+	return count == 1
+}
+
+func columnExists(t *testing.T, db *sql.DB, table string, column string) bool {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count))
+	return count == 1
+}
+
+func assertVersion(t *testing.T, migrator *migrate.Migrator, version uint) {
+	t.Helper()
+
+	status, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, version, status.Version)
+	assert.False(t, status.Dirty)
+	for _, migration := range status.Migrations {
+		assert.Equal(t, migration.Version <= version, migration.Applied, "migration %d", migration.Version)
+	}
+}
+
+func TestDriversShareVersions(t *testing.T) {
+	logger := logger.NewLogger("test")
+
+	mysql, err := migrate.New(nil, config.DriverMySQL, logger)
+	require.NoError(t, err)
+	require.NotEmpty(t, mysql.Migrations())
+
+	for _, driver := range []string{config.DriverPostgres, config.DriverSQLite} {
+		migrator, err := migrate.New(nil, driver, logger)
+		require.NoError(t, err)
+
+		require.Len(t, migrator.Migrations(), len(mysql.Migrations()), driver)
+		for i, migration := range migrator.Migrations() {
+			assert.Equal(t, mysql.Migrations()[i].Version, migration.Version, driver)
+			assert.Equal(t, mysql.Migrations()[i].Name, migration.Name, driver)
+		}
+	}
+
+	_, err = migrate.New(nil, "oracle", logger)
+	assert.Error(t, err)
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newSQLiteMigrator(t)
+	latest := migrator.Migrations()[len(migrator.Migrations())-1].Version
+
+	assertVersion(t, migrator, 0)
+
+	require.NoError(t, migrator.Up(ctx))
+	assertVersion(t, migrator, latest)
+	assert.True(t, tableExists(t, db, "task_dependencies"))
+	assert.True(t, columnExists(t, db, "tasks", "parent_id"))
+
+	require.NoError(t, migrator.Up(ctx), "migrating an up to date database is a no-op")
+	assertVersion(t, migrator, latest)
+
+	require.NoError(t, migrator.Down(ctx))
+	assertVersion(t, migrator, latest-1)
+	assert.True(t, tableExists(t, db, "task_dependencies"))
+
+	require.NoError(t, migrator.Down(ctx))
+	assertVersion(t, migrator, latest-2)
+	assert.False(t, tableExists(t, db, "task_dependencies"))
+	assert.True(t, columnExists(t, db, "tasks", "parent_id"))
+
+	require.NoError(t, migrator.Down(ctx))
+	assertVersion(t, migrator, latest-3)
+	assert.False(t, columnExists(t, db, "tasks", "parent_id"))
+	assert.True(t, columnExists(t, db, "tasks", "category_id"))
+	assert.True(t, tableExists(t, db, "api_keys"))
+
+	require.NoError(t, migrator.Goto(ctx, 2))
+	assertVersion(t, migrator, 2)
+	assert.True(t, tableExists(t, db, "categories"))
+	assert.False(t, tableExists(t, db, "tasks"))
+
+	require.NoError(t, migrator.Steps(ctx, 1))
+	assertVersion(t, migrator, 3)
+
+	require.NoError(t, migrator.Goto(ctx, 0))
+	assertVersion(t, migrator, 0)
+	assert.False(t, tableExists(t, db, "users"))
+
+	assert.Error(t, migrator.Goto(ctx, latest+1))
+	assertVersion(t, migrator, 0)
+
+	require.NoError(t, migrator.Steps(ctx, 100))
+	assertVersion(t, migrator, latest)
+}
+
+func TestDirtyDatabase(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newSQLiteMigrator(t)
+
+	require.NoError(t, migrator.Goto(ctx, 3))
+	_, err := db.Exec("UPDATE schema_migrations SET dirty = TRUE")
+	require.NoError(t, err)
+
+	status, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Dirty)
+
+	var dirtyErr *migrate.DirtyError
+	if assert.ErrorAs(t, migrator.Up(ctx), &dirtyErr) {
+		assert.Equal(t, uint(3), dirtyErr.Version)
+	}
+
+	require.NoError(t, migrator.Force(ctx, 3))
+	assertVersion(t, migrator, 3)
+	require.NoError(t, migrator.Up(ctx))
+}
+
+func TestRevokedAPIKeyNamesCanBeReused(t *testing.T) {
+	migrator, db := newSQLiteMigrator(t)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	_, err := db.Exec("INSERT INTO users (id, email, password_hash, first_name, last_name) VALUES ('u1', 'john@email.com', 'x', 'John', 'Doe')")
+	require.NoError(t, err)
+	insertKey := func(id string, hash string) error {
+		_, err := db.Exec("INSERT INTO api_keys (id, user_id, name, prefix, key_hash) VALUES (?, 'u1', 'ci', 'tms_', ?)", id, hash)
+		return err
+	}
+
+	require.NoError(t, insertKey("k1", "h1"))
+	assert.Error(t, insertKey("k2", "h2"), "an active key reserves its name")
+
+	_, err = db.Exec("UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = 'k1'")
+	require.NoError(t, err)
+	assert.NoError(t, insertKey("k2", "h2"), "a revoked key does not")
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	ScopeRead      APIKeyScope = "read"
+	ScopeReadWrite APIKeyScope = "read_write"
+)
+
+func (s APIKeyScope) IsValid() bool {
+	return s == ScopeRead || s == ScopeReadWrite
+}
+
+// DBAPIKey is a personal API key. Only the SHA-256 hash of the key is
+// stored; Prefix is kept in clear so users can tell their keys apart.
+type DBAPIKey struct {
+	ID         string      `json:"id"`
+	UserID     string      `json:"userID"`
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix"`
+	KeyHash    string      `json:"-"`
+	Scope      APIKeyScope `json:"scope"`
+	LastUsedAt *time.Time  `json:"lastUsedAt"`
+	RevokedAt  *time.Time  `json:"revokedAt,omitempty"`
+	CreatedAt  *time.Time  `json:"createdAt"`
+}
+
+func (k DBAPIKey) String() string {
+	return fmt.Sprintf(
+		"DBAPIKey[ID=%s, UserID=%s, Name=%s, Prefix=%s, Scope=%s, Revoked=%t]",
+		k.ID,
+		k.UserID,
+		k.Name,
+		k.Prefix,
+		k.Scope,
+		k.RevokedAt != nil,
+	)
+}
@@ -6,11 +6,11 @@ import (
 )
 
 type DBCategory struct {
-	ID        string
-	UserID    string
-	Name      string
-	Color     string
-	CreatedAt *time.Time
+	ID        string     `json:"id"`
+	UserID    string     `json:"userID"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	CreatedAt *time.Time `json:"createdAt"`
 }
 
 func (c DBCategory) String() string {
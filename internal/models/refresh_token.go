@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DBRefreshToken is a stored refresh token. Only the SHA-256 hash of the
+// token is kept; tokens issued by rotating one another share a FamilyID.
+type DBRefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy string
+	CreatedAt  *time.Time
+}
+
+func (t DBRefreshToken) String() string {
+	return fmt.Sprintf(
+		"DBRefreshToken[ID=%s, UserID=%s, FamilyID=%s, ExpiresAt=%s, Revoked=%t]",
+		t.ID,
+		t.UserID,
+		t.FamilyID,
+		t.ExpiresAt.Format(time.RFC3339),
+		t.RevokedAt != nil,
+	)
+}
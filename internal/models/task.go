@@ -22,10 +22,31 @@ const (
 	Completed  TaskStatus = "completed"
 )
 
+// IsValid reports whether p is one of the priorities accepted by the tasks table.
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case Low, Medium, High:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid reports whether s is one of the statuses accepted by the tasks table.
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case Pending, InProgress, Completed:
+		return true
+	default:
+		return false
+	}
+}
+
 type DBTask struct {
 	ID          string       `json:"id"`
 	UserID      string       `json:"userID"`
 	CategoryID  string       `json:"categoryID"`
+	ParentID    string       `json:"parentID"`
 	Title       string       `json:"title"`
 	Description string       `json:"description"`
 	Priority    TaskPriority `json:"priority"`
@@ -34,6 +55,25 @@ type DBTask struct {
 	CompletedAt *time.Time   `json:"completedAt"`
 	CreatedAt   *time.Time   `json:"createdAt"`
 	UpdatedAt   *time.Time   `json:"updatedAt"`
+	// AutoComplete completes the task once all of its subtasks are.
+	AutoComplete bool `json:"autoComplete"`
+
+	// Progress counts the direct subtasks of the task. It is derived rather
+	// than stored, and is nil for tasks without subtasks.
+	Progress *SubtaskProgress `json:"progress,omitempty"`
+}
+
+// SubtaskProgress counts the direct subtasks of a task and how many of them
+// are completed.
+type SubtaskProgress struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// TaskTree is a task together with the subtasks nested under it.
+type TaskTree struct {
+	DBTask
+	Subtasks []TaskTree `json:"subtasks"`
 }
 
 func (t DBTask) String() string {
@@ -45,10 +85,11 @@ func (t DBTask) String() string {
 	}
 
 	return fmt.Sprintf(
-		"DBTask[ID=%s, UserID=%s, CategoryID=%s, Title=%s, Description=%s, Priority=%s, Status=%s, DueDate=%s, CompletedAt=%s, CreatedAt=%s, UpdatedAt=%s]",
+		"DBTask[ID=%s, UserID=%s, CategoryID=%s, ParentID=%s, Title=%s, Description=%s, Priority=%s, Status=%s, DueDate=%s, CompletedAt=%s, CreatedAt=%s, UpdatedAt=%s, AutoComplete=%t]",
 		t.ID,
 		t.UserID,
 		t.CategoryID,
+		t.ParentID,
 		t.Title,
 		t.Description,
 		t.Priority,
@@ -57,5 +98,6 @@ func (t DBTask) String() string {
 		formatTime(t.CompletedAt),
 		formatTime(t.CreatedAt),
 		formatTime(t.UpdatedAt),
+		t.AutoComplete,
 	)
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// DBTaskDependency records that the task TaskID cannot start until the task
+// BlockedByID is completed.
+type DBTaskDependency struct {
+	TaskID      string     `json:"taskID"`
+	BlockedByID string     `json:"blockedByID"`
+	CreatedAt   *time.Time `json:"createdAt"`
+}
+
+// TaskDependencies lists the tasks a task is blocked by and the tasks it
+// blocks.
+type TaskDependencies struct {
+	BlockedBy []DBTask `json:"blockedBy"`
+	Blocks    []DBTask `json:"blocks"`
+}
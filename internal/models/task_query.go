@@ -0,0 +1,198 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TaskSortField string
+
+const (
+	SortByCreatedAt TaskSortField = "createdAt"
+	SortByUpdatedAt TaskSortField = "updatedAt"
+	SortByDueDate   TaskSortField = "dueDate"
+	SortByPriority  TaskSortField = "priority"
+	SortByStatus    TaskSortField = "status"
+	SortByTitle     TaskSortField = "title"
+)
+
+// NoDueDate stands in for a missing due date when sorting and paginating, so
+// tasks without a due date sort after every task that has one.
+var NoDueDate = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+func (f TaskSortField) IsValid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByDueDate, SortByPriority, SortByStatus, SortByTitle:
+		return true
+	default:
+		return false
+	}
+}
+
+type TaskSort struct {
+	Field      TaskSortField
+	Descending bool
+}
+
+// TaskFilter narrows a task listing. Every "After" bound is inclusive and
+// every "Before" bound is exclusive.
+type TaskFilter struct {
+	UserID      string
+	Statuses    []TaskStatus
+	Priorities  []TaskPriority
+	CategoryIDs []string
+	DueAfter    *time.Time
+	DueBefore   *time.Time
+	OverdueAt   *time.Time
+	// Ready keeps only open tasks whose blocking tasks are all completed.
+	Ready         bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+// TaskQuery describes one page of a task listing. When Cursor is set the page
+// starts right after the task the cursor was issued for and Offset is ignored.
+type TaskQuery struct {
+	Filter TaskFilter
+	Sort   []TaskSort
+	Limit  int
+	Offset int
+	Cursor *TaskCursor
+}
+
+type TaskPage struct {
+	Tasks      []DBTask
+	Total      int
+	NextCursor string
+}
+
+// TaskCursor is the decoded form of an opaque keyset pagination cursor. It
+// holds the sort key values and ID of the last task on the previous page.
+type TaskCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"id"`
+}
+
+// SortKey renders a sort specification such as "-dueDate,title". Cursors
+// remember it so they cannot be replayed against a different ordering.
+func SortKey(sort []TaskSort) string {
+	keys := make([]string, 0, len(sort))
+	for _, s := range sort {
+		if s.Descending {
+			keys = append(keys, "-"+string(s.Field))
+		} else {
+			keys = append(keys, string(s.Field))
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// PriorityRank orders priorities from low to high.
+func PriorityRank(p TaskPriority) int {
+	switch p {
+	case Low:
+		return 1
+	case Medium:
+		return 2
+	case High:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// StatusRank orders statuses along the workflow.
+func StatusRank(s TaskStatus) int {
+	switch s {
+	case Pending:
+		return 1
+	case InProgress:
+		return 2
+	case Completed:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// SortValue returns the value of field for task in the form stored in cursors.
+func (t DBTask) SortValue(field TaskSortField) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return NoDueDate.Format(time.RFC3339Nano)
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	switch field {
+	case SortByCreatedAt:
+		return formatTime(t.CreatedAt)
+	case SortByUpdatedAt:
+		return formatTime(t.UpdatedAt)
+	case SortByDueDate:
+		return formatTime(t.DueDate)
+	case SortByPriority:
+		return strconv.Itoa(PriorityRank(t.Priority))
+	case SortByStatus:
+		return strconv.Itoa(StatusRank(t.Status))
+	default:
+		return t.Title
+	}
+}
+
+// ParseSortValue converts a cursor value back to the typed value that is
+// compared against field.
+func ParseSortValue(field TaskSortField, value string) (any, error) {
+	switch field {
+	case SortByCreatedAt, SortByUpdatedAt, SortByDueDate:
+		return time.Parse(time.RFC3339Nano, value)
+	case SortByPriority, SortByStatus:
+		return strconv.Atoi(value)
+	default:
+		return value, nil
+	}
+}
+
+func NewTaskCursor(sort []TaskSort, task DBTask) *TaskCursor {
+	values := make([]string, 0, len(sort))
+	for _, s := range sort {
+		values = append(values, task.SortValue(s.Field))
+	}
+	return &TaskCursor{Sort: SortKey(sort), Values: values, ID: task.ID}
+}
+
+func (c *TaskCursor) Encode() string {
+	encoded, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodeTaskCursor parses an opaque cursor and checks that it was issued for
+// the same sort order.
+func DecodeTaskCursor(cursor string, sort []TaskSort) (*TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+
+	var decoded TaskCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", err)
+	}
+	if decoded.Sort != SortKey(sort) || len(decoded.Values) != len(sort) || decoded.ID == "" {
+		return nil, fmt.Errorf("cursor does not match sort %q", SortKey(sort))
+	}
+	for i, s := range sort {
+		if _, err := ParseSortValue(s.Field, decoded.Values[i]); err != nil {
+			return nil, fmt.Errorf("malformed cursor value: %w", err)
+		}
+	}
+
+	return &decoded, nil
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.DBAPIKey) (*models.DBAPIKey, error)
+	GetAllForUser(ctx context.Context, user_id string) ([]models.DBAPIKey, error)
+	GetById(ctx context.Context, key_id string) (*models.DBAPIKey, error)
+	GetByHash(ctx context.Context, key_hash string) (*models.DBAPIKey, error)
+	Revoke(ctx context.Context, key_id string) error
+	TouchLastUsed(ctx context.Context, key_id string, used_at time.Time) error
+}
@@ -0,0 +1,179 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+const (
+	createAPIKeyQuery        = "INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scope) VALUES (?, ?, ?, ?, ?, ?)"
+	getAPIKeyAfterCreate     = "SELECT id, created_at FROM api_keys WHERE id = ?"
+	getAllAPIKeysForUser     = "SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at FROM api_keys WHERE user_id = ? ORDER BY created_at, id"
+	getAPIKeyByIDQuery       = "SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at FROM api_keys WHERE id = ?"
+	getAPIKeyByHashQuery     = "SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at FROM api_keys WHERE key_hash = ?"
+	revokeAPIKeyQuery        = "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL"
+	touchAPIKeyLastUsedQuery = "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+)
+
+type apiKeyRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewAPIKeyRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) APIKeyRepository {
+	return &apiKeyRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (a *apiKeyRepository) scanDBAPIKey(rows any) (*models.DBAPIKey, error) {
+	key := &models.DBAPIKey{}
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scope, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	case *sql.Rows:
+		err = r.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scope, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	default:
+		return nil, fmt.Errorf("unsupported row type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (a *apiKeyRepository) validateRowsAffected(result sql.Result, operation string, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return a.errorHandler.HandleDatabaseError(operation, err)
+	}
+	if rowsAffected == 0 {
+		return a.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no api key found with id %s: %w", id, sql.ErrNoRows))
+	}
+	return nil
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, key *models.DBAPIKey) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "creating api key", slog.String("user_id", key.UserID))
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("CreateAPIKey", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, a.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	keyID := uuid.NewString()
+
+	_, err = tx.ExecContext(ctx, createAPIKeyQuery, keyID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("CreateAPIKey", err)
+	}
+
+	var createdKey models.DBAPIKey
+	err = tx.QueryRowContext(ctx, getAPIKeyAfterCreate, keyID).Scan(&createdKey.ID, &createdKey.CreatedAt)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("CreateAPIKey", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("CreateAPIKey", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key created", slog.String("api_key_id", createdKey.ID))
+	return &createdKey, nil
+}
+
+func (a *apiKeyRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting all api keys for a user", slog.String("user_id", user_id))
+
+	rows, err := a.db.QueryContext(ctx, getAllAPIKeysForUser, user_id)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+	}
+	defer rows.Close()
+
+	keys := make([]models.DBAPIKey, 0)
+	for rows.Next() {
+		key, err := a.scanDBAPIKey(rows)
+		if err != nil {
+			return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "got all api keys for user", slog.String("user_id", user_id), slog.Int("count", len(keys)))
+	return keys, nil
+}
+
+func (a *apiKeyRepository) GetById(ctx context.Context, key_id string) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting api key by ID", slog.String("api_key_id", key_id))
+
+	row := a.db.QueryRowContext(ctx, getAPIKeyByIDQuery, key_id)
+	key, err := a.scanDBAPIKey(row)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAPIKeyByID", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "got api key", slog.String("api_key_id", key_id))
+	return key, nil
+}
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, key_hash string) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting api key by hash")
+
+	row := a.db.QueryRowContext(ctx, getAPIKeyByHashQuery, key_hash)
+	key, err := a.scanDBAPIKey(row)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAPIKeyByHash", err)
+	}
+
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "got api key", slog.String("api_key_id", key.ID))
+	return key, nil
+}
+
+func (a *apiKeyRepository) Revoke(ctx context.Context, key_id string) error {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "revoking api key", slog.String("api_key_id", key_id))
+
+	result, err := a.db.ExecContext(ctx, revokeAPIKeyQuery, key_id)
+	if err != nil {
+		return a.errorHandler.HandleDatabaseError("RevokeAPIKey", err)
+	}
+
+	if err := a.validateRowsAffected(result, "RevokeAPIKey", key_id); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key revoked", slog.String("api_key_id", key_id))
+	return nil
+}
+
+func (a *apiKeyRepository) TouchLastUsed(ctx context.Context, key_id string, used_at time.Time) error {
+	result, err := a.db.ExecContext(ctx, touchAPIKeyLastUsedQuery, used_at, key_id)
+	if err != nil {
+		return a.errorHandler.HandleDatabaseError("TouchAPIKeyLastUsed", err)
+	}
+
+	return a.validateRowsAffected(result, "TouchAPIKeyLastUsed", key_id)
+}
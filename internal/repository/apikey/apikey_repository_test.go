@@ -0,0 +1,149 @@
+package apikey_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/apikey"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyRepoTestSuite struct {
+	suite.Suite
+	mySQLContainer *testutils.MySQLContainer
+	ctx            context.Context
+	repository     apikey.APIKeyRepository
+}
+
+func (suite *APIKeyRepoTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	mySQLContainer, err := testutils.CreateMySQLContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.mySQLContainer = mySQLContainer
+	host, _ := mySQLContainer.Container.Host(suite.ctx)
+	port, _ := mySQLContainer.Container.MappedPort(suite.ctx, "3306")
+
+	err = database.Connect("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	db := database.GetDb()
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+	apiKeyRepository := apikey.NewAPIKeyRepository(database.NewConn(db), dbErrorHandler, logger)
+	suite.repository = apiKeyRepository
+}
+
+func (suite *APIKeyRepoTestSuite) TearDownSuite() {
+	if err := suite.mySQLContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating mysql container: %s", err)
+	}
+}
+
+func assertStatus(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, statusCode, appErr.StatusCode)
+	}
+}
+
+func (suite *APIKeyRepoTestSuite) TestAPIKeyLifecycle() {
+	t := suite.T()
+	var keyID string
+
+	t.Run("CreateAPIKey", func(t *testing.T) {
+		created, err := suite.repository.Create(suite.ctx, &models.DBAPIKey{
+			UserID:  "1244ABC",
+			Name:    "ci",
+			Prefix:  "tms_abcdefgh",
+			KeyHash: "c3",
+			Scope:   models.ScopeRead,
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		keyID = created.ID
+
+		if t.Failed() {
+			t.Fatal("CreateAPIKey failed, stopping sequential execution")
+		}
+	})
+
+	t.Run("CreateDuplicateAPIKeyName", func(t *testing.T) {
+		_, err := suite.repository.Create(suite.ctx, &models.DBAPIKey{
+			UserID:  "1244ABC",
+			Name:    "ci",
+			Prefix:  "tms_ijklmnop",
+			KeyHash: "d4",
+			Scope:   models.ScopeReadWrite,
+		})
+		assertStatus(t, err, http.StatusConflict)
+	})
+
+	t.Run("GetAPIKeyByHash", func(t *testing.T) {
+		stored, err := suite.repository.GetByHash(suite.ctx, "c3")
+		assert.NoError(t, err)
+		assert.Equal(t, keyID, stored.ID)
+		assert.Equal(t, models.ScopeRead, stored.Scope)
+		assert.Nil(t, stored.LastUsedAt)
+		assert.Nil(t, stored.RevokedAt)
+	})
+
+	t.Run("TouchAPIKeyLastUsed", func(t *testing.T) {
+		usedAt := time.Now().UTC().Truncate(time.Second)
+		err := suite.repository.TouchLastUsed(suite.ctx, keyID, usedAt)
+		assert.NoError(t, err)
+
+		stored, err := suite.repository.GetById(suite.ctx, keyID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, stored.LastUsedAt) {
+			assert.Equal(t, usedAt, stored.LastUsedAt.UTC())
+		}
+	})
+
+	t.Run("GetAllAPIKeysForUser", func(t *testing.T) {
+		keys, err := suite.repository.GetAllForUser(suite.ctx, "1244ABC")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+	})
+
+	t.Run("RevokeAPIKey", func(t *testing.T) {
+		err := suite.repository.Revoke(suite.ctx, keyID)
+		assert.NoError(t, err)
+
+		stored, err := suite.repository.GetById(suite.ctx, keyID)
+		assert.NoError(t, err)
+		assert.NotNil(t, stored.RevokedAt)
+
+		err = suite.repository.Revoke(suite.ctx, keyID)
+		assertStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("CreateAPIKeyWithRevokedName", func(t *testing.T) {
+		created, err := suite.repository.Create(suite.ctx, &models.DBAPIKey{
+			UserID:  "1244ABC",
+			Name:    "ci",
+			Prefix:  "tms_qrstuvwx",
+			KeyHash: "e5",
+			Scope:   models.ScopeRead,
+		})
+		assert.NoError(t, err, "a revoked key does not reserve its name")
+		assert.NotEqual(t, keyID, created.ID)
+	})
+}
+
+func TestAPIKeyRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepoTestSuite))
+}
@@ -1,11 +1,15 @@
 package category
 
-import "github.com/kjj1998/task-management-system/internal/models"
+import (
+	"context"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
 
 type CategoryRepository interface {
-	GetAllForUser(user_id string) ([]models.DBCategory, error)
-	GetById(category_id string) (*models.DBCategory, error)
-	Create(*models.DBCategory) (*models.DBCategory, error)
-	Update(*models.DBCategory) error
-	Delete(category_id string) error
+	GetAllForUser(ctx context.Context, user_id string) ([]models.DBCategory, error)
+	GetById(ctx context.Context, category_id string) (*models.DBCategory, error)
+	Create(ctx context.Context, category *models.DBCategory) (*models.DBCategory, error)
+	Update(ctx context.Context, category *models.DBCategory) error
+	Delete(ctx context.Context, category_id string) error
 }
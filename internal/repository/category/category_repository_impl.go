@@ -1,12 +1,15 @@
 package category
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
 	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/models"
 )
 
@@ -19,12 +22,12 @@ const (
 )
 
 type categoryRepository struct {
-	db           *sql.DB
+	db           database.Conn
 	errorHandler *errors.DatabaseErrorHandler
 	logger       *slog.Logger
 }
 
-func NewCategoryRepository(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) CategoryRepository {
+func NewCategoryRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) CategoryRepository {
 	return &categoryRepository{
 		db:           db,
 		errorHandler: errorHandler,
@@ -55,56 +58,56 @@ func (c *categoryRepository) validateRowsAffected(result sql.Result, operation s
 		return c.errorHandler.HandleDatabaseError(operation, err)
 	}
 	if rowsAffected == 0 {
-		return c.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no category found with id %s", id))
+		return c.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no category found with id %s: %w", id, sql.ErrNoRows))
 	}
 	return nil
 }
 
-func (c *categoryRepository) Create(category *models.DBCategory) (*models.DBCategory, error) {
-	c.logger.Debug("creating category", slog.String("user_id", category.UserID))
+func (c *categoryRepository) Create(ctx context.Context, category *models.DBCategory) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "creating category", slog.String("user_id", category.UserID))
 
-	tx, err := c.db.Begin()
+	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		c.logger.Error("failed to create category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			c.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, c.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
 	category_id := uuid.NewString()
 
-	_, err = tx.Exec(createCategoryQuery, category_id, category.UserID, category.Name, category.Color)
+	_, err = tx.ExecContext(ctx, createCategoryQuery, category_id, category.UserID, category.Name, category.Color)
 	if err != nil {
-		c.logger.Error("failed to create category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
 	}
 
 	var createdCategory models.DBCategory
-	err = tx.QueryRow(getCategoryAfterCreate, category_id).Scan(&createdCategory.ID, &createdCategory.CreatedAt)
+	err = tx.QueryRowContext(ctx, getCategoryAfterCreate, category_id).Scan(&createdCategory.ID, &createdCategory.CreatedAt)
 	if err != nil {
-		c.logger.Error("failed to create category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		c.logger.Error("failed to create category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
 	}
 
-	c.logger.Info("category created", slog.String("category_id", createdCategory.ID))
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category created", slog.String("category_id", createdCategory.ID))
 	return &createdCategory, nil
 }
 
-func (c *categoryRepository) GetAllForUser(user_id string) ([]models.DBCategory, error) {
-	c.logger.Debug("fetching categories", slog.String("user_id", user_id))
+func (c *categoryRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching categories", slog.String("user_id", user_id))
 
-	rows, err := c.db.Query(getAllCategoriesForUser, user_id)
+	rows, err := c.db.QueryContext(ctx, getAllCategoriesForUser, user_id)
 	if err != nil {
-		c.logger.Error("failed to fetch categories", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch categories", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
 	}
 
@@ -112,53 +115,53 @@ func (c *categoryRepository) GetAllForUser(user_id string) ([]models.DBCategory,
 	for rows.Next() {
 		category, err := c.scanDBCategory(rows)
 		if err != nil {
-			c.logger.Error("failed to scan category", slog.String("error", err.Error()))
+			logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to scan category", slog.String("error", err.Error()))
 			return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
 		}
 		categories = append(categories, *category)
 	}
 
 	if err := rows.Err(); err != nil {
-		c.logger.Error("error reading categories", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "error reading categories", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
 	}
 
-	c.logger.Debug("categories retrieved", slog.String("user_id", user_id), slog.Int("count", len(categories)))
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "categories retrieved", slog.String("user_id", user_id), slog.Int("count", len(categories)))
 	return categories, nil
 }
 
-func (c *categoryRepository) GetById(category_id string) (*models.DBCategory, error) {
-	c.logger.Debug("fetching category", slog.String("category_id", category_id))
+func (c *categoryRepository) GetById(ctx context.Context, category_id string) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching category", slog.String("category_id", category_id))
 
-	row := c.db.QueryRow(getCategoryByIDQuery, category_id)
+	row := c.db.QueryRowContext(ctx, getCategoryByIDQuery, category_id)
 	category, err := c.scanDBCategory(row)
 
 	if err != nil {
-		c.logger.Error("failed to fetch category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch category", slog.String("error", err.Error()))
 		return nil, c.errorHandler.HandleDatabaseError("GetCategoryByID", err)
 	}
 
-	c.logger.Info("category retrieved", slog.String("category_id", category.ID))
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category retrieved", slog.String("category_id", category.ID))
 	return category, nil
 }
 
-func (c *categoryRepository) Update(category *models.DBCategory) error {
-	c.logger.Debug("updating category", slog.String("category_id", category.ID))
+func (c *categoryRepository) Update(ctx context.Context, category *models.DBCategory) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "updating category", slog.String("category_id", category.ID))
 
-	tx, err := c.db.Begin()
+	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		c.logger.Error("failed to update category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
 		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			c.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, c.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
-	result, err := tx.Exec(updateCategoryQuery, category.Name, category.Color, category.ID)
+	result, err := tx.ExecContext(ctx, updateCategoryQuery, category.Name, category.Color, category.ID)
 	if err != nil {
-		c.logger.Error("failed to update category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
 		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
 	}
 
@@ -168,34 +171,34 @@ func (c *categoryRepository) Update(category *models.DBCategory) error {
 
 	err = tx.Commit()
 	if err != nil {
-		c.logger.Error("failed to update category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
 		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
 	}
 
-	c.logger.Info("category updated", slog.String("category_id", category.ID))
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category updated", slog.String("category_id", category.ID))
 	return nil
 }
 
-func (c *categoryRepository) Delete(category_id string) error {
-	c.logger.Debug("deleting category", slog.String("category_id", category_id))
+func (c *categoryRepository) Delete(ctx context.Context, category_id string) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "deleting category", slog.String("category_id", category_id))
 
 	command := "DELETE FROM categories WHERE id = ?"
-	result, err := c.db.Exec(command, category_id)
+	result, err := c.db.ExecContext(ctx, command, category_id)
 	if err != nil {
-		c.logger.Error("failed to delete category", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to delete category", slog.String("error", err.Error()))
 		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.logger.Error("failed to check deletion result", slog.String("error", err.Error()))
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to check deletion result", slog.String("error", err.Error()))
 		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
 	}
 	if rowsAffected == 0 {
-		c.logger.Warn("category not found for deletion", slog.String("category_id", category_id))
-		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
+		logger.FromContext(ctx, c.logger).WarnContext(ctx, "category not found for deletion", slog.String("category_id", category_id))
+		return c.errorHandler.HandleDatabaseError("DeleteCategory", fmt.Errorf("no category found with id %s: %w", category_id, sql.ErrNoRows))
 	}
 
-	c.logger.Info("category deleted", slog.String("category_id", category_id))
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category deleted", slog.String("category_id", category_id))
 	return nil
 }
@@ -3,6 +3,7 @@ package category_test
 import (
 	"context"
 	"log"
+	"net/http"
 	"testing"
 
 	"github.com/kjj1998/task-management-system/internal/database"
@@ -39,7 +40,7 @@ func (suite *CategoryRepoTestSuite) SetupSuite() {
 	suite.Require().NoError(err, "Failed to connect to test database")
 	db := database.GetDb()
 	dbErrorHandler := errors.NewDatabaseErrorHandler()
-	categoryRepository := category.NewCategoryRepository(db, dbErrorHandler, logger)
+	categoryRepository := category.NewCategoryRepository(database.NewConn(db), dbErrorHandler, logger)
 	suite.repository = categoryRepository
 }
 
@@ -59,7 +60,7 @@ func (suite *CategoryRepoTestSuite) TestCategoryRepositoryOperations() {
 			Color:  "#ff0000",
 		}
 
-		createdCategory, err := suite.repository.Create(category)
+		createdCategory, err := suite.repository.Create(suite.ctx, category)
 		assert.NoError(t, err)
 		assert.NotNil(t, createdCategory)
 
@@ -68,8 +69,22 @@ func (suite *CategoryRepoTestSuite) TestCategoryRepositoryOperations() {
 		}
 	})
 
+	t.Run("CreateDuplicateCategory", func(t *testing.T) {
+		category := &models.DBCategory{
+			UserID: "1244ABC",
+			Name:   "urgent",
+			Color:  "#00ff00",
+		}
+
+		_, err := suite.repository.Create(suite.ctx, category)
+
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	})
+
 	t.Run("GetAllCategoriesForUser", func(t *testing.T) {
-		categories, err := suite.repository.GetAllForUser("1244ABC")
+		categories, err := suite.repository.GetAllForUser(suite.ctx, "1244ABC")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, categories)
@@ -83,10 +98,10 @@ func (suite *CategoryRepoTestSuite) TestCategoryRepositoryOperations() {
 			Name:   "Do by today",
 			Color:  "#ffff00",
 		}
-		err := suite.repository.Update(category)
+		err := suite.repository.Update(suite.ctx, category)
 		assert.NoError(t, err)
 
-		updated_category, err := suite.repository.GetById("2345SDSXAS")
+		updated_category, err := suite.repository.GetById(suite.ctx, "2345SDSXAS")
 		assert.NoError(t, err)
 		assert.NotNil(t, updated_category)
 		assert.Equal(t, "Do by today", updated_category.Name)
@@ -94,13 +109,21 @@ func (suite *CategoryRepoTestSuite) TestCategoryRepositoryOperations() {
 	})
 
 	t.Run("DeleteCategory", func(t *testing.T) {
-		err := suite.repository.Delete("2345SDSXAS")
+		err := suite.repository.Delete(suite.ctx, "2345SDSXAS")
 		assert.NoError(t, err)
 
-		_, err = suite.repository.GetById("2345SDSXAS")
+		_, err = suite.repository.GetById(suite.ctx, "2345SDSXAS")
 		expectedErrorMessage := "Resource not found, sql: no rows in result set"
 		assert.Contains(t, err.Error(), expectedErrorMessage)
 	})
+
+	t.Run("DeleteMissingCategory", func(t *testing.T) {
+		err := suite.repository.Delete(suite.ctx, "2345SDSXAS")
+
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
 }
 
 func TestCategoryRepoTestSuite(t *testing.T) {
@@ -0,0 +1,17 @@
+package dependency
+
+import (
+	"context"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+type DependencyRepository interface {
+	Create(ctx context.Context, dependency *models.DBTaskDependency) (*models.DBTaskDependency, error)
+	// GetAllForUser returns the dependencies between the tasks of a user.
+	GetAllForUser(ctx context.Context, user_id string) ([]models.DBTaskDependency, error)
+	// GetForTask returns the dependencies a task takes part in on either
+	// side.
+	GetForTask(ctx context.Context, task_id string) ([]models.DBTaskDependency, error)
+	Delete(ctx context.Context, task_id string, blocked_by_id string) error
+}
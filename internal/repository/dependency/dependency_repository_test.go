@@ -0,0 +1,116 @@
+package dependency_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DependencyRepoTestSuite struct {
+	suite.Suite
+	mySQLContainer *testutils.MySQLContainer
+	ctx            context.Context
+	repository     dependency.DependencyRepository
+	taskRepository task.TaskRepository
+}
+
+func (suite *DependencyRepoTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	mySQLContainer, err := testutils.CreateMySQLContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.mySQLContainer = mySQLContainer
+	host, _ := mySQLContainer.Container.Host(suite.ctx)
+	port, _ := mySQLContainer.Container.MappedPort(suite.ctx, "3306")
+
+	err = database.Connect("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	db := database.GetDb()
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+	suite.repository = dependency.NewDependencyRepository(database.NewConn(db), dbErrorHandler, logger)
+	suite.taskRepository = task.NewTaskRepository(database.NewConn(db), dbErrorHandler, logger)
+}
+
+func (suite *DependencyRepoTestSuite) TearDownSuite() {
+	if err := suite.mySQLContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating mysql container: %s", err)
+	}
+}
+
+func assertStatus(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, statusCode, appErr.StatusCode)
+	}
+}
+
+func (suite *DependencyRepoTestSuite) TestDependencyLifecycle() {
+	t := suite.T()
+
+	mopTask, err := suite.taskRepository.Create(suite.ctx, &models.DBTask{
+		UserID:   "1244ABC",
+		Title:    "Mop Floor",
+		Priority: models.Medium,
+		Status:   models.Pending,
+	})
+	suite.Require().NoError(err)
+
+	t.Run("CreateDependency", func(t *testing.T) {
+		created, err := suite.repository.Create(suite.ctx, &models.DBTaskDependency{TaskID: mopTask.ID, BlockedByID: "DSFDS23423"})
+		assert.NoError(t, err)
+		assert.Equal(t, mopTask.ID, created.TaskID)
+		assert.NotNil(t, created.CreatedAt)
+
+		if t.Failed() {
+			t.Fatal("CreateDependency failed, stopping sequential execution")
+		}
+	})
+
+	t.Run("CreateDuplicateDependency", func(t *testing.T) {
+		_, err := suite.repository.Create(suite.ctx, &models.DBTaskDependency{TaskID: mopTask.ID, BlockedByID: "DSFDS23423"})
+		assertStatus(t, err, http.StatusConflict)
+	})
+
+	t.Run("GetAllDependenciesForUser", func(t *testing.T) {
+		dependencies, err := suite.repository.GetAllForUser(suite.ctx, "1244ABC")
+		assert.NoError(t, err)
+		assert.Len(t, dependencies, 1)
+	})
+
+	t.Run("GetDependenciesForTask", func(t *testing.T) {
+		dependencies, err := suite.repository.GetForTask(suite.ctx, "DSFDS23423")
+		assert.NoError(t, err)
+		if assert.Len(t, dependencies, 1) {
+			assert.Equal(t, mopTask.ID, dependencies[0].TaskID)
+		}
+	})
+
+	t.Run("DeleteDependency", func(t *testing.T) {
+		err := suite.repository.Delete(suite.ctx, mopTask.ID, "DSFDS23423")
+		assert.NoError(t, err)
+
+		err = suite.repository.Delete(suite.ctx, mopTask.ID, "DSFDS23423")
+		assertStatus(t, err, http.StatusNotFound)
+	})
+}
+
+func TestDependencyRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(DependencyRepoTestSuite))
+}
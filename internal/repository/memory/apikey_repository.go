@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/apikey"
+)
+
+type apiKeyRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewAPIKeyRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) apikey.APIKeyRepository {
+	return &apiKeyRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func copyAPIKey(key models.DBAPIKey) models.DBAPIKey {
+	key.LastUsedAt = copyTime(key.LastUsedAt)
+	key.RevokedAt = copyTime(key.RevokedAt)
+	key.CreatedAt = copyTime(key.CreatedAt)
+	return key
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, key *models.DBAPIKey) (*models.DBAPIKey, error) {
+	if err := checkContext(ctx, a.errorHandler, "CreateAPIKey"); err != nil {
+		return nil, err
+	}
+
+	var createdKey models.DBAPIKey
+	err := a.db.write(func(t *tables) error {
+		if _, ok := t.users[key.UserID]; !ok {
+			return foreignKeyViolation("CreateAPIKey", "user_id", key.UserID)
+		}
+		for _, existing := range t.apiKeys {
+			if existing.UserID == key.UserID && existing.RevokedAt == nil && sameKey(existing.Name, key.Name) {
+				return a.errorHandler.HandleUniqueViolation("CreateAPIKey", errors.UniqueUserAPIKeyName, fmt.Errorf("duplicate api key name %s", key.Name))
+			}
+			if existing.KeyHash == key.KeyHash {
+				return a.errorHandler.HandleUniqueViolation("CreateAPIKey", "api_keys.key_hash", fmt.Errorf("duplicate api key hash"))
+			}
+		}
+
+		row := copyAPIKey(*key)
+		row.ID = uuid.NewString()
+		row.LastUsedAt = nil
+		row.RevokedAt = nil
+		row.CreatedAt = a.db.timestamp()
+		t.apiKeys[row.ID] = row
+
+		createdKey = models.DBAPIKey{ID: row.ID, CreatedAt: copyTime(row.CreatedAt)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key created", slog.String("api_key_id", createdKey.ID))
+	return &createdKey, nil
+}
+
+func (a *apiKeyRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBAPIKey, error) {
+	if err := checkContext(ctx, a.errorHandler, "GetAllAPIKeysForUser"); err != nil {
+		return nil, err
+	}
+
+	keys := make([]models.DBAPIKey, 0)
+	_ = a.db.read(func(t *tables) error {
+		for _, row := range t.apiKeys {
+			if row.UserID == user_id {
+				keys = append(keys, copyAPIKey(row))
+			}
+		}
+		return nil
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		if c := keys[i].CreatedAt.Compare(*keys[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+func (a *apiKeyRepository) GetById(ctx context.Context, key_id string) (*models.DBAPIKey, error) {
+	if err := checkContext(ctx, a.errorHandler, "GetAPIKeyByID"); err != nil {
+		return nil, err
+	}
+
+	var found models.DBAPIKey
+	err := a.db.read(func(t *tables) error {
+		row, ok := t.apiKeys[key_id]
+		if !ok {
+			return notFound(a.errorHandler, "GetAPIKeyByID", "api key", key_id)
+		}
+		found = copyAPIKey(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, key_hash string) (*models.DBAPIKey, error) {
+	if err := checkContext(ctx, a.errorHandler, "GetAPIKeyByHash"); err != nil {
+		return nil, err
+	}
+
+	var found *models.DBAPIKey
+	err := a.db.read(func(t *tables) error {
+		for _, row := range t.apiKeys {
+			if row.KeyHash == key_hash {
+				key := copyAPIKey(row)
+				found = &key
+				return nil
+			}
+		}
+		return notFound(a.errorHandler, "GetAPIKeyByHash", "api key", "for hash")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+func (a *apiKeyRepository) Revoke(ctx context.Context, key_id string) error {
+	if err := checkContext(ctx, a.errorHandler, "RevokeAPIKey"); err != nil {
+		return err
+	}
+
+	err := a.db.write(func(t *tables) error {
+		row, ok := t.apiKeys[key_id]
+		if !ok || row.RevokedAt != nil {
+			return notFound(a.errorHandler, "RevokeAPIKey", "api key", key_id)
+		}
+
+		row.RevokedAt = a.db.timestamp()
+		t.apiKeys[key_id] = row
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key revoked", slog.String("api_key_id", key_id))
+	return nil
+}
+
+func (a *apiKeyRepository) TouchLastUsed(ctx context.Context, key_id string, used_at time.Time) error {
+	if err := checkContext(ctx, a.errorHandler, "TouchAPIKeyLastUsed"); err != nil {
+		return err
+	}
+
+	return a.db.write(func(t *tables) error {
+		row, ok := t.apiKeys[key_id]
+		if !ok {
+			return notFound(a.errorHandler, "TouchAPIKeyLastUsed", "api key", key_id)
+		}
+
+		usedAt := used_at.Truncate(time.Second)
+		row.LastUsedAt = &usedAt
+		t.apiKeys[key_id] = row
+		return nil
+	})
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/category"
+)
+
+type categoryRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewCategoryRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) category.CategoryRepository {
+	return &categoryRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (c *categoryRepository) nameTaken(t *tables, category *models.DBCategory, exceptID string) bool {
+	for _, existing := range t.categories {
+		if existing.ID != exceptID && existing.UserID == category.UserID && sameKey(existing.Name, category.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *categoryRepository) Create(ctx context.Context, category *models.DBCategory) (*models.DBCategory, error) {
+	if err := checkContext(ctx, c.errorHandler, "CreateCategory"); err != nil {
+		return nil, err
+	}
+
+	var createdCategory models.DBCategory
+	err := c.db.write(func(t *tables) error {
+		if _, ok := t.users[category.UserID]; !ok {
+			return foreignKeyViolation("CreateCategory", "user_id", category.UserID)
+		}
+		if c.nameTaken(t, category, "") {
+			return c.errorHandler.HandleUniqueViolation("CreateCategory", errors.UniqueUserCategoryKey, fmt.Errorf("duplicate category name %s", category.Name))
+		}
+
+		row := *category
+		row.ID = uuid.NewString()
+		row.CreatedAt = c.db.timestamp()
+		t.categories[row.ID] = row
+
+		createdCategory = models.DBCategory{ID: row.ID, CreatedAt: copyTime(row.CreatedAt)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category created", slog.String("category_id", createdCategory.ID))
+	return &createdCategory, nil
+}
+
+func (c *categoryRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBCategory, error) {
+	if err := checkContext(ctx, c.errorHandler, "GetAllCategoriesForUser"); err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.DBCategory, 0)
+	_ = c.db.read(func(t *tables) error {
+		for _, row := range t.categories {
+			if row.UserID == user_id {
+				row.CreatedAt = copyTime(row.CreatedAt)
+				categories = append(categories, row)
+			}
+		}
+		return nil
+	})
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+
+	return categories, nil
+}
+
+func (c *categoryRepository) GetById(ctx context.Context, category_id string) (*models.DBCategory, error) {
+	if err := checkContext(ctx, c.errorHandler, "GetCategoryByID"); err != nil {
+		return nil, err
+	}
+
+	var found models.DBCategory
+	err := c.db.read(func(t *tables) error {
+		row, ok := t.categories[category_id]
+		if !ok {
+			return notFound(c.errorHandler, "GetCategoryByID", "category", category_id)
+		}
+		found = row
+		found.CreatedAt = copyTime(row.CreatedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+func (c *categoryRepository) Update(ctx context.Context, category *models.DBCategory) error {
+	if err := checkContext(ctx, c.errorHandler, "UpdateCategory"); err != nil {
+		return err
+	}
+
+	err := c.db.write(func(t *tables) error {
+		row, ok := t.categories[category.ID]
+		if !ok {
+			return notFound(c.errorHandler, "UpdateCategory", "category", category.ID)
+		}
+		if c.nameTaken(t, &models.DBCategory{UserID: row.UserID, Name: category.Name}, row.ID) {
+			return c.errorHandler.HandleUniqueViolation("UpdateCategory", errors.UniqueUserCategoryKey, fmt.Errorf("duplicate category name %s", category.Name))
+		}
+
+		row.Name = category.Name
+		row.Color = category.Color
+		t.categories[row.ID] = row
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "updated category", slog.String("category_id", category.ID))
+	return nil
+}
+
+// Delete removes the category. Its tasks become uncategorised, matching the
+// ON DELETE SET NULL foreign key of the MySQL schema.
+func (c *categoryRepository) Delete(ctx context.Context, category_id string) error {
+	if err := checkContext(ctx, c.errorHandler, "DeleteCategory"); err != nil {
+		return err
+	}
+
+	err := c.db.write(func(t *tables) error {
+		if _, ok := t.categories[category_id]; !ok {
+			return notFound(c.errorHandler, "DeleteCategory", "category", category_id)
+		}
+
+		delete(t.categories, category_id)
+		for taskID, task := range t.tasks {
+			if task.CategoryID == category_id {
+				task.CategoryID = ""
+				t.tasks[taskID] = task
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "deleted category", slog.String("category_id", category_id))
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package memory implements the repository interfaces on top of in-memory
+// tables. It mirrors the MySQL schema, including its unique keys and foreign
+// key actions, so it can stand in for MySQL in tests and in demo mode.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+type tables struct {
+	users         map[string]models.DBUser
+	categories    map[string]models.DBCategory
+	tasks         map[string]models.DBTask
+	refreshTokens map[string]models.DBRefreshToken
+	apiKeys       map[string]models.DBAPIKey
+	dependencies  map[dependencyKey]models.DBTaskDependency
+}
+
+// Database holds the rows of every table. It is safe for concurrent use.
+type Database struct {
+	mu     sync.RWMutex
+	tables *tables
+	now    func() time.Time
+}
+
+func NewDatabase() *Database {
+	return &Database{
+		tables: &tables{
+			users:         make(map[string]models.DBUser),
+			categories:    make(map[string]models.DBCategory),
+			tasks:         make(map[string]models.DBTask),
+			refreshTokens: make(map[string]models.DBRefreshToken),
+			apiKeys:       make(map[string]models.DBAPIKey),
+			dependencies:  make(map[dependencyKey]models.DBTaskDependency),
+		},
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Atomically runs fn against a copy of the database and keeps its changes
+// only if fn returns nil. Other callers are blocked until fn returns, so a
+// unit of work never observes, or is observed in, a partial state.
+func (d *Database) Atomically(fn func(tx *Database) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tx := &Database{tables: d.tables.clone(), now: d.now}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	d.tables = tx.tables
+	return nil
+}
+
+func (d *Database) read(fn func(t *tables) error) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return fn(d.tables)
+}
+
+func (d *Database) write(fn func(t *tables) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return fn(d.tables)
+}
+
+// timestamp returns the current time at the one second resolution of a
+// MySQL TIMESTAMP column.
+func (d *Database) timestamp() *time.Time {
+	now := d.now().Truncate(time.Second)
+	return &now
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		users:         cloneMap(t.users),
+		categories:    cloneMap(t.categories),
+		tasks:         cloneMap(t.tasks),
+		refreshTokens: cloneMap(t.refreshTokens),
+		apiKeys:       cloneMap(t.apiKeys),
+		dependencies:  cloneMap(t.dependencies),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	cloned := make(map[K]V, len(m))
+	for k, v := range m {
+		cloned[k] = v
+	}
+	return cloned
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// sameKey compares values of a unique key the way MySQL's default
+// case-insensitive collation does.
+func sameKey(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// checkContext fails operations whose request was cancelled or timed out,
+// like a query run with an expired context would.
+func checkContext(ctx context.Context, errorHandler *errors.DatabaseErrorHandler, operation string) error {
+	if err := ctx.Err(); err != nil {
+		return errorHandler.HandleDatabaseError(operation, err)
+	}
+	return nil
+}
+
+func notFound(errorHandler *errors.DatabaseErrorHandler, operation string, resource string, id string) error {
+	return errorHandler.HandleDatabaseError(operation, fmt.Errorf("no %s found with id %s: %w", resource, id, sql.ErrNoRows))
+}
+
+// foreignKeyViolation reports a write whose column references a row that
+// does not exist, as the SQL backends do when a foreign key fails.
+func foreignKeyViolation(operation string, column string, id string) error {
+	appErr := errors.NewInvalidReferenceError("A referenced resource does not exist", fmt.Errorf("%s: no row for %s %s", operation, column, id))
+	appErr.Operation = operation
+	return appErr
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+)
+
+// dependencyKey is the primary key of the task_dependencies table.
+type dependencyKey struct {
+	taskID      string
+	blockedByID string
+}
+
+type dependencyRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewDependencyRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) dependency.DependencyRepository {
+	return &dependencyRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func copyDependency(dependency models.DBTaskDependency) models.DBTaskDependency {
+	dependency.CreatedAt = copyTime(dependency.CreatedAt)
+	return dependency
+}
+
+// deleteDependenciesOf removes the dependencies a deleted task took part in,
+// matching the ON DELETE CASCADE foreign keys of the MySQL schema.
+func (t *tables) deleteDependenciesOf(task_id string) {
+	for key := range t.dependencies {
+		if key.taskID == task_id || key.blockedByID == task_id {
+			delete(t.dependencies, key)
+		}
+	}
+}
+
+// isBlocked reports whether any task that task_id is blocked by is still
+// open.
+func (t *tables) isBlocked(task_id string) bool {
+	for key := range t.dependencies {
+		if key.taskID == task_id && t.tasks[key.blockedByID].Status != models.Completed {
+			return true
+		}
+	}
+	return false
+}
+
+func sortDependencies(dependencies []models.DBTaskDependency) {
+	sort.Slice(dependencies, func(i, j int) bool {
+		if c := dependencies[i].CreatedAt.Compare(*dependencies[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		if dependencies[i].TaskID != dependencies[j].TaskID {
+			return dependencies[i].TaskID < dependencies[j].TaskID
+		}
+		return dependencies[i].BlockedByID < dependencies[j].BlockedByID
+	})
+}
+
+func (d *dependencyRepository) Create(ctx context.Context, dependency *models.DBTaskDependency) (*models.DBTaskDependency, error) {
+	if err := checkContext(ctx, d.errorHandler, "CreateDependency"); err != nil {
+		return nil, err
+	}
+
+	var createdDependency models.DBTaskDependency
+	err := d.db.write(func(t *tables) error {
+		if _, ok := t.tasks[dependency.TaskID]; !ok {
+			return foreignKeyViolation("CreateDependency", "task_id", dependency.TaskID)
+		}
+		if _, ok := t.tasks[dependency.BlockedByID]; !ok {
+			return foreignKeyViolation("CreateDependency", "blocked_by_id", dependency.BlockedByID)
+		}
+
+		key := dependencyKey{taskID: dependency.TaskID, blockedByID: dependency.BlockedByID}
+		if _, ok := t.dependencies[key]; ok {
+			return d.errorHandler.HandleUniqueViolation("CreateDependency", errors.UniqueTaskDependency, fmt.Errorf("duplicate dependency of %s on %s", dependency.TaskID, dependency.BlockedByID))
+		}
+
+		row := models.DBTaskDependency{TaskID: dependency.TaskID, BlockedByID: dependency.BlockedByID, CreatedAt: d.db.timestamp()}
+		t.dependencies[key] = row
+
+		createdDependency = copyDependency(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "task dependency created", slog.String("task_id", dependency.TaskID), slog.String("blocked_by_id", dependency.BlockedByID))
+	return &createdDependency, nil
+}
+
+func (d *dependencyRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTaskDependency, error) {
+	if err := checkContext(ctx, d.errorHandler, "GetAllDependenciesForUser"); err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]models.DBTaskDependency, 0)
+	_ = d.db.read(func(t *tables) error {
+		for key, row := range t.dependencies {
+			if t.tasks[key.taskID].UserID == user_id {
+				dependencies = append(dependencies, copyDependency(row))
+			}
+		}
+		return nil
+	})
+	sortDependencies(dependencies)
+
+	return dependencies, nil
+}
+
+func (d *dependencyRepository) GetForTask(ctx context.Context, task_id string) ([]models.DBTaskDependency, error) {
+	if err := checkContext(ctx, d.errorHandler, "GetDependenciesForTask"); err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]models.DBTaskDependency, 0)
+	_ = d.db.read(func(t *tables) error {
+		for key, row := range t.dependencies {
+			if key.taskID == task_id || key.blockedByID == task_id {
+				dependencies = append(dependencies, copyDependency(row))
+			}
+		}
+		return nil
+	})
+	sortDependencies(dependencies)
+
+	return dependencies, nil
+}
+
+func (d *dependencyRepository) Delete(ctx context.Context, task_id string, blocked_by_id string) error {
+	if err := checkContext(ctx, d.errorHandler, "DeleteDependency"); err != nil {
+		return err
+	}
+
+	err := d.db.write(func(t *tables) error {
+		key := dependencyKey{taskID: task_id, blockedByID: blocked_by_id}
+		if _, ok := t.dependencies[key]; !ok {
+			return notFound(d.errorHandler, "DeleteDependency", "dependency", task_id+" on "+blocked_by_id)
+		}
+		delete(t.dependencies, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "task dependency deleted", slog.String("task_id", task_id), slog.String("blocked_by_id", blocked_by_id))
+	return nil
+}
@@ -0,0 +1,100 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepositories(db *memory.Database) repositorytest.Repositories {
+	logger := logger.NewLogger("test")
+	errorHandler := errors.NewDatabaseErrorHandler()
+
+	return repositorytest.Repositories{
+		Users:        memory.NewUserRepository(db, errorHandler, logger),
+		Categories:   memory.NewCategoryRepository(db, errorHandler, logger),
+		Tasks:        memory.NewTaskRepository(db, errorHandler, logger),
+		Dependencies: memory.NewDependencyRepository(db, errorHandler, logger),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, newRepositories(memory.NewDatabase()))
+}
+
+func TestAtomically(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDatabase()
+	repos := newRepositories(db)
+
+	user, err := repos.Users.Create(ctx, &models.DBUser{Email: "john@email.com", FirstName: "John", LastName: "Doe"})
+	require.NoError(t, err)
+
+	var categoryID string
+	err = db.Atomically(func(tx *memory.Database) error {
+		created, err := newRepositories(tx).Categories.Create(ctx, &models.DBCategory{UserID: user.ID, Name: "routine", Color: "#007bff"})
+		if err != nil {
+			return err
+		}
+		categoryID = created.ID
+		return fmt.Errorf("abort unit of work")
+	})
+	assert.EqualError(t, err, "abort unit of work")
+
+	_, err = repos.Categories.GetById(ctx, categoryID)
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	}
+
+	err = db.Atomically(func(tx *memory.Database) error {
+		created, err := newRepositories(tx).Categories.Create(ctx, &models.DBCategory{UserID: user.ID, Name: "routine", Color: "#007bff"})
+		if err != nil {
+			return err
+		}
+		categoryID = created.ID
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = repos.Categories.GetById(ctx, categoryID)
+	assert.NoError(t, err)
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	repos := newRepositories(memory.NewDatabase())
+
+	user, err := repos.Users.Create(ctx, &models.DBUser{Email: "john@email.com", FirstName: "John", LastName: "Doe"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	conflicts := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repos.Categories.Create(ctx, &models.DBCategory{UserID: user.ID, Name: "routine", Color: "#007bff"})
+			if err != nil {
+				conflicts <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(conflicts)
+
+	assert.Len(t, conflicts, 19, "exactly one concurrent create of the same name succeeds")
+	categories, err := repos.Categories.GetAllForUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 1)
+}
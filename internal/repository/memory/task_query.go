@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+// matchesFilter reports whether task passes every condition of filter. As in
+// SQL, a missing due date never satisfies a due date bound.
+func matchesFilter(t *tables, task models.DBTask, filter models.TaskFilter) bool {
+	if task.UserID != filter.UserID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, task.Status) {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !slices.Contains(filter.Priorities, task.Priority) {
+		return false
+	}
+	if len(filter.CategoryIDs) > 0 && (task.CategoryID == "" || !slices.Contains(filter.CategoryIDs, task.CategoryID)) {
+		return false
+	}
+
+	inRange := func(value *time.Time, after *time.Time, before *time.Time) bool {
+		if after == nil && before == nil {
+			return true
+		}
+		if value == nil {
+			return false
+		}
+		if after != nil && value.Before(*after) {
+			return false
+		}
+		return before == nil || value.Before(*before)
+	}
+	if !inRange(task.DueDate, filter.DueAfter, filter.DueBefore) ||
+		!inRange(task.CreatedAt, filter.CreatedAfter, filter.CreatedBefore) ||
+		!inRange(task.UpdatedAt, filter.UpdatedAfter, filter.UpdatedBefore) {
+		return false
+	}
+
+	if filter.OverdueAt != nil {
+		if task.DueDate == nil || !task.DueDate.Before(*filter.OverdueAt) || task.Status == models.Completed {
+			return false
+		}
+	}
+
+	if filter.Ready {
+		if task.Status == models.Completed || t.isBlocked(task.ID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortKey returns the value of field that tasks are ordered by, using the
+// same ranks and missing-date stand-in as the SQL sort expressions.
+func sortKey(task models.DBTask, field models.TaskSortField) any {
+	orNoDueDate := func(t *time.Time) time.Time {
+		if t == nil {
+			return models.NoDueDate
+		}
+		return t.UTC()
+	}
+
+	switch field {
+	case models.SortByCreatedAt:
+		return orNoDueDate(task.CreatedAt)
+	case models.SortByUpdatedAt:
+		return orNoDueDate(task.UpdatedAt)
+	case models.SortByDueDate:
+		return orNoDueDate(task.DueDate)
+	case models.SortByPriority:
+		return models.PriorityRank(task.Priority)
+	case models.SortByStatus:
+		return models.StatusRank(task.Status)
+	default:
+		return task.Title
+	}
+}
+
+func compareKeys(a, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int:
+		return a - b.(int)
+	default:
+		// Titles compare case-insensitively, like MySQL's default collation.
+		return strings.Compare(strings.ToLower(a.(string)), strings.ToLower(b.(string)))
+	}
+}
+
+// compareTasks orders task against the sort key values and ID of another
+// task, breaking ties by ID like the SQL listing does.
+func compareTasks(sorts []models.TaskSort, task models.DBTask, keys []any, id string) int {
+	for i, s := range sorts {
+		c := compareKeys(sortKey(task, s.Field), keys[i])
+		if s.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(task.ID, id)
+}
+
+func taskKeys(sorts []models.TaskSort, task models.DBTask) []any {
+	keys := make([]any, 0, len(sorts))
+	for _, s := range sorts {
+		keys = append(keys, sortKey(task, s.Field))
+	}
+	return keys
+}
+
+// paginateTasks sorts the tasks that matched a query's filter and cuts out
+// the requested page.
+func paginateTasks(tasks []models.DBTask, query models.TaskQuery) (*models.TaskPage, error) {
+	total := len(tasks)
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return compareTasks(query.Sort, tasks[i], taskKeys(query.Sort, tasks[j]), tasks[j].ID) < 0
+	})
+
+	start := 0
+	if query.Cursor != nil {
+		keys := make([]any, 0, len(query.Sort))
+		for i, s := range query.Sort {
+			value, err := models.ParseSortValue(s.Field, query.Cursor.Values[i])
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, value)
+		}
+		start = sort.Search(len(tasks), func(i int) bool {
+			return compareTasks(query.Sort, tasks[i], keys, query.Cursor.ID) > 0
+		})
+	} else if query.Offset > 0 {
+		start = min(query.Offset, len(tasks))
+	}
+
+	pageTasks := tasks[start:]
+	page := &models.TaskPage{Tasks: pageTasks, Total: total}
+	if len(pageTasks) > query.Limit {
+		page.Tasks = pageTasks[:query.Limit]
+		page.NextCursor = models.NewTaskCursor(query.Sort, page.Tasks[query.Limit-1]).Encode()
+	}
+	if page.Tasks == nil {
+		page.Tasks = make([]models.DBTask, 0)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+)
+
+type taskRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewTaskRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) task.TaskRepository {
+	return &taskRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func copyTask(task models.DBTask) models.DBTask {
+	task.DueDate = copyTime(task.DueDate)
+	task.CompletedAt = copyTime(task.CompletedAt)
+	task.CreatedAt = copyTime(task.CreatedAt)
+	task.UpdatedAt = copyTime(task.UpdatedAt)
+	task.Progress = nil
+	return task
+}
+
+func (r *taskRepository) checkForeignKeys(t *tables, operation string, task *models.DBTask) error {
+	if _, ok := t.users[task.UserID]; !ok {
+		return foreignKeyViolation(operation, "user_id", task.UserID)
+	}
+	if task.CategoryID != "" {
+		if _, ok := t.categories[task.CategoryID]; !ok {
+			return foreignKeyViolation(operation, "category_id", task.CategoryID)
+		}
+	}
+	if task.ParentID != "" {
+		if _, ok := t.tasks[task.ParentID]; !ok {
+			return foreignKeyViolation(operation, "parent_id", task.ParentID)
+		}
+	}
+	return nil
+}
+
+func (r *taskRepository) Create(ctx context.Context, task *models.DBTask) (*models.DBTask, error) {
+	if err := checkContext(ctx, r.errorHandler, "CreateTask"); err != nil {
+		return nil, err
+	}
+
+	var createdTask models.DBTask
+	err := r.db.write(func(t *tables) error {
+		if err := r.checkForeignKeys(t, "CreateTask", task); err != nil {
+			return err
+		}
+
+		row := copyTask(*task)
+		row.ID = uuid.NewString()
+		row.CreatedAt = r.db.timestamp()
+		row.UpdatedAt = copyTime(row.CreatedAt)
+		t.tasks[row.ID] = row
+
+		createdTask = models.DBTask{ID: row.ID, CreatedAt: copyTime(row.CreatedAt)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "task created", slog.String("task_id", createdTask.ID))
+	return &createdTask, nil
+}
+
+func (r *taskRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTask, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetAllTasksForUser"); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.DBTask, 0)
+	_ = r.db.read(func(t *tables) error {
+		for _, row := range t.tasks {
+			if row.UserID == user_id {
+				tasks = append(tasks, copyTask(row))
+			}
+		}
+		return nil
+	})
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks, nil
+}
+
+func (r *taskRepository) List(ctx context.Context, query models.TaskQuery) (*models.TaskPage, error) {
+	if err := checkContext(ctx, r.errorHandler, "ListTasks"); err != nil {
+		return nil, err
+	}
+
+	var matched []models.DBTask
+	_ = r.db.read(func(t *tables) error {
+		for _, row := range t.tasks {
+			if matchesFilter(t, row, query.Filter) {
+				matched = append(matched, copyTask(row))
+			}
+		}
+		return nil
+	})
+
+	page, err := paginateTasks(matched, query)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "listed tasks for user", slog.String("user_id", query.Filter.UserID), slog.Int("count", len(page.Tasks)), slog.Int("total", page.Total))
+	return page, nil
+}
+
+func (r *taskRepository) GetById(ctx context.Context, task_id string) (*models.DBTask, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetTaskByID"); err != nil {
+		return nil, err
+	}
+
+	var found models.DBTask
+	err := r.db.read(func(t *tables) error {
+		row, ok := t.tasks[task_id]
+		if !ok {
+			return notFound(r.errorHandler, "GetTaskByID", "task", task_id)
+		}
+		found = copyTask(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+func (r *taskRepository) GetByIds(ctx context.Context, task_ids []string) ([]models.DBTask, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetTasksByIDs"); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.DBTask, 0, len(task_ids))
+	_ = r.db.read(func(t *tables) error {
+		seen := make(map[string]bool, len(task_ids))
+		for _, id := range task_ids {
+			if row, ok := t.tasks[id]; ok && !seen[id] {
+				seen[id] = true
+				tasks = append(tasks, copyTask(row))
+			}
+		}
+		return nil
+	})
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(*tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(*tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+
+	return tasks, nil
+}
+
+func (r *taskRepository) Update(ctx context.Context, task *models.DBTask) error {
+	if err := checkContext(ctx, r.errorHandler, "UpdateTask"); err != nil {
+		return err
+	}
+
+	err := r.db.write(func(t *tables) error {
+		row, ok := t.tasks[task.ID]
+		if !ok {
+			return notFound(r.errorHandler, "UpdateTask", "task", task.ID)
+		}
+
+		row.CategoryID = task.CategoryID
+		row.ParentID = task.ParentID
+		row.Title = task.Title
+		row.Description = task.Description
+		row.Priority = task.Priority
+		row.Status = task.Status
+		row.DueDate = copyTime(task.DueDate)
+		row.CompletedAt = copyTime(task.CompletedAt)
+		row.UpdatedAt = copyTime(task.UpdatedAt)
+		row.AutoComplete = task.AutoComplete
+		if err := r.checkForeignKeys(t, "UpdateTask", &row); err != nil {
+			return err
+		}
+
+		t.tasks[row.ID] = row
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "updated task", slog.String("task_id", task.ID))
+	return nil
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id string) error {
+	if err := checkContext(ctx, r.errorHandler, "DeleteTask"); err != nil {
+		return err
+	}
+
+	err := r.db.write(func(t *tables) error {
+		if _, ok := t.tasks[id]; !ok {
+			return notFound(r.errorHandler, "DeleteTask", "task", id)
+		}
+		delete(t.tasks, id)
+		t.deleteDependenciesOf(id)
+
+		// Subtasks are detached like ON DELETE SET NULL does.
+		for taskID, task := range t.tasks {
+			if task.ParentID == id {
+				task.ParentID = ""
+				t.tasks[taskID] = task
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "deleted task", slog.String("task_id", id))
+	return nil
+}
+
+func (r *taskRepository) GetSubtree(ctx context.Context, task_id string) ([]models.DBTask, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetTaskSubtree"); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.DBTask, 0)
+	_ = r.db.read(func(t *tables) error {
+		// Tasks already seen are skipped, so the walk ends even if the
+		// hierarchy has become a cycle.
+		seen := map[string]bool{task_id: true}
+		level := []string{task_id}
+		for len(level) > 0 {
+			parents := make(map[string]bool, len(level))
+			for _, id := range level {
+				parents[id] = true
+			}
+
+			var children []models.DBTask
+			for _, row := range t.tasks {
+				if parents[row.ParentID] && !seen[row.ID] {
+					seen[row.ID] = true
+					children = append(children, copyTask(row))
+				}
+			}
+			sort.Slice(children, func(i, j int) bool {
+				if !children[i].CreatedAt.Equal(*children[j].CreatedAt) {
+					return children[i].CreatedAt.Before(*children[j].CreatedAt)
+				}
+				return children[i].ID < children[j].ID
+			})
+
+			level = level[:0]
+			for _, child := range children {
+				tasks = append(tasks, child)
+				level = append(level, child.ID)
+			}
+		}
+		return nil
+	})
+
+	return tasks, nil
+}
+
+func (r *taskRepository) GetSubtaskProgress(ctx context.Context, parent_ids []string) (map[string]models.SubtaskProgress, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetSubtaskProgress"); err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string]bool, len(parent_ids))
+	for _, id := range parent_ids {
+		parents[id] = true
+	}
+
+	progress := make(map[string]models.SubtaskProgress)
+	_ = r.db.read(func(t *tables) error {
+		for _, row := range t.tasks {
+			if row.ParentID == "" || !parents[row.ParentID] {
+				continue
+			}
+			counts := progress[row.ParentID]
+			counts.Total++
+			if row.Status == models.Completed {
+				counts.Completed++
+			}
+			progress[row.ParentID] = counts
+		}
+		return nil
+	})
+
+	return progress, nil
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/token"
+)
+
+type refreshTokenRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewRefreshTokenRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) token.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.DBRefreshToken) (*models.DBRefreshToken, error) {
+	if err := checkContext(ctx, r.errorHandler, "CreateRefreshToken"); err != nil {
+		return nil, err
+	}
+
+	var createdToken models.DBRefreshToken
+	err := r.db.write(func(t *tables) error {
+		if _, ok := t.users[token.UserID]; !ok {
+			return foreignKeyViolation("CreateRefreshToken", "user_id", token.UserID)
+		}
+		for _, existing := range t.refreshTokens {
+			if existing.TokenHash == token.TokenHash {
+				return r.errorHandler.HandleUniqueViolation("CreateRefreshToken", "refresh_tokens.token_hash", fmt.Errorf("duplicate refresh token hash"))
+			}
+		}
+
+		row := *token
+		row.ID = uuid.NewString()
+		row.RevokedAt = nil
+		row.ReplacedBy = ""
+		row.CreatedAt = r.db.timestamp()
+		t.refreshTokens[row.ID] = row
+
+		createdToken = models.DBRefreshToken{ID: row.ID, CreatedAt: copyTime(row.CreatedAt)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token created", slog.String("token_id", createdToken.ID))
+	return &createdToken, nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, token_hash string) (*models.DBRefreshToken, error) {
+	if err := checkContext(ctx, r.errorHandler, "GetRefreshTokenByHash"); err != nil {
+		return nil, err
+	}
+
+	var found *models.DBRefreshToken
+	err := r.db.read(func(t *tables) error {
+		for _, row := range t.refreshTokens {
+			if row.TokenHash == token_hash {
+				row.RevokedAt = copyTime(row.RevokedAt)
+				row.CreatedAt = copyTime(row.CreatedAt)
+				found = &row
+				return nil
+			}
+		}
+		return notFound(r.errorHandler, "GetRefreshTokenByHash", "refresh token", "for hash")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// Revoke marks a token as used. Like the MySQL implementation it fails with a
+// not found error when the token is missing or was already revoked.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, token_id string, replaced_by string) error {
+	if err := checkContext(ctx, r.errorHandler, "RevokeRefreshToken"); err != nil {
+		return err
+	}
+
+	err := r.db.write(func(t *tables) error {
+		row, ok := t.refreshTokens[token_id]
+		if !ok || row.RevokedAt != nil {
+			return notFound(r.errorHandler, "RevokeRefreshToken", "active refresh token", token_id)
+		}
+
+		row.RevokedAt = r.db.timestamp()
+		row.ReplacedBy = replaced_by
+		t.refreshTokens[token_id] = row
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token revoked", slog.String("token_id", token_id))
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, family_id string) error {
+	if err := checkContext(ctx, r.errorHandler, "RevokeRefreshTokenFamily"); err != nil {
+		return err
+	}
+
+	_ = r.db.write(func(t *tables) error {
+		now := r.db.timestamp()
+		for id, row := range t.refreshTokens {
+			if row.FamilyID == family_id && row.RevokedAt == nil {
+				row.RevokedAt = copyTime(now)
+				t.refreshTokens[id] = row
+			}
+		}
+		return nil
+	})
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token family revoked", slog.String("family_id", family_id))
+	return nil
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/user"
+)
+
+type userRepository struct {
+	db           *Database
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewUserRepository(db *Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) user.UserRepository {
+	return &userRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (u *userRepository) emailTaken(t *tables, email string, exceptID string) bool {
+	for _, existing := range t.users {
+		if existing.ID != exceptID && sameKey(existing.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *userRepository) Create(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	if err := checkContext(ctx, u.errorHandler, "CreateUser"); err != nil {
+		return nil, err
+	}
+
+	var createdUser models.DBUser
+	err := u.db.write(func(t *tables) error {
+		if u.emailTaken(t, user.Email, "") {
+			return u.errorHandler.HandleUniqueViolation("CreateUser", errors.UniqueUserEmailKey, fmt.Errorf("duplicate email %s", user.Email))
+		}
+
+		now := u.db.timestamp()
+		row := *user
+		row.ID = uuid.NewString()
+		row.CreatedAt = *now
+		row.UpdatedAt = *now
+		t.users[row.ID] = row
+
+		createdUser = models.DBUser{ID: row.ID, CreatedAt: row.CreatedAt}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "created user", slog.String("user_id", createdUser.ID))
+	return &createdUser, nil
+}
+
+func (u *userRepository) GetById(ctx context.Context, id string) (*models.DBUser, error) {
+	if err := checkContext(ctx, u.errorHandler, "GetUserByID"); err != nil {
+		return nil, err
+	}
+
+	var found models.DBUser
+	err := u.db.read(func(t *tables) error {
+		row, ok := t.users[id]
+		if !ok {
+			return notFound(u.errorHandler, "GetUserByID", "user", id)
+		}
+		found = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &found, nil
+}
+
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (*models.DBUser, error) {
+	if err := checkContext(ctx, u.errorHandler, "GetUserByEmail"); err != nil {
+		return nil, err
+	}
+
+	var found *models.DBUser
+	err := u.db.read(func(t *tables) error {
+		for _, row := range t.users {
+			if sameKey(row.Email, email) {
+				found = &row
+				return nil
+			}
+		}
+		return notFound(u.errorHandler, "GetUserByEmail", "user", email)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// Delete removes the user together with everything they own, matching the
+// ON DELETE CASCADE foreign keys of the MySQL schema.
+func (u *userRepository) Delete(ctx context.Context, id string) error {
+	if err := checkContext(ctx, u.errorHandler, "DeleteUser"); err != nil {
+		return err
+	}
+
+	err := u.db.write(func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return notFound(u.errorHandler, "DeleteUser", "user", id)
+		}
+
+		delete(t.users, id)
+		for taskID, task := range t.tasks {
+			if task.UserID == id {
+				delete(t.tasks, taskID)
+				t.deleteDependenciesOf(taskID)
+			}
+		}
+		for categoryID, category := range t.categories {
+			if category.UserID == id {
+				delete(t.categories, categoryID)
+			}
+		}
+		for tokenID, token := range t.refreshTokens {
+			if token.UserID == id {
+				delete(t.refreshTokens, tokenID)
+			}
+		}
+		for keyID, key := range t.apiKeys {
+			if key.UserID == id {
+				delete(t.apiKeys, keyID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "deleted user", slog.String("user_id", id))
+	return nil
+}
+
+func (u *userRepository) Update(ctx context.Context, user *models.DBUser) error {
+	if err := checkContext(ctx, u.errorHandler, "UpdateUser"); err != nil {
+		return err
+	}
+
+	err := u.db.write(func(t *tables) error {
+		row, ok := t.users[user.ID]
+		if !ok {
+			return notFound(u.errorHandler, "UpdateUser", "user", user.ID)
+		}
+		if u.emailTaken(t, user.Email, user.ID) {
+			return u.errorHandler.HandleUniqueViolation("UpdateUser", errors.UniqueUserEmailKey, fmt.Errorf("duplicate email %s", user.Email))
+		}
+
+		row.Email = user.Email
+		row.FirstName = user.FirstName
+		row.LastName = user.LastName
+		row.UpdatedAt = *u.db.timestamp()
+		t.users[row.ID] = row
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "updated user", slog.String("user_id", user.ID))
+	return nil
+}
+
+// Lock only checks that the user exists: units of work already run one at
+// a time.
+func (u *userRepository) Lock(ctx context.Context, id string) error {
+	if err := checkContext(ctx, u.errorHandler, "LockUser"); err != nil {
+		return err
+	}
+
+	return u.db.read(func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return notFound(u.errorHandler, "LockUser", "user", id)
+		}
+		return nil
+	})
+}
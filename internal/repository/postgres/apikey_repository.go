@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/apikey"
+)
+
+const (
+	apiKeyColumns            = "id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at"
+	createAPIKeyQuery        = "INSERT INTO api_keys (user_id, name, prefix, key_hash, scope) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at"
+	getAllAPIKeysForUser     = "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id = $1 ORDER BY created_at, id"
+	getAPIKeyByIDQuery       = "SELECT " + apiKeyColumns + " FROM api_keys WHERE id = $1"
+	getAPIKeyByHashQuery     = "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+	revokeAPIKeyQuery        = "UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL"
+	touchAPIKeyLastUsedQuery = "UPDATE api_keys SET last_used_at = $1 WHERE id = $2"
+)
+
+type apiKeyRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewAPIKeyRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) apikey.APIKeyRepository {
+	return &apiKeyRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (a *apiKeyRepository) scanDBAPIKey(rows any) (*models.DBAPIKey, error) {
+	key := &models.DBAPIKey{}
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scope, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	case *sql.Rows:
+		err = r.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scope, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (a *apiKeyRepository) Create(ctx context.Context, key *models.DBAPIKey) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "creating api key", slog.String("user_id", key.UserID))
+
+	var createdKey models.DBAPIKey
+	err := a.db.QueryRowContext(ctx, createAPIKeyQuery, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scope).Scan(&createdKey.ID, &createdKey.CreatedAt)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("CreateAPIKey", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key created", slog.String("api_key_id", createdKey.ID))
+	return &createdKey, nil
+}
+
+func (a *apiKeyRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting all api keys for a user", slog.String("user_id", user_id))
+
+	keys := make([]models.DBAPIKey, 0)
+	if !validID(user_id) {
+		return keys, nil
+	}
+
+	rows, err := a.db.QueryContext(ctx, getAllAPIKeysForUser, user_id)
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := a.scanDBAPIKey(rows)
+		if err != nil {
+			return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAllAPIKeysForUser", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "got all api keys for user", slog.String("user_id", user_id), slog.Int("count", len(keys)))
+	return keys, nil
+}
+
+func (a *apiKeyRepository) GetById(ctx context.Context, key_id string) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting api key by ID", slog.String("api_key_id", key_id))
+
+	if err := checkID(a.errorHandler, "GetAPIKeyByID", "api key", key_id); err != nil {
+		return nil, err
+	}
+
+	key, err := a.scanDBAPIKey(a.db.QueryRowContext(ctx, getAPIKeyByIDQuery, key_id))
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAPIKeyByID", err)
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "got api key", slog.String("api_key_id", key_id))
+	return key, nil
+}
+
+func (a *apiKeyRepository) GetByHash(ctx context.Context, key_hash string) (*models.DBAPIKey, error) {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "getting api key by hash")
+
+	key, err := a.scanDBAPIKey(a.db.QueryRowContext(ctx, getAPIKeyByHashQuery, key_hash))
+	if err != nil {
+		return nil, a.errorHandler.HandleDatabaseError("GetAPIKeyByHash", err)
+	}
+
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "got api key", slog.String("api_key_id", key.ID))
+	return key, nil
+}
+
+func (a *apiKeyRepository) Revoke(ctx context.Context, key_id string) error {
+	logger.FromContext(ctx, a.logger).DebugContext(ctx, "revoking api key", slog.String("api_key_id", key_id))
+
+	if err := checkID(a.errorHandler, "RevokeAPIKey", "api key", key_id); err != nil {
+		return err
+	}
+
+	result, err := a.db.ExecContext(ctx, revokeAPIKeyQuery, key_id)
+	if err != nil {
+		return a.errorHandler.HandleDatabaseError("RevokeAPIKey", err)
+	}
+
+	if err := validateRowsAffected(a.errorHandler, result, "RevokeAPIKey", "api key", key_id); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, a.logger).InfoContext(ctx, "api key revoked", slog.String("api_key_id", key_id))
+	return nil
+}
+
+func (a *apiKeyRepository) TouchLastUsed(ctx context.Context, key_id string, used_at time.Time) error {
+	if err := checkID(a.errorHandler, "TouchAPIKeyLastUsed", "api key", key_id); err != nil {
+		return err
+	}
+
+	result, err := a.db.ExecContext(ctx, touchAPIKeyLastUsedQuery, used_at, key_id)
+	if err != nil {
+		return a.errorHandler.HandleDatabaseError("TouchAPIKeyLastUsed", err)
+	}
+
+	return validateRowsAffected(a.errorHandler, result, "TouchAPIKeyLastUsed", "api key", key_id)
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/category"
+)
+
+const (
+	categoryColumns         = "id, user_id, name, color, created_at"
+	createCategoryQuery     = "INSERT INTO categories (user_id, name, color) VALUES ($1, $2, $3) RETURNING id, created_at"
+	getAllCategoriesForUser = "SELECT " + categoryColumns + " FROM categories WHERE user_id = $1"
+	getCategoryByIDQuery    = "SELECT " + categoryColumns + " FROM categories WHERE id = $1"
+	updateCategoryQuery     = "UPDATE categories SET name = $1, color = $2 WHERE id = $3"
+	deleteCategoryQuery     = "DELETE FROM categories WHERE id = $1"
+)
+
+type categoryRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewCategoryRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) category.CategoryRepository {
+	return &categoryRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (c *categoryRepository) scanDBCategory(rows any) (*models.DBCategory, error) {
+	category := &models.DBCategory{}
+	var color sql.NullString
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&category.ID, &category.UserID, &category.Name, &color, &category.CreatedAt)
+	case *sql.Rows:
+		err = r.Scan(&category.ID, &category.UserID, &category.Name, &color, &category.CreatedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	category.Color = color.String
+	return category, nil
+}
+
+func (c *categoryRepository) Create(ctx context.Context, category *models.DBCategory) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "creating category", slog.String("user_id", category.UserID))
+
+	var createdCategory models.DBCategory
+	err := c.db.QueryRowContext(ctx, createCategoryQuery, category.UserID, category.Name, category.Color).Scan(&createdCategory.ID, &createdCategory.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category created", slog.String("category_id", createdCategory.ID))
+	return &createdCategory, nil
+}
+
+func (c *categoryRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching categories", slog.String("user_id", user_id))
+
+	categories := make([]models.DBCategory, 0)
+	if !validID(user_id) {
+		return categories, nil
+	}
+
+	rows, err := c.db.QueryContext(ctx, getAllCategoriesForUser, user_id)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch categories", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category, err := c.scanDBCategory(rows)
+		if err != nil {
+			logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to scan category", slog.String("error", err.Error()))
+			return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "error reading categories", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+	}
+
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "categories retrieved", slog.String("user_id", user_id), slog.Int("count", len(categories)))
+	return categories, nil
+}
+
+func (c *categoryRepository) GetById(ctx context.Context, category_id string) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching category", slog.String("category_id", category_id))
+
+	if err := checkID(c.errorHandler, "GetCategoryByID", "category", category_id); err != nil {
+		return nil, err
+	}
+
+	category, err := c.scanDBCategory(c.db.QueryRowContext(ctx, getCategoryByIDQuery, category_id))
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetCategoryByID", err)
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category retrieved", slog.String("category_id", category.ID))
+	return category, nil
+}
+
+func (c *categoryRepository) Update(ctx context.Context, category *models.DBCategory) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "updating category", slog.String("category_id", category.ID))
+
+	if err := checkID(c.errorHandler, "UpdateCategory", "category", category.ID); err != nil {
+		return err
+	}
+
+	result, err := c.db.ExecContext(ctx, updateCategoryQuery, category.Name, category.Color, category.ID)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
+	}
+
+	if err := validateRowsAffected(c.errorHandler, result, "UpdateCategory", "category", category.ID); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category updated", slog.String("category_id", category.ID))
+	return nil
+}
+
+func (c *categoryRepository) Delete(ctx context.Context, category_id string) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "deleting category", slog.String("category_id", category_id))
+
+	if err := checkID(c.errorHandler, "DeleteCategory", "category", category_id); err != nil {
+		return err
+	}
+
+	result, err := c.db.ExecContext(ctx, deleteCategoryQuery, category_id)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to delete category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
+	}
+
+	if err := validateRowsAffected(c.errorHandler, result, "DeleteCategory", "category", category_id); err != nil {
+		logger.FromContext(ctx, c.logger).WarnContext(ctx, "category not found for deletion", slog.String("category_id", category_id))
+		return err
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category deleted", slog.String("category_id", category_id))
+	return nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+)
+
+const (
+	createDependencyQuery       = "INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2) RETURNING created_at"
+	getAllDependenciesForUser   = "SELECT d.task_id, d.blocked_by_id, d.created_at FROM task_dependencies d JOIN tasks t ON t.id = d.task_id WHERE t.user_id = $1 ORDER BY d.created_at, d.task_id, d.blocked_by_id"
+	getDependenciesForTaskQuery = "SELECT task_id, blocked_by_id, created_at FROM task_dependencies WHERE task_id = $1 OR blocked_by_id = $1 ORDER BY created_at, task_id, blocked_by_id"
+	deleteDependencyQuery       = "DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2"
+)
+
+type dependencyRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewDependencyRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) dependency.DependencyRepository {
+	return &dependencyRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (d *dependencyRepository) scanDependencies(rows *sql.Rows, operation string) ([]models.DBTaskDependency, error) {
+	defer rows.Close()
+
+	dependencies := make([]models.DBTaskDependency, 0)
+	for rows.Next() {
+		var dependency models.DBTaskDependency
+		if err := rows.Scan(&dependency.TaskID, &dependency.BlockedByID, &dependency.CreatedAt); err != nil {
+			return nil, d.errorHandler.HandleDatabaseError(operation, err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, d.errorHandler.HandleDatabaseError(operation, err)
+	}
+
+	return dependencies, nil
+}
+
+func (d *dependencyRepository) Create(ctx context.Context, dependency *models.DBTaskDependency) (*models.DBTaskDependency, error) {
+	logger.FromContext(ctx, d.logger).DebugContext(ctx, "creating task dependency", slog.String("task_id", dependency.TaskID), slog.String("blocked_by_id", dependency.BlockedByID))
+
+	createdDependency := models.DBTaskDependency{TaskID: dependency.TaskID, BlockedByID: dependency.BlockedByID}
+	err := d.db.QueryRowContext(ctx, createDependencyQuery, dependency.TaskID, dependency.BlockedByID).Scan(&createdDependency.CreatedAt)
+	if err != nil {
+		return nil, d.errorHandler.HandleDatabaseError("CreateDependency", err)
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "task dependency created", slog.String("task_id", dependency.TaskID), slog.String("blocked_by_id", dependency.BlockedByID))
+	return &createdDependency, nil
+}
+
+func (d *dependencyRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTaskDependency, error) {
+	logger.FromContext(ctx, d.logger).DebugContext(ctx, "getting all task dependencies for a user", slog.String("user_id", user_id))
+
+	if !validID(user_id) {
+		return make([]models.DBTaskDependency, 0), nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, getAllDependenciesForUser, user_id)
+	if err != nil {
+		return nil, d.errorHandler.HandleDatabaseError("GetAllDependenciesForUser", err)
+	}
+
+	dependencies, err := d.scanDependencies(rows, "GetAllDependenciesForUser")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "got all task dependencies for user", slog.String("user_id", user_id), slog.Int("count", len(dependencies)))
+	return dependencies, nil
+}
+
+func (d *dependencyRepository) GetForTask(ctx context.Context, task_id string) ([]models.DBTaskDependency, error) {
+	logger.FromContext(ctx, d.logger).DebugContext(ctx, "getting dependencies of task", slog.String("task_id", task_id))
+
+	if !validID(task_id) {
+		return make([]models.DBTaskDependency, 0), nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, getDependenciesForTaskQuery, task_id)
+	if err != nil {
+		return nil, d.errorHandler.HandleDatabaseError("GetDependenciesForTask", err)
+	}
+
+	dependencies, err := d.scanDependencies(rows, "GetDependenciesForTask")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "got dependencies of task", slog.String("task_id", task_id), slog.Int("count", len(dependencies)))
+	return dependencies, nil
+}
+
+func (d *dependencyRepository) Delete(ctx context.Context, task_id string, blocked_by_id string) error {
+	logger.FromContext(ctx, d.logger).DebugContext(ctx, "deleting task dependency", slog.String("task_id", task_id), slog.String("blocked_by_id", blocked_by_id))
+
+	if !validID(task_id) || !validID(blocked_by_id) {
+		return d.errorHandler.HandleDatabaseError("DeleteDependency", fmt.Errorf("task %s is not blocked by %s: %w", task_id, blocked_by_id, sql.ErrNoRows))
+	}
+
+	result, err := d.db.ExecContext(ctx, deleteDependencyQuery, task_id, blocked_by_id)
+	if err != nil {
+		return d.errorHandler.HandleDatabaseError("DeleteDependency", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return d.errorHandler.HandleDatabaseError("DeleteDependency", err)
+	}
+	if rowsAffected == 0 {
+		return d.errorHandler.HandleDatabaseError("DeleteDependency", fmt.Errorf("task %s is not blocked by %s: %w", task_id, blocked_by_id, sql.ErrNoRows))
+	}
+
+	logger.FromContext(ctx, d.logger).InfoContext(ctx, "deleted task dependency", slog.String("task_id", task_id), slog.String("blocked_by_id", blocked_by_id))
+	return nil
+}
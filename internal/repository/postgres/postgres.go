@@ -0,0 +1,44 @@
+// Package postgres implements the repository interfaces on PostgreSQL. IDs
+// are generated by the database as native UUIDs and enums are native types;
+// the schema lives in migrations/postgres.
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+)
+
+// nullableID maps an empty ID to NULL so optional foreign keys are not
+// checked against a row that cannot exist.
+func nullableID(id string) sql.NullString {
+	return sql.NullString{String: id, Valid: id != ""}
+}
+
+// validID reports whether id can be stored in a UUID column. No row can
+// have an ID that cannot.
+func validID(id string) bool {
+	return uuid.Validate(id) == nil
+}
+
+// checkID reports a lookup by an ID that is not a UUID as a missing row, the
+// same way MySQL treats it, instead of letting PostgreSQL reject the query.
+func checkID(errorHandler *errors.DatabaseErrorHandler, operation string, resource string, id string) error {
+	if !validID(id) {
+		return errorHandler.HandleDatabaseError(operation, fmt.Errorf("no %s found with id %s: %w", resource, id, sql.ErrNoRows))
+	}
+	return nil
+}
+
+func validateRowsAffected(errorHandler *errors.DatabaseErrorHandler, result sql.Result, operation string, resource string, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errorHandler.HandleDatabaseError(operation, err)
+	}
+	if rowsAffected == 0 {
+		return errorHandler.HandleDatabaseError(operation, fmt.Errorf("no %s found with id %s: %w", resource, id, sql.ErrNoRows))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package postgres_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/repository/postgres"
+	"github.com/kjj1998/task-management-system/internal/repository/repositorytest"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostgresTestSuite struct {
+	suite.Suite
+	postgresContainer *testutils.PostgresContainer
+	ctx               context.Context
+	repos             repositorytest.Repositories
+}
+
+func (suite *PostgresTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	postgresContainer, err := testutils.CreatePostgresContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.postgresContainer = postgresContainer
+	host, _ := postgresContainer.Container.Host(suite.ctx)
+	port, _ := postgresContainer.Container.MappedPort(suite.ctx, "5432")
+
+	err = database.ConnectPostgres("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	conn := database.NewConn(database.GetDb())
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+
+	suite.repos = repositorytest.Repositories{
+		Users:        postgres.NewUserRepository(conn, dbErrorHandler, logger),
+		Categories:   postgres.NewCategoryRepository(conn, dbErrorHandler, logger),
+		Tasks:        postgres.NewTaskRepository(conn, dbErrorHandler, logger),
+		Dependencies: postgres.NewDependencyRepository(conn, dbErrorHandler, logger),
+	}
+}
+
+func (suite *PostgresTestSuite) TearDownSuite() {
+	if err := suite.postgresContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func (suite *PostgresTestSuite) TestConformance() {
+	repositorytest.Run(suite.T(), suite.repos)
+}
+
+func TestPostgresTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresTestSuite))
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+const (
+	listTasksSelect = "SELECT " + taskColumns + " FROM tasks"
+	countTasksQuery = "SELECT COUNT(*) FROM tasks"
+)
+
+// queryArgs collects the arguments of a query while it is built and hands
+// out the numbered placeholder for each.
+type queryArgs []any
+
+func (a *queryArgs) add(value any) string {
+	*a = append(*a, value)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+func (a *queryArgs) list(values []any) string {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, a.add(value))
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// sortExpression returns an expression for field that never evaluates to
+// NULL, so it can be compared against keyset cursor values. Enums compare by
+// their position in the type, which is the rank the cursor stores, and
+// titles compare case-insensitively like MySQL's default collation.
+func sortExpression(field models.TaskSortField, args *queryArgs) string {
+	switch field {
+	case models.SortByCreatedAt:
+		return "created_at"
+	case models.SortByUpdatedAt:
+		return "updated_at"
+	case models.SortByDueDate:
+		return fmt.Sprintf("COALESCE(due_date, %s)", args.add(models.NoDueDate))
+	case models.SortByPriority:
+		return "array_position(enum_range(NULL::task_priority), priority)"
+	case models.SortByStatus:
+		return "array_position(enum_range(NULL::task_status), status)"
+	default:
+		return "LOWER(title)"
+	}
+}
+
+func buildTaskFilter(filter models.TaskFilter, args *queryArgs) []string {
+	conditions := []string{"user_id = " + args.add(filter.UserID)}
+
+	in := func(column string, values []any) {
+		if len(values) == 0 {
+			return
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, args.list(values)))
+	}
+
+	statuses := make([]any, 0, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses = append(statuses, status)
+	}
+	in("status", statuses)
+
+	priorities := make([]any, 0, len(filter.Priorities))
+	for _, priority := range filter.Priorities {
+		priorities = append(priorities, priority)
+	}
+	in("priority", priorities)
+
+	categoryIDs := make([]any, 0, len(filter.CategoryIDs))
+	for _, categoryID := range filter.CategoryIDs {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	in("category_id", categoryIDs)
+
+	bounds := []struct {
+		condition string
+		value     any
+		set       bool
+	}{
+		{"due_date >= %s", filter.DueAfter, filter.DueAfter != nil},
+		{"due_date < %s", filter.DueBefore, filter.DueBefore != nil},
+		{"created_at >= %s", filter.CreatedAfter, filter.CreatedAfter != nil},
+		{"created_at < %s", filter.CreatedBefore, filter.CreatedBefore != nil},
+		{"updated_at >= %s", filter.UpdatedAfter, filter.UpdatedAfter != nil},
+		{"updated_at < %s", filter.UpdatedBefore, filter.UpdatedBefore != nil},
+	}
+	for _, bound := range bounds {
+		if bound.set {
+			conditions = append(conditions, fmt.Sprintf(bound.condition, args.add(bound.value)))
+		}
+	}
+
+	if filter.OverdueAt != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date < %s AND status <> %s", args.add(filter.OverdueAt), args.add(models.Completed)))
+	}
+	if filter.Ready {
+		conditions = append(conditions, fmt.Sprintf("status <> %s AND NOT EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id WHERE d.task_id = tasks.id AND b.status <> %s)", args.add(models.Completed), args.add(models.Completed)))
+	}
+
+	return conditions
+}
+
+func buildTaskCountQuery(filter models.TaskFilter) (string, []any) {
+	var args queryArgs
+	conditions := buildTaskFilter(filter, &args)
+	return countTasksQuery + " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildTaskListQuery builds the query for one page of tasks. It selects one
+// row more than the limit so the caller can tell whether another page exists.
+func buildTaskListQuery(query models.TaskQuery) (string, []any, error) {
+	var args queryArgs
+	conditions := buildTaskFilter(query.Filter, &args)
+
+	if query.Cursor != nil {
+		condition, err := buildCursorCondition(query.Sort, query.Cursor, &args)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	orderBy := make([]string, 0, len(query.Sort)+1)
+	for _, s := range query.Sort {
+		direction := "ASC"
+		if s.Descending {
+			direction = "DESC"
+		}
+		orderBy = append(orderBy, sortExpression(s.Field, &args)+" "+direction)
+	}
+	orderBy = append(orderBy, "id ASC")
+
+	sqlQuery := fmt.Sprintf("%s WHERE %s ORDER BY %s LIMIT %s",
+		listTasksSelect,
+		strings.Join(conditions, " AND "),
+		strings.Join(orderBy, ", "),
+		args.add(query.Limit+1),
+	)
+
+	if query.Cursor == nil && query.Offset > 0 {
+		sqlQuery += " OFFSET " + args.add(query.Offset)
+	}
+
+	return sqlQuery, args, nil
+}
+
+// buildCursorCondition expands the keyset comparison
+// (k1, ..., kn, id) > (v1, ..., vn, lastID) into its OR-of-ANDs form so
+// that every sort key can have its own direction.
+func buildCursorCondition(sort []models.TaskSort, cursor *models.TaskCursor, args *queryArgs) (string, error) {
+	type key struct {
+		field      models.TaskSortField
+		value      any
+		descending bool
+	}
+
+	keys := make([]key, 0, len(sort)+1)
+	for i, s := range sort {
+		value, err := models.ParseSortValue(s.Field, cursor.Values[i])
+		if err != nil {
+			return "", err
+		}
+		if s.Field == models.SortByTitle {
+			value = strings.ToLower(value.(string))
+		}
+		keys = append(keys, key{s.Field, value, s.Descending})
+	}
+
+	keys = append(keys, key{value: cursor.ID})
+
+	// The ID tiebreak is the last key and has no sort field.
+	expression := func(k key) string {
+		if k.field == "" {
+			return "id"
+		}
+		return sortExpression(k.field, args)
+	}
+
+	alternatives := make([]string, 0, len(keys))
+	for i, k := range keys {
+		parts := make([]string, 0, i+1)
+		for _, previous := range keys[:i] {
+			parts = append(parts, expression(previous)+" = "+args.add(previous.value))
+		}
+		operator := ">"
+		if k.descending {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", expression(k), operator, args.add(k.value)))
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", nil
+}
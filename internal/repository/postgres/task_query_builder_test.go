@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const ownerID = "5f0c6a52-8d1e-4a43-9a0e-2f3b8f6f4a11"
+
+func TestBuildTaskListQuery(t *testing.T) {
+	t.Run("FiltersAndOffset", func(t *testing.T) {
+		dueBefore := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{
+				UserID:     ownerID,
+				Statuses:   []models.TaskStatus{models.Pending, models.InProgress},
+				Priorities: []models.TaskPriority{models.High},
+				DueBefore:  &dueBefore,
+			},
+			Sort:   []models.TaskSort{{Field: models.SortByPriority, Descending: true}},
+			Limit:  10,
+			Offset: 20,
+		}
+
+		sqlQuery, args, err := buildTaskListQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, listTasksSelect+
+			" WHERE user_id = $1 AND status IN ($2, $3) AND priority IN ($4) AND due_date < $5"+
+			" ORDER BY array_position(enum_range(NULL::task_priority), priority) DESC, id ASC LIMIT $6 OFFSET $7", sqlQuery)
+		assert.Equal(t, []any{ownerID, models.Pending, models.InProgress, models.High, &dueBefore, 11, 20}, args)
+	})
+
+	t.Run("KeysetCursor", func(t *testing.T) {
+		createdAt := time.Date(2025, time.June, 29, 19, 10, 51, 0, time.UTC)
+		sort := []models.TaskSort{
+			{Field: models.SortByDueDate},
+			{Field: models.SortByCreatedAt, Descending: true},
+		}
+		taskID := "0b7e3f4c-51a2-4d8e-8f6a-9c1d2e3f4a5b"
+		cursor := models.NewTaskCursor(sort, models.DBTask{ID: taskID, CreatedAt: &createdAt})
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{UserID: ownerID},
+			Sort:   sort,
+			Limit:  5,
+			Offset: 10,
+			Cursor: cursor,
+		}
+
+		sqlQuery, args, err := buildTaskListQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, listTasksSelect+
+			" WHERE user_id = $1 AND ((COALESCE(due_date, $2) > $3)"+
+			" OR (COALESCE(due_date, $4) = $5 AND created_at < $6)"+
+			" OR (COALESCE(due_date, $7) = $8 AND created_at = $9 AND id > $10))"+
+			" ORDER BY COALESCE(due_date, $11) ASC, created_at DESC, id ASC LIMIT $12", sqlQuery)
+		assert.Equal(t, []any{
+			ownerID,
+			models.NoDueDate, models.NoDueDate,
+			models.NoDueDate, models.NoDueDate, createdAt,
+			models.NoDueDate, models.NoDueDate, createdAt, taskID,
+			models.NoDueDate, 6,
+		}, args)
+	})
+
+	t.Run("TitleCursorIgnoresCase", func(t *testing.T) {
+		sort := []models.TaskSort{{Field: models.SortByTitle}}
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{UserID: ownerID},
+			Sort:   sort,
+			Limit:  5,
+			Cursor: models.NewTaskCursor(sort, models.DBTask{ID: "0b7e3f4c-51a2-4d8e-8f6a-9c1d2e3f4a5b", Title: "Sweep Floor"}),
+		}
+
+		sqlQuery, args, err := buildTaskListQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, listTasksSelect+
+			" WHERE user_id = $1 AND ((LOWER(title) > $2) OR (LOWER(title) = $3 AND id > $4))"+
+			" ORDER BY LOWER(title) ASC, id ASC LIMIT $5", sqlQuery)
+		assert.Equal(t, []any{ownerID, "sweep floor", "sweep floor", "0b7e3f4c-51a2-4d8e-8f6a-9c1d2e3f4a5b", 6}, args)
+	})
+}
+
+func TestBuildTaskCountQuery(t *testing.T) {
+	overdueAt := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	sqlQuery, args := buildTaskCountQuery(models.TaskFilter{UserID: ownerID, OverdueAt: &overdueAt})
+	assert.Equal(t, countTasksQuery+" WHERE user_id = $1 AND due_date < $2 AND status <> $3", sqlQuery)
+	assert.Equal(t, []any{ownerID, &overdueAt, models.Completed}, args)
+}
+
+func TestBuildTaskCountQueryReady(t *testing.T) {
+	sqlQuery, args := buildTaskCountQuery(models.TaskFilter{UserID: ownerID, Ready: true})
+	assert.Equal(t, countTasksQuery+" WHERE user_id = $1 AND status <> $2 AND NOT EXISTS"+
+		" (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id WHERE d.task_id = tasks.id AND b.status <> $3)", sqlQuery)
+	assert.Equal(t, []any{ownerID, models.Completed, models.Completed}, args)
+}
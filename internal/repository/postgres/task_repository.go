@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+)
+
+const (
+	taskColumns        = "id, user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, created_at, updated_at, auto_complete"
+	createTaskQuery    = "INSERT INTO tasks (user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, auto_complete) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at"
+	getTaskByIDQuery   = "SELECT " + taskColumns + " FROM tasks WHERE id = $1"
+	getTasksByIDsQuery = "SELECT " + taskColumns + " FROM tasks WHERE id IN (%s) ORDER BY created_at, id"
+	getAllTasksForUser = "SELECT " + taskColumns + " FROM tasks WHERE user_id = $1"
+	updateTaskQuery    = "UPDATE tasks SET category_id = $1, parent_id = $2, title = $3, description = $4, priority = $5, status = $6, due_date = $7, completed_at = $8, updated_at = COALESCE($9, CURRENT_TIMESTAMP), auto_complete = $10 WHERE id = $11"
+	deleteTaskQuery    = "DELETE FROM tasks WHERE id = $1"
+	// getSubtreeQuery walks down from a task one level at a time, keeping
+	// the depth of each descendant so the shallowest come first. The path
+	// of IDs each row was reached through stops the walk at a task it has
+	// already seen, so it ends even if the hierarchy has become a cycle.
+	getSubtreeQuery = "WITH RECURSIVE subtree (id, depth, path) AS (" +
+		"SELECT id, 1, ARRAY[$1::uuid, id] FROM tasks WHERE parent_id = $1 " +
+		"UNION ALL SELECT tasks.id, subtree.depth + 1, subtree.path || tasks.id FROM tasks JOIN subtree ON tasks.parent_id = subtree.id" +
+		" WHERE tasks.id <> ALL (subtree.path)" +
+		") SELECT " + taskColumns + " FROM tasks JOIN subtree USING (id) ORDER BY subtree.depth, created_at, id"
+	subtaskProgressQuery = "SELECT parent_id, COUNT(*) FILTER (WHERE status = 'completed'), COUNT(*) FROM tasks WHERE parent_id IN (%s) GROUP BY parent_id"
+)
+
+type taskRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewTaskRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) task.TaskRepository {
+	return &taskRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (t *taskRepository) scanDBTask(rows any) (*models.DBTask, error) {
+	task := &models.DBTask{}
+	var categoryID, parentID, description sql.NullString
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
+	case *sql.Rows:
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
+	}
+	if err != nil {
+		return nil, err
+	}
+	task.CategoryID = categoryID.String
+	task.ParentID = parentID.String
+	task.Description = description.String
+	return task, nil
+}
+
+func (t *taskRepository) scanDBTasks(rows *sql.Rows, operation string, capacity int) ([]models.DBTask, error) {
+	defer rows.Close()
+
+	tasks := make([]models.DBTask, 0, capacity)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError(operation, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError(operation, err)
+	}
+
+	return tasks, nil
+}
+
+func (t *taskRepository) Create(ctx context.Context, task *models.DBTask) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "creating task", slog.String("user_id", task.UserID))
+
+	var createdTask models.DBTask
+	err := t.db.QueryRowContext(ctx, createTaskQuery, task.UserID, nullableID(task.CategoryID), nullableID(task.ParentID), task.Title, task.Description, task.Priority, task.Status, task.DueDate, task.CompletedAt, task.AutoComplete).
+		Scan(&createdTask.ID, &createdTask.CreatedAt)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "task created", slog.String("task_id", createdTask.ID), slog.String("creation_time", createdTask.CreatedAt.Format(time.RFC3339)))
+	return &createdTask, nil
+}
+
+func (t *taskRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting all tasks for a user", slog.String("user_id", user_id))
+
+	if !validID(user_id) {
+		return make([]models.DBTask, 0), nil
+	}
+
+	rows, err := t.db.QueryContext(ctx, getAllTasksForUser, user_id)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
+	}
+
+	tasks, err := t.scanDBTasks(rows, "GetAllTasksForUser", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got all tasks for user", slog.String("user_id", user_id))
+	return tasks, nil
+}
+
+func (t *taskRepository) List(ctx context.Context, query models.TaskQuery) (*models.TaskPage, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "listing tasks for a user", slog.String("user_id", query.Filter.UserID))
+
+	countQuery, countArgs := buildTaskCountQuery(query.Filter)
+	var total int
+	err := t.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	listQuery, listArgs, err := buildTaskListQuery(query)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	rows, err := t.db.QueryContext(ctx, listQuery, listArgs...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	tasks, err := t.scanDBTasks(rows, "ListTasks", query.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TaskPage{Tasks: tasks, Total: total}
+	if len(tasks) > query.Limit {
+		page.Tasks = tasks[:query.Limit]
+		page.NextCursor = models.NewTaskCursor(query.Sort, page.Tasks[query.Limit-1]).Encode()
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "listed tasks for user", slog.String("user_id", query.Filter.UserID), slog.Int("count", len(page.Tasks)), slog.Int("total", total))
+	return page, nil
+}
+
+func (t *taskRepository) GetById(ctx context.Context, task_id string) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting task by ID", slog.String("task_id", task_id))
+
+	if err := checkID(t.errorHandler, "GetTaskByID", "task", task_id); err != nil {
+		return nil, err
+	}
+
+	task, err := t.scanDBTask(t.db.QueryRowContext(ctx, getTaskByIDQuery, task_id))
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskByID", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got task", slog.String("task_id", task_id))
+	return task, nil
+}
+
+func (t *taskRepository) GetByIds(ctx context.Context, task_ids []string) ([]models.DBTask, error) {
+	ids := make([]any, 0, len(task_ids))
+	for _, id := range task_ids {
+		if validID(id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return make([]models.DBTask, 0), nil
+	}
+
+	var args queryArgs
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(getTasksByIDsQuery, args.list(ids)), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+	}
+
+	return t.scanDBTasks(rows, "GetTasksByIDs", len(ids))
+}
+
+func (t *taskRepository) Update(ctx context.Context, task *models.DBTask) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "updating task", slog.String("task_id", task.ID))
+
+	if err := checkID(t.errorHandler, "UpdateTask", "task", task.ID); err != nil {
+		return err
+	}
+
+	result, err := t.db.ExecContext(ctx,
+		updateTaskQuery,
+		nullableID(task.CategoryID),
+		nullableID(task.ParentID),
+		task.Title,
+		task.Description,
+		task.Priority,
+		task.Status,
+		task.DueDate,
+		task.CompletedAt,
+		task.UpdatedAt,
+		task.AutoComplete,
+		task.ID,
+	)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
+	}
+
+	if err := validateRowsAffected(t.errorHandler, result, "UpdateTask", "task", task.ID); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "updated task", slog.String("task_id", task.ID))
+	return nil
+}
+
+func (t *taskRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "deleting task", slog.String("task_id", id))
+
+	if err := checkID(t.errorHandler, "DeleteTask", "task", id); err != nil {
+		return err
+	}
+
+	result, err := t.db.ExecContext(ctx, deleteTaskQuery, id)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
+	}
+
+	if err := validateRowsAffected(t.errorHandler, result, "DeleteTask", "task", id); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "deleted task", slog.String("task_id", id))
+	return nil
+}
+
+func (t *taskRepository) GetSubtree(ctx context.Context, task_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting subtree of task", slog.String("task_id", task_id))
+
+	if !validID(task_id) {
+		return make([]models.DBTask, 0), nil
+	}
+
+	rows, err := t.db.QueryContext(ctx, getSubtreeQuery, task_id)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+	}
+
+	tasks, err := t.scanDBTasks(rows, "GetTaskSubtree", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got subtree of task", slog.String("task_id", task_id), slog.Int("count", len(tasks)))
+	return tasks, nil
+}
+
+func (t *taskRepository) GetSubtaskProgress(ctx context.Context, parent_ids []string) (map[string]models.SubtaskProgress, error) {
+	progress := make(map[string]models.SubtaskProgress)
+
+	ids := make([]any, 0, len(parent_ids))
+	for _, id := range parent_ids {
+		if validID(id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return progress, nil
+	}
+
+	var args queryArgs
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(subtaskProgressQuery, args.list(ids)), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var counts models.SubtaskProgress
+		if err := rows.Scan(&parentID, &counts.Completed, &counts.Total); err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+		}
+		progress[parentID] = counts
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+
+	return progress, nil
+}
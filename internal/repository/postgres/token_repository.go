@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/token"
+)
+
+const (
+	createRefreshTokenQuery       = "INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	getRefreshTokenByHashQuery    = "SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at FROM refresh_tokens WHERE token_hash = $1"
+	revokeRefreshTokenQuery       = "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $1 WHERE id = $2 AND revoked_at IS NULL"
+	revokeRefreshTokenFamilyQuery = "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL"
+)
+
+type refreshTokenRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewRefreshTokenRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) token.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.DBRefreshToken) (*models.DBRefreshToken, error) {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "creating refresh token", slog.String("user_id", token.UserID), slog.String("family_id", token.FamilyID))
+
+	var createdToken models.DBRefreshToken
+	err := r.db.QueryRowContext(ctx, createRefreshTokenQuery, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt).Scan(&createdToken.ID, &createdToken.CreatedAt)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("CreateRefreshToken", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token created", slog.String("token_id", createdToken.ID))
+	return &createdToken, nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, token_hash string) (*models.DBRefreshToken, error) {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "getting refresh token by hash")
+
+	token := &models.DBRefreshToken{}
+	var replacedBy sql.NullString
+	err := r.db.QueryRowContext(ctx, getRefreshTokenByHashQuery, token_hash).
+		Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &replacedBy, &token.CreatedAt)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("GetRefreshTokenByHash", err)
+	}
+	token.ReplacedBy = replacedBy.String
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "got refresh token", slog.String("token_id", token.ID))
+	return token, nil
+}
+
+// Revoke marks a token as used. It fails with a not found error when the
+// token is missing or was already revoked, so only one of several concurrent
+// rotations of the same token can succeed.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, token_id string, replaced_by string) error {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "revoking refresh token", slog.String("token_id", token_id))
+
+	if err := checkID(r.errorHandler, "RevokeRefreshToken", "refresh token", token_id); err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, revokeRefreshTokenQuery, nullableID(replaced_by), token_id)
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", err)
+	}
+	if rowsAffected == 0 {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", fmt.Errorf("no active refresh token found with id %s: %w", token_id, sql.ErrNoRows))
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token revoked", slog.String("token_id", token_id))
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, family_id string) error {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "revoking refresh token family", slog.String("family_id", family_id))
+
+	if !validID(family_id) {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, revokeRefreshTokenFamilyQuery, family_id)
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshTokenFamily", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token family revoked", slog.String("family_id", family_id))
+	return nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/user"
+)
+
+const (
+	userColumns      = "id, email, password_hash, first_name, last_name, created_at, updated_at"
+	createUserQuery  = "INSERT INTO users (email, password_hash, first_name, last_name) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	getUserByIDQuery = "SELECT " + userColumns + " FROM users WHERE id = $1"
+	getUserByEmail   = "SELECT " + userColumns + " FROM users WHERE LOWER(email) = LOWER($1)"
+	lockUserQuery    = "SELECT id FROM users WHERE id = $1 FOR NO KEY UPDATE"
+	deleteUserQuery  = "DELETE FROM users WHERE id = $1"
+	updateUserQuery  = "UPDATE users SET email = $1, first_name = $2, last_name = $3 WHERE id = $4"
+)
+
+type userRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewUserRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) user.UserRepository {
+	return &userRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (u *userRepository) scanDBUser(row *sql.Row) (*models.DBUser, error) {
+	user := &models.DBUser{}
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *userRepository) Create(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "creating user", slog.String("email", user.Email))
+
+	var createdUser models.DBUser
+	err := u.db.QueryRowContext(ctx, createUserQuery, user.Email, user.PasswordHash, user.FirstName, user.LastName).Scan(&createdUser.ID, &createdUser.CreatedAt)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "created user", slog.String("user_id", createdUser.ID))
+	return &createdUser, nil
+}
+
+func (u *userRepository) GetById(ctx context.Context, id string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by id", slog.String("user_id", id))
+
+	if err := checkID(u.errorHandler, "GetUserByID", "user", id); err != nil {
+		return nil, err
+	}
+
+	user, err := u.scanDBUser(u.db.QueryRowContext(ctx, getUserByIDQuery, id))
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("GetUserByID", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user", slog.String("user_id", id))
+	return user, nil
+}
+
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by email", slog.String("email", email))
+
+	user, err := u.scanDBUser(u.db.QueryRowContext(ctx, getUserByEmail, email))
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("GetUserByEmail", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user by email", slog.String("user_id", user.ID))
+	return user, nil
+}
+
+func (u *userRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "delete user", slog.String("user_id", id))
+
+	if err := checkID(u.errorHandler, "DeleteUser", "user", id); err != nil {
+		return err
+	}
+
+	result, err := u.db.ExecContext(ctx, deleteUserQuery, id)
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
+	}
+
+	if err := validateRowsAffected(u.errorHandler, result, "DeleteUser", "user", id); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "deleted user", slog.String("user_id", id))
+	return nil
+}
+
+func (u *userRepository) Update(ctx context.Context, user *models.DBUser) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "update user", slog.String("user_id", user.ID))
+
+	if err := checkID(u.errorHandler, "UpdateUser", "user", user.ID); err != nil {
+		return err
+	}
+
+	result, err := u.db.ExecContext(ctx, updateUserQuery, user.Email, user.FirstName, user.LastName, user.ID)
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
+	}
+
+	if err := validateRowsAffected(u.errorHandler, result, "UpdateUser", "user", user.ID); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "updated user", slog.String("user_id", user.ID))
+	return nil
+}
+
+// Lock takes a FOR NO KEY UPDATE lock, which does not block the key share
+// locks that inserting the user's tasks takes.
+func (u *userRepository) Lock(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "locking user", slog.String("user_id", id))
+
+	if err := checkID(u.errorHandler, "LockUser", "user", id); err != nil {
+		return err
+	}
+
+	var lockedID string
+	if err := u.db.QueryRowContext(ctx, lockUserQuery, id).Scan(&lockedID); err != nil {
+		return u.errorHandler.HandleDatabaseError("LockUser", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,435 @@
+// Package repositorytest holds a conformance suite that every implementation
+// of the repository interfaces must pass, so the MySQL and in-memory backends
+// cannot drift apart.
+package repositorytest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/category"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+	"github.com/kjj1998/task-management-system/internal/repository/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories are the implementations under test. They must share one
+// backing database.
+type Repositories struct {
+	Users        user.UserRepository
+	Categories   category.CategoryRepository
+	Tasks        task.TaskRepository
+	Dependencies dependency.DependencyRepository
+}
+
+// Run runs the conformance suite against repos. It only creates and removes
+// its own rows, so it can share a database with other tests.
+func Run(t *testing.T, repos Repositories) {
+	c := &conformance{repos: repos, ctx: context.Background()}
+
+	t.Run("Users", c.testUsers)
+	t.Run("Categories", c.testCategories)
+	t.Run("Tasks", c.testTasks)
+	t.Run("TaskHierarchy", c.testTaskHierarchy)
+	t.Run("TaskDependencies", c.testTaskDependencies)
+	t.Run("DeleteUserCascades", c.testDeleteUserCascades)
+}
+
+type conformance struct {
+	repos Repositories
+	ctx   context.Context
+}
+
+func assertStatus(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, statusCode, appErr.StatusCode)
+	}
+}
+
+func assertCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, code, appErr.Code)
+	}
+}
+
+func (c *conformance) createUser(t *testing.T) *models.DBUser {
+	t.Helper()
+
+	user := &models.DBUser{
+		Email:        uuid.NewString() + "@email.com",
+		PasswordHash: "not-a-real-hash",
+		FirstName:    "Conformance",
+		LastName:     "Tester",
+	}
+	created, err := c.repos.Users.Create(c.ctx, user)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+
+	user.ID = created.ID
+	return user
+}
+
+func (c *conformance) createCategory(t *testing.T, userID string, name string) string {
+	t.Helper()
+
+	created, err := c.repos.Categories.Create(c.ctx, &models.DBCategory{UserID: userID, Name: name, Color: "#007bff"})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.NotNil(t, created.CreatedAt)
+
+	return created.ID
+}
+
+func (c *conformance) createTask(t *testing.T, task models.DBTask) string {
+	t.Helper()
+
+	created, err := c.repos.Tasks.Create(c.ctx, &task)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.NotNil(t, created.CreatedAt)
+
+	return created.ID
+}
+
+func (c *conformance) testUsers(t *testing.T) {
+	user := c.createUser(t)
+
+	stored, err := c.repos.Users.GetById(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, stored.Email)
+	assert.Equal(t, "not-a-real-hash", stored.PasswordHash)
+
+	stored, err = c.repos.Users.GetByEmail(c.ctx, strings.ToUpper(user.Email))
+	require.NoError(t, err, "email lookups are case-insensitive")
+	assert.Equal(t, user.ID, stored.ID)
+
+	_, err = c.repos.Users.Create(c.ctx, &models.DBUser{Email: user.Email, PasswordHash: "x", FirstName: "Copy", LastName: "Cat"})
+	assertStatus(t, err, http.StatusConflict)
+
+	other := c.createUser(t)
+	other.Email = strings.ToUpper(user.Email)
+	assertStatus(t, c.repos.Users.Update(c.ctx, other), http.StatusConflict)
+
+	user.FirstName = "Renamed"
+	require.NoError(t, c.repos.Users.Update(c.ctx, user))
+	stored, err = c.repos.Users.GetById(c.ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", stored.FirstName)
+
+	missing := uuid.NewString()
+	_, err = c.repos.Users.GetById(c.ctx, missing)
+	assertStatus(t, err, http.StatusNotFound)
+	_, err = c.repos.Users.GetByEmail(c.ctx, missing+"@email.com")
+	assertStatus(t, err, http.StatusNotFound)
+	assertStatus(t, c.repos.Users.Update(c.ctx, &models.DBUser{ID: missing, Email: missing + "@email.com"}), http.StatusNotFound)
+	assertStatus(t, c.repos.Users.Delete(c.ctx, missing), http.StatusNotFound)
+
+	require.NoError(t, c.repos.Users.Delete(c.ctx, other.ID))
+	_, err = c.repos.Users.GetById(c.ctx, other.ID)
+	assertStatus(t, err, http.StatusNotFound)
+}
+
+func (c *conformance) testCategories(t *testing.T) {
+	owner := c.createUser(t)
+	other := c.createUser(t)
+
+	workID := c.createCategory(t, owner.ID, "Work")
+	homeID := c.createCategory(t, owner.ID, "Home")
+	c.createCategory(t, other.ID, "Work")
+
+	_, err := c.repos.Categories.Create(c.ctx, &models.DBCategory{UserID: owner.ID, Name: "work", Color: "#000000"})
+	assertStatus(t, err, http.StatusConflict)
+
+	categories, err := c.repos.Categories.GetAllForUser(c.ctx, owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, categories, 2)
+
+	stored, err := c.repos.Categories.GetById(c.ctx, workID)
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, stored.UserID)
+	assert.Equal(t, "Work", stored.Name)
+
+	stored.Name = "home"
+	assertStatus(t, c.repos.Categories.Update(c.ctx, stored), http.StatusConflict)
+
+	stored.Name = "Office"
+	stored.Color = "#ff0000"
+	require.NoError(t, c.repos.Categories.Update(c.ctx, stored))
+	stored, err = c.repos.Categories.GetById(c.ctx, workID)
+	require.NoError(t, err)
+	assert.Equal(t, "Office", stored.Name)
+	assert.Equal(t, "#ff0000", stored.Color)
+
+	taskID := c.createTask(t, models.DBTask{UserID: owner.ID, CategoryID: homeID, Title: "Water plants", Priority: models.Low, Status: models.Pending})
+	require.NoError(t, c.repos.Categories.Delete(c.ctx, homeID))
+	task, err := c.repos.Tasks.GetById(c.ctx, taskID)
+	require.NoError(t, err, "deleting a category keeps its tasks")
+	assert.Empty(t, task.CategoryID)
+
+	missing := uuid.NewString()
+	_, err = c.repos.Categories.GetById(c.ctx, homeID)
+	assertStatus(t, err, http.StatusNotFound)
+	assertStatus(t, c.repos.Categories.Update(c.ctx, &models.DBCategory{ID: missing, Name: "Ghost", Color: "#000000"}), http.StatusNotFound)
+	assertStatus(t, c.repos.Categories.Delete(c.ctx, missing), http.StatusNotFound)
+}
+
+func (c *conformance) testTasks(t *testing.T) {
+	owner := c.createUser(t)
+	categoryID := c.createCategory(t, owner.ID, "Chores")
+	dueDate := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+
+	lowID := c.createTask(t, models.DBTask{UserID: owner.ID, CategoryID: categoryID, Title: "Dust shelves", Priority: models.Low, Status: models.Pending})
+	mediumID := c.createTask(t, models.DBTask{UserID: owner.ID, Title: "Buy milk", Priority: models.Medium, Status: models.InProgress, DueDate: &dueDate})
+	highID := c.createTask(t, models.DBTask{UserID: owner.ID, CategoryID: categoryID, Title: "Fix sink", Priority: models.High, Status: models.Pending})
+
+	stored, err := c.repos.Tasks.GetById(c.ctx, mediumID)
+	require.NoError(t, err)
+	assert.Equal(t, "Buy milk", stored.Title)
+	assert.Empty(t, stored.CategoryID)
+	if assert.NotNil(t, stored.DueDate) {
+		assert.True(t, dueDate.Equal(*stored.DueDate))
+	}
+
+	tasks, err := c.repos.Tasks.GetAllForUser(c.ctx, owner.ID)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 3)
+
+	t.Run("List", func(t *testing.T) {
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{UserID: owner.ID},
+			Sort:   []models.TaskSort{{Field: models.SortByPriority, Descending: true}},
+			Limit:  2,
+		}
+		page, err := c.repos.Tasks.List(c.ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		require.Len(t, page.Tasks, 2)
+		assert.Equal(t, highID, page.Tasks[0].ID)
+		assert.Equal(t, mediumID, page.Tasks[1].ID)
+		require.NotEmpty(t, page.NextCursor)
+
+		query.Cursor, err = models.DecodeTaskCursor(page.NextCursor, query.Sort)
+		require.NoError(t, err)
+		page, err = c.repos.Tasks.List(c.ctx, query)
+		require.NoError(t, err)
+		require.Len(t, page.Tasks, 1)
+		assert.Equal(t, lowID, page.Tasks[0].ID)
+		assert.Empty(t, page.NextCursor)
+
+		page, err = c.repos.Tasks.List(c.ctx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: owner.ID, Statuses: []models.TaskStatus{models.Pending}, CategoryIDs: []string{categoryID}},
+			Sort:   []models.TaskSort{{Field: models.SortByTitle}},
+			Limit:  10,
+			Offset: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+		require.Len(t, page.Tasks, 1)
+		assert.Equal(t, highID, page.Tasks[0].ID)
+
+		page, err = c.repos.Tasks.List(c.ctx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: owner.ID, DueBefore: &dueDate},
+			Sort:   []models.TaskSort{{Field: models.SortByDueDate}},
+			Limit:  10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, page.Total, "due date bounds exclude tasks without a due date")
+		assert.Empty(t, page.Tasks)
+	})
+
+	byIDs, err := c.repos.Tasks.GetByIds(c.ctx, []string{highID, uuid.NewString(), lowID, highID})
+	require.NoError(t, err)
+	ids := make([]string, len(byIDs))
+	for i, task := range byIDs {
+		ids[i] = task.ID
+	}
+	assert.ElementsMatch(t, []string{lowID, highID}, ids, "unknown and repeated IDs are left out")
+	byIDs, err = c.repos.Tasks.GetByIds(c.ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, byIDs)
+
+	stored.Title = "Buy oat milk"
+	stored.Status = models.Completed
+	stored.CategoryID = categoryID
+	completedAt := time.Now().UTC().Truncate(time.Second)
+	stored.CompletedAt = &completedAt
+	require.NoError(t, c.repos.Tasks.Update(c.ctx, stored))
+	stored, err = c.repos.Tasks.GetById(c.ctx, mediumID)
+	require.NoError(t, err)
+	assert.Equal(t, "Buy oat milk", stored.Title)
+	assert.Equal(t, models.Completed, stored.Status)
+	assert.Equal(t, categoryID, stored.CategoryID)
+	if assert.NotNil(t, stored.CompletedAt) {
+		assert.True(t, completedAt.Equal(*stored.CompletedAt))
+	}
+
+	require.NoError(t, c.repos.Tasks.Delete(c.ctx, lowID))
+	_, err = c.repos.Tasks.GetById(c.ctx, lowID)
+	assertStatus(t, err, http.StatusNotFound)
+
+	missing := uuid.NewString()
+	_, err = c.repos.Tasks.Create(c.ctx, &models.DBTask{UserID: owner.ID, CategoryID: missing, Title: "Orphan", Priority: models.Low, Status: models.Pending})
+	assertStatus(t, err, http.StatusUnprocessableEntity)
+	assertCode(t, err, errors.CodeInvalidReference)
+	stored.CategoryID = missing
+	err = c.repos.Tasks.Update(c.ctx, stored)
+	assertStatus(t, err, http.StatusUnprocessableEntity)
+	assertCode(t, err, errors.CodeInvalidReference)
+
+	assertStatus(t, c.repos.Tasks.Update(c.ctx, &models.DBTask{ID: missing, UserID: owner.ID, Title: "Ghost", Priority: models.Low, Status: models.Pending}), http.StatusNotFound)
+	assertStatus(t, c.repos.Tasks.Delete(c.ctx, missing), http.StatusNotFound)
+}
+
+func (c *conformance) testTaskHierarchy(t *testing.T) {
+	owner := c.createUser(t)
+	rootID := c.createTask(t, models.DBTask{UserID: owner.ID, Title: "Move house", Priority: models.High, Status: models.Pending, AutoComplete: true})
+	packID := c.createTask(t, models.DBTask{UserID: owner.ID, ParentID: rootID, Title: "Pack boxes", Priority: models.Medium, Status: models.Completed})
+	cleanID := c.createTask(t, models.DBTask{UserID: owner.ID, ParentID: rootID, Title: "Clean flat", Priority: models.Medium, Status: models.Pending})
+	kitchenID := c.createTask(t, models.DBTask{UserID: owner.ID, ParentID: cleanID, Title: "Clean kitchen", Priority: models.Low, Status: models.Pending})
+
+	root, err := c.repos.Tasks.GetById(c.ctx, rootID)
+	require.NoError(t, err)
+	assert.Empty(t, root.ParentID)
+	assert.True(t, root.AutoComplete)
+
+	kitchen, err := c.repos.Tasks.GetById(c.ctx, kitchenID)
+	require.NoError(t, err)
+	assert.Equal(t, cleanID, kitchen.ParentID)
+	assert.False(t, kitchen.AutoComplete)
+
+	subtree, err := c.repos.Tasks.GetSubtree(c.ctx, rootID)
+	require.NoError(t, err)
+	ids := make([]string, len(subtree))
+	for i, task := range subtree {
+		ids[i] = task.ID
+	}
+	require.Len(t, ids, 3)
+	assert.ElementsMatch(t, []string{packID, cleanID}, ids[:2], "direct subtasks come first")
+	assert.Equal(t, kitchenID, ids[2])
+
+	subtree, err = c.repos.Tasks.GetSubtree(c.ctx, kitchenID)
+	require.NoError(t, err)
+	assert.Empty(t, subtree)
+
+	progress, err := c.repos.Tasks.GetSubtaskProgress(c.ctx, []string{rootID, cleanID, kitchenID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.SubtaskProgress{
+		rootID:  {Completed: 1, Total: 2},
+		cleanID: {Completed: 0, Total: 1},
+	}, progress)
+
+	// Repositories do not reject cycles, but walking one must still end.
+	root.ParentID = kitchenID
+	require.NoError(t, c.repos.Tasks.Update(c.ctx, root))
+	subtree, err = c.repos.Tasks.GetSubtree(c.ctx, rootID)
+	require.NoError(t, err)
+	assert.Len(t, subtree, 3)
+	subtree, err = c.repos.Tasks.GetSubtree(c.ctx, kitchenID)
+	require.NoError(t, err)
+	assert.Len(t, subtree, 3)
+	root.ParentID = ""
+	require.NoError(t, c.repos.Tasks.Update(c.ctx, root))
+
+	kitchen.ParentID = rootID
+	require.NoError(t, c.repos.Tasks.Update(c.ctx, kitchen))
+	progress, err = c.repos.Tasks.GetSubtaskProgress(c.ctx, []string{rootID, cleanID})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.SubtaskProgress{rootID: {Completed: 1, Total: 3}}, progress)
+
+	kitchen.ParentID = uuid.NewString()
+	assert.Error(t, c.repos.Tasks.Update(c.ctx, kitchen), "the parent must exist")
+
+	require.NoError(t, c.repos.Tasks.Delete(c.ctx, rootID))
+	pack, err := c.repos.Tasks.GetById(c.ctx, packID)
+	require.NoError(t, err)
+	assert.Empty(t, pack.ParentID, "deleting a parent detaches its subtasks")
+}
+
+func (c *conformance) testTaskDependencies(t *testing.T) {
+	owner := c.createUser(t)
+	designID := c.createTask(t, models.DBTask{UserID: owner.ID, Title: "Design API", Priority: models.High, Status: models.Pending})
+	buildID := c.createTask(t, models.DBTask{UserID: owner.ID, Title: "Build API", Priority: models.High, Status: models.Pending})
+	shipID := c.createTask(t, models.DBTask{UserID: owner.ID, Title: "Ship API", Priority: models.Medium, Status: models.Pending})
+
+	created, err := c.repos.Dependencies.Create(c.ctx, &models.DBTaskDependency{TaskID: buildID, BlockedByID: designID})
+	require.NoError(t, err)
+	assert.Equal(t, buildID, created.TaskID)
+	assert.Equal(t, designID, created.BlockedByID)
+	assert.NotNil(t, created.CreatedAt)
+	_, err = c.repos.Dependencies.Create(c.ctx, &models.DBTaskDependency{TaskID: shipID, BlockedByID: buildID})
+	require.NoError(t, err)
+
+	_, err = c.repos.Dependencies.Create(c.ctx, &models.DBTaskDependency{TaskID: buildID, BlockedByID: designID})
+	assertStatus(t, err, http.StatusConflict)
+	_, err = c.repos.Dependencies.Create(c.ctx, &models.DBTaskDependency{TaskID: buildID, BlockedByID: uuid.NewString()})
+	assert.Error(t, err, "the blocking task must exist")
+
+	edges := func(dependencies []models.DBTaskDependency) []string {
+		edges := make([]string, len(dependencies))
+		for i, dependency := range dependencies {
+			edges[i] = dependency.TaskID + " <- " + dependency.BlockedByID
+		}
+		return edges
+	}
+
+	all, err := c.repos.Dependencies.GetAllForUser(c.ctx, owner.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{buildID + " <- " + designID, shipID + " <- " + buildID}, edges(all))
+
+	forBuild, err := c.repos.Dependencies.GetForTask(c.ctx, buildID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, edges(all), edges(forBuild), "both sides of a task are returned")
+
+	ready, err := c.repos.Tasks.List(c.ctx, models.TaskQuery{Filter: models.TaskFilter{UserID: owner.ID, Ready: true}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, ready.Tasks, 1)
+	assert.Equal(t, designID, ready.Tasks[0].ID, "only tasks without open blockers are ready")
+
+	other := c.createUser(t)
+	none, err := c.repos.Dependencies.GetAllForUser(c.ctx, other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	require.NoError(t, c.repos.Dependencies.Delete(c.ctx, shipID, buildID))
+	assertStatus(t, c.repos.Dependencies.Delete(c.ctx, shipID, buildID), http.StatusNotFound)
+
+	require.NoError(t, c.repos.Tasks.Delete(c.ctx, designID))
+	forBuild, err = c.repos.Dependencies.GetForTask(c.ctx, buildID)
+	require.NoError(t, err)
+	assert.Empty(t, forBuild, "deleting a task removes its dependencies")
+}
+
+func (c *conformance) testDeleteUserCascades(t *testing.T) {
+	owner := c.createUser(t)
+	categoryID := c.createCategory(t, owner.ID, "Errands")
+	taskID := c.createTask(t, models.DBTask{UserID: owner.ID, CategoryID: categoryID, Title: "Post letter", Priority: models.Medium, Status: models.Pending})
+	subtaskID := c.createTask(t, models.DBTask{UserID: owner.ID, ParentID: taskID, Title: "Buy stamps", Priority: models.Medium, Status: models.Pending})
+	_, err := c.repos.Dependencies.Create(c.ctx, &models.DBTaskDependency{TaskID: taskID, BlockedByID: subtaskID})
+	require.NoError(t, err)
+
+	require.NoError(t, c.repos.Users.Delete(c.ctx, owner.ID))
+
+	_, err = c.repos.Categories.GetById(c.ctx, categoryID)
+	assertStatus(t, err, http.StatusNotFound)
+	_, err = c.repos.Tasks.GetById(c.ctx, taskID)
+	assertStatus(t, err, http.StatusNotFound)
+	_, err = c.repos.Tasks.GetById(c.ctx, subtaskID)
+	assertStatus(t, err, http.StatusNotFound)
+	dependencies, err := c.repos.Dependencies.GetForTask(c.ctx, taskID)
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
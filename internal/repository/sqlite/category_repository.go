@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/category"
+)
+
+const (
+	createCategoryQuery     = "INSERT INTO categories (id, user_id, name, color) VALUES (?, ?, ?, ?)"
+	getCategoryAfterCreate  = "SELECT id, created_at FROM categories WHERE id = ?"
+	getAllCategoriesForUser = "SELECT id, user_id, name, color, created_at FROM categories WHERE user_id = ?"
+	getCategoryByIDQuery    = "SELECT * FROM categories WHERE id = ?"
+	updateCategoryQuery     = "UPDATE categories SET name = ?, color = ? WHERE id = ?"
+)
+
+type categoryRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewCategoryRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) category.CategoryRepository {
+	return &categoryRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (c *categoryRepository) scanDBCategory(rows any) (*models.DBCategory, error) {
+	category := &models.DBCategory{}
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&category.ID, &category.UserID, &category.Name, &category.Color, &category.CreatedAt)
+	case *sql.Rows:
+		err = r.Scan(&category.ID, &category.UserID, &category.Name, &category.Color, &category.CreatedAt)
+	default:
+		return nil, fmt.Errorf("unsupported row type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (c *categoryRepository) validateRowsAffected(result sql.Result, operation string, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return c.errorHandler.HandleDatabaseError(operation, err)
+	}
+	if rowsAffected == 0 {
+		return c.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no category found with id %s: %w", id, sql.ErrNoRows))
+	}
+	return nil
+}
+
+func (c *categoryRepository) Create(ctx context.Context, category *models.DBCategory) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "creating category", slog.String("user_id", category.UserID))
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, c.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	category_id := uuid.NewString()
+
+	_, err = tx.ExecContext(ctx, createCategoryQuery, category_id, category.UserID, category.Name, category.Color)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
+	}
+
+	var createdCategory models.DBCategory
+	err = tx.QueryRowContext(ctx, getCategoryAfterCreate, category_id).Scan(&createdCategory.ID, &createdCategory.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to create category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("CreateCategory", err)
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category created", slog.String("category_id", createdCategory.ID))
+	return &createdCategory, nil
+}
+
+func (c *categoryRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching categories", slog.String("user_id", user_id))
+
+	rows, err := c.db.QueryContext(ctx, getAllCategoriesForUser, user_id)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch categories", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+	}
+
+	categories := make([]models.DBCategory, 0)
+	for rows.Next() {
+		category, err := c.scanDBCategory(rows)
+		if err != nil {
+			logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to scan category", slog.String("error", err.Error()))
+			return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "error reading categories", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetAllCategoriesForUser", err)
+	}
+
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "categories retrieved", slog.String("user_id", user_id), slog.Int("count", len(categories)))
+	return categories, nil
+}
+
+func (c *categoryRepository) GetById(ctx context.Context, category_id string) (*models.DBCategory, error) {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "fetching category", slog.String("category_id", category_id))
+
+	row := c.db.QueryRowContext(ctx, getCategoryByIDQuery, category_id)
+	category, err := c.scanDBCategory(row)
+
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to fetch category", slog.String("error", err.Error()))
+		return nil, c.errorHandler.HandleDatabaseError("GetCategoryByID", err)
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category retrieved", slog.String("category_id", category.ID))
+	return category, nil
+}
+
+func (c *categoryRepository) Update(ctx context.Context, category *models.DBCategory) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "updating category", slog.String("category_id", category.ID))
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, c.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, updateCategoryQuery, category.Name, category.Color, category.ID)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
+	}
+
+	if err := c.validateRowsAffected(result, "UpdateCategory", category.ID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to update category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("UpdateCategory", err)
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category updated", slog.String("category_id", category.ID))
+	return nil
+}
+
+func (c *categoryRepository) Delete(ctx context.Context, category_id string) error {
+	logger.FromContext(ctx, c.logger).DebugContext(ctx, "deleting category", slog.String("category_id", category_id))
+
+	command := "DELETE FROM categories WHERE id = ?"
+	result, err := c.db.ExecContext(ctx, command, category_id)
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to delete category", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.FromContext(ctx, c.logger).ErrorContext(ctx, "failed to check deletion result", slog.String("error", err.Error()))
+		return c.errorHandler.HandleDatabaseError("DeleteCategory", err)
+	}
+	if rowsAffected == 0 {
+		logger.FromContext(ctx, c.logger).WarnContext(ctx, "category not found for deletion", slog.String("category_id", category_id))
+		return c.errorHandler.HandleDatabaseError("DeleteCategory", fmt.Errorf("no category found with id %s: %w", category_id, sql.ErrNoRows))
+	}
+
+	logger.FromContext(ctx, c.logger).InfoContext(ctx, "category deleted", slog.String("category_id", category_id))
+	return nil
+}
@@ -0,0 +1,27 @@
+// Package sqlite implements the repository interfaces on an embedded SQLite
+// database, for installs without a database server. The schema lives in
+// migrations/sqlite and mirrors the MySQL one.
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+)
+
+// timeFormat is the layout of CURRENT_TIMESTAMP. Every time is stored in it,
+// in UTC, so that SQLite's text comparisons order times correctly.
+const timeFormat = time.DateTime
+
+// timestamp converts t to its stored form, or NULL when t is nil.
+func timestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(timeFormat)
+}
+
+// nullableID maps an empty ID to NULL so optional foreign keys are not
+// checked against a row that cannot exist.
+func nullableID(id string) sql.NullString {
+	return sql.NullString{String: id, Valid: id != ""}
+}
@@ -0,0 +1,68 @@
+package sqlite_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/repositorytest"
+	"github.com/kjj1998/task-management-system/internal/repository/sqlite"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRepositories(t *testing.T) repositorytest.Repositories {
+	t.Helper()
+
+	logger := logger.NewLogger("test")
+	db, err := testutils.CreateSQLiteDatabase(t.TempDir(), logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	var journalMode string
+	require.NoError(t, db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	require.Equal(t, "wal", journalMode)
+
+	conn := database.NewConn(db)
+	errorHandler := errors.NewDatabaseErrorHandler()
+
+	return repositorytest.Repositories{
+		Users:        sqlite.NewUserRepository(conn, errorHandler, logger),
+		Categories:   sqlite.NewCategoryRepository(conn, errorHandler, logger),
+		Tasks:        sqlite.NewTaskRepository(conn, errorHandler, logger),
+		Dependencies: sqlite.NewDependencyRepository(conn, errorHandler, logger),
+	}
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, newRepositories(t))
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	repos := newRepositories(t)
+
+	user, err := repos.Users.Create(ctx, &models.DBUser{Email: "john@email.com", PasswordHash: "x", FirstName: "John", LastName: "Doe"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repos.Tasks.Create(ctx, &models.DBTask{UserID: user.ID, Title: fmt.Sprintf("Task %d", i), Priority: models.Low, Status: models.Pending})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := repos.Tasks.GetAllForUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 20)
+}
@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+const (
+	listTasksSelect = "SELECT " + taskColumns + " FROM tasks"
+	countTasksQuery = "SELECT COUNT(*) FROM tasks"
+)
+
+// sortExpressions maps sort fields to expressions that never evaluate to NULL,
+// so they can be compared against keyset cursor values. Enums compare by the
+// rank the cursor stores, and titles case-insensitively like MySQL's default
+// collation.
+var sortExpressions = map[models.TaskSortField]string{
+	models.SortByCreatedAt: "created_at",
+	models.SortByUpdatedAt: "updated_at",
+	models.SortByDueDate:   "COALESCE(due_date, ?)",
+	models.SortByPriority:  "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 ELSE 0 END",
+	models.SortByStatus:    "CASE status WHEN 'pending' THEN 1 WHEN 'in_progress' THEN 2 WHEN 'completed' THEN 3 ELSE 0 END",
+	models.SortByTitle:     "title COLLATE NOCASE",
+}
+
+// sortExpression returns the SQL expression for field together with the
+// arguments its placeholders need.
+func sortExpression(field models.TaskSortField) (string, []any) {
+	expression := sortExpressions[field]
+	if field == models.SortByDueDate {
+		return expression, []any{timestamp(&models.NoDueDate)}
+	}
+	return expression, nil
+}
+
+func buildTaskFilter(filter models.TaskFilter) ([]string, []any) {
+	conditions := []string{"user_id = ?"}
+	args := []any{filter.UserID}
+
+	in := func(column string, values []any) {
+		if len(values) == 0 {
+			return
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, placeholders))
+		args = append(args, values...)
+	}
+
+	statuses := make([]any, 0, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses = append(statuses, status)
+	}
+	in("status", statuses)
+
+	priorities := make([]any, 0, len(filter.Priorities))
+	for _, priority := range filter.Priorities {
+		priorities = append(priorities, priority)
+	}
+	in("priority", priorities)
+
+	categoryIDs := make([]any, 0, len(filter.CategoryIDs))
+	for _, categoryID := range filter.CategoryIDs {
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	in("category_id", categoryIDs)
+
+	bounds := []struct {
+		condition string
+		value     any
+		set       bool
+	}{
+		{"due_date >= ?", timestamp(filter.DueAfter), filter.DueAfter != nil},
+		{"due_date < ?", timestamp(filter.DueBefore), filter.DueBefore != nil},
+		{"created_at >= ?", timestamp(filter.CreatedAfter), filter.CreatedAfter != nil},
+		{"created_at < ?", timestamp(filter.CreatedBefore), filter.CreatedBefore != nil},
+		{"updated_at >= ?", timestamp(filter.UpdatedAfter), filter.UpdatedAfter != nil},
+		{"updated_at < ?", timestamp(filter.UpdatedBefore), filter.UpdatedBefore != nil},
+	}
+	for _, bound := range bounds {
+		if bound.set {
+			conditions = append(conditions, bound.condition)
+			args = append(args, bound.value)
+		}
+	}
+
+	if filter.OverdueAt != nil {
+		conditions = append(conditions, "due_date < ? AND status <> ?")
+		args = append(args, timestamp(filter.OverdueAt), models.Completed)
+	}
+
+	if filter.Ready {
+		conditions = append(conditions, "status <> ? AND NOT EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id WHERE d.task_id = tasks.id AND b.status <> ?)")
+		args = append(args, models.Completed, models.Completed)
+	}
+
+	return conditions, args
+}
+
+func buildTaskCountQuery(filter models.TaskFilter) (string, []any) {
+	conditions, args := buildTaskFilter(filter)
+	return countTasksQuery + " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildTaskListQuery builds the query for one page of tasks. It selects one
+// row more than the limit so the caller can tell whether another page exists.
+func buildTaskListQuery(query models.TaskQuery) (string, []any, error) {
+	conditions, args := buildTaskFilter(query.Filter)
+
+	if query.Cursor != nil {
+		condition, cursorArgs, err := buildCursorCondition(query.Sort, query.Cursor)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, cursorArgs...)
+	}
+
+	orderBy := make([]string, 0, len(query.Sort)+1)
+	for _, s := range query.Sort {
+		expression, expressionArgs := sortExpression(s.Field)
+		direction := "ASC"
+		if s.Descending {
+			direction = "DESC"
+		}
+		orderBy = append(orderBy, expression+" "+direction)
+		args = append(args, expressionArgs...)
+	}
+	orderBy = append(orderBy, "id ASC")
+
+	sqlQuery := fmt.Sprintf("%s WHERE %s ORDER BY %s LIMIT ?",
+		listTasksSelect,
+		strings.Join(conditions, " AND "),
+		strings.Join(orderBy, ", "),
+	)
+	args = append(args, query.Limit+1)
+
+	if query.Cursor == nil && query.Offset > 0 {
+		sqlQuery += " OFFSET ?"
+		args = append(args, query.Offset)
+	}
+
+	return sqlQuery, args, nil
+}
+
+// buildCursorCondition expands the keyset comparison
+// (k1, ..., kn, id) > (v1, ..., vn, lastID) into its OR-of-ANDs form so
+// that every sort key can have its own direction.
+func buildCursorCondition(sort []models.TaskSort, cursor *models.TaskCursor) (string, []any, error) {
+	type key struct {
+		expression string
+		args       []any
+		value      any
+		descending bool
+	}
+
+	keys := make([]key, 0, len(sort)+1)
+	for i, s := range sort {
+		value, err := models.ParseSortValue(s.Field, cursor.Values[i])
+		if err != nil {
+			return "", nil, err
+		}
+		if t, ok := value.(time.Time); ok {
+			value = timestamp(&t)
+		}
+		expression, expressionArgs := sortExpression(s.Field)
+		keys = append(keys, key{expression, expressionArgs, value, s.Descending})
+	}
+	keys = append(keys, key{expression: "id", value: cursor.ID})
+
+	var args []any
+	alternatives := make([]string, 0, len(keys))
+	for i, k := range keys {
+		parts := make([]string, 0, i+1)
+		for _, previous := range keys[:i] {
+			parts = append(parts, previous.expression+" = ?")
+			args = append(args, previous.args...)
+			args = append(args, previous.value)
+		}
+		operator := ">"
+		if k.descending {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", k.expression, operator))
+		args = append(args, k.args...)
+		args = append(args, k.value)
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
@@ -0,0 +1,367 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+)
+
+const (
+	taskColumns        = "id, user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, created_at, updated_at, auto_complete"
+	createTaskQuery    = "INSERT INTO tasks (id, user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, auto_complete) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	getTaskByIDQuery   = "SELECT " + taskColumns + " FROM tasks WHERE id = ?"
+	getTasksByIDsQuery = "SELECT " + taskColumns + " FROM tasks WHERE id IN (%s) ORDER BY created_at, id"
+	getTaskAfterCreate = "SELECT id, created_at FROM tasks WHERE id = ?"
+	getAllTasksForUser = "SELECT " + taskColumns + " FROM tasks WHERE user_id = ?"
+	updateTaskQuery    = "UPDATE tasks SET category_id = ?, parent_id = ?, title = ?, description = ?, priority = ?, status = ?, due_date = ?, completed_at = ?, updated_at = ?, auto_complete = ? WHERE id = ?"
+	deleteTaskQuery    = "DELETE FROM tasks WHERE id = ?"
+	// getSubtreeQuery walks down from a task one level at a time, keeping
+	// the depth of each descendant so the shallowest come first. The path
+	// of IDs each row was reached through stops the walk at a task it has
+	// already seen, so it ends even if the hierarchy has become a cycle.
+	getSubtreeQuery = "WITH RECURSIVE subtree (id, depth, path) AS (" +
+		"SELECT id, 1, ',' || ? || ',' || id || ',' FROM tasks WHERE parent_id = ? " +
+		"UNION ALL SELECT tasks.id, subtree.depth + 1, subtree.path || tasks.id || ',' FROM tasks JOIN subtree ON tasks.parent_id = subtree.id" +
+		" WHERE instr(subtree.path, ',' || tasks.id || ',') = 0" +
+		") SELECT " + taskColumns + " FROM tasks JOIN subtree USING (id) ORDER BY subtree.depth, created_at, id"
+	subtaskProgressQuery = "SELECT parent_id, COUNT(CASE WHEN status = 'completed' THEN 1 END), COUNT(*) FROM tasks WHERE parent_id IN (%s) GROUP BY parent_id"
+)
+
+type taskRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewTaskRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) task.TaskRepository {
+	return &taskRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (t *taskRepository) scanDBTask(rows any) (*models.DBTask, error) {
+	task := &models.DBTask{}
+	var categoryID, parentID, description sql.NullString
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
+	case *sql.Rows:
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
+	default:
+		return nil, fmt.Errorf("unsupported row type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	task.CategoryID = categoryID.String
+	task.ParentID = parentID.String
+	task.Description = description.String
+	return task, nil
+}
+
+func (t *taskRepository) validateRowsAffected(result sql.Result, operation string, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError(operation, err)
+	}
+	if rowsAffected == 0 {
+		return t.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no task found with id %s: %w", id, sql.ErrNoRows))
+	}
+	return nil
+}
+
+func (t *taskRepository) Create(ctx context.Context, task *models.DBTask) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "creating task", slog.String("user_id", task.UserID))
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	task_id := uuid.NewString()
+
+	_, err = tx.ExecContext(ctx, createTaskQuery, task_id, task.UserID, nullableID(task.CategoryID), nullableID(task.ParentID), task.Title, task.Description, task.Priority, task.Status, timestamp(task.DueDate), timestamp(task.CompletedAt), task.AutoComplete)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
+	}
+
+	var createdTask models.DBTask
+	err = tx.QueryRowContext(ctx, getTaskAfterCreate, task_id).Scan(&createdTask.ID, &createdTask.CreatedAt)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "task created", slog.String("task_id", createdTask.ID), slog.String("creation_time", createdTask.CreatedAt.Format(time.RFC3339)))
+	return &createdTask, nil
+}
+
+func (t *taskRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting all tasks for a user", slog.String("user_id", user_id))
+	rows, err := t.db.QueryContext(ctx, getAllTasksForUser, user_id)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
+	}
+
+	tasks := make([]models.DBTask, 0)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got all tasks for user", slog.String("user_id", user_id))
+	return tasks, nil
+}
+
+func (t *taskRepository) List(ctx context.Context, query models.TaskQuery) (*models.TaskPage, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "listing tasks for a user", slog.String("user_id", query.Filter.UserID))
+
+	countQuery, countArgs := buildTaskCountQuery(query.Filter)
+	var total int
+	err := t.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	listQuery, listArgs, err := buildTaskListQuery(query)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	rows, err := t.db.QueryContext(ctx, listQuery, listArgs...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]models.DBTask, 0, query.Limit)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	page := &models.TaskPage{Tasks: tasks, Total: total}
+	if len(tasks) > query.Limit {
+		page.Tasks = tasks[:query.Limit]
+		page.NextCursor = models.NewTaskCursor(query.Sort, page.Tasks[query.Limit-1]).Encode()
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "listed tasks for user", slog.String("user_id", query.Filter.UserID), slog.Int("count", len(page.Tasks)), slog.Int("total", total))
+	return page, nil
+}
+
+func (t *taskRepository) GetById(ctx context.Context, task_id string) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting task by ID", slog.String("task_id", task_id))
+
+	row := t.db.QueryRowContext(ctx, getTaskByIDQuery, task_id)
+	task, err := t.scanDBTask(row)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskByID", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got task", slog.String("task_id", task_id))
+	return task, nil
+}
+
+func (t *taskRepository) GetByIds(ctx context.Context, task_ids []string) ([]models.DBTask, error) {
+	tasks := make([]models.DBTask, 0, len(task_ids))
+	if len(task_ids) == 0 {
+		return tasks, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(task_ids)), ", ")
+	args := make([]any, len(task_ids))
+	for i, id := range task_ids {
+		args[i] = id
+	}
+
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(getTasksByIDsQuery, placeholders), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+	}
+
+	return tasks, nil
+}
+
+func (t *taskRepository) Update(ctx context.Context, task *models.DBTask) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "updating task", slog.String("task_id", task.ID))
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		updateTaskQuery,
+		nullableID(task.CategoryID),
+		nullableID(task.ParentID),
+		task.Title,
+		task.Description,
+		task.Priority,
+		task.Status,
+		timestamp(task.DueDate),
+		timestamp(task.CompletedAt),
+		timestamp(task.UpdatedAt),
+		task.AutoComplete,
+		task.ID,
+	)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
+	}
+
+	if err := t.validateRowsAffected(result, "UpdateTask", task.ID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "updated task", slog.String("task_id", task.ID))
+	return nil
+}
+
+func (t *taskRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "deleting task", slog.String("task_id", id))
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, deleteTaskQuery, id)
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
+	}
+
+	if err := t.validateRowsAffected(result, "DeleteTask", id); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "deleted task", slog.String("task_id", id))
+	return nil
+}
+
+func (t *taskRepository) GetSubtree(ctx context.Context, task_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting subtree of task", slog.String("task_id", task_id))
+
+	rows, err := t.db.QueryContext(ctx, getSubtreeQuery, task_id, task_id)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]models.DBTask, 0)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got subtree of task", slog.String("task_id", task_id), slog.Int("count", len(tasks)))
+	return tasks, nil
+}
+
+func (t *taskRepository) GetSubtaskProgress(ctx context.Context, parent_ids []string) (map[string]models.SubtaskProgress, error) {
+	progress := make(map[string]models.SubtaskProgress)
+	if len(parent_ids) == 0 {
+		return progress, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(parent_ids)), ", ")
+	args := make([]any, len(parent_ids))
+	for i, id := range parent_ids {
+		args[i] = id
+	}
+
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(subtaskProgressQuery, placeholders), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var counts models.SubtaskProgress
+		if err := rows.Scan(&parentID, &counts.Completed, &counts.Total); err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+		}
+		progress[parentID] = counts
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/user"
+)
+
+const (
+	createUserQuery         = "INSERT INTO users (id, email, password_hash, first_name, last_name) VALUES (?, ?, ?, ?, ?)"
+	getUserAfterCreateQuery = "SELECT id, created_at FROM users WHERE id = ?"
+	getUserByIDQuery        = "SELECT * FROM users WHERE id = ?"
+	getUserByEmail          = "SELECT * FROM users WHERE LOWER(email) = LOWER(?)"
+	lockUserQuery           = "SELECT id FROM users WHERE id = ?"
+	deleteUserQuery         = "DELETE FROM users WHERE id = ?"
+	updateUserQuery         = "UPDATE users SET email = ?, first_name = ?, last_name = ? WHERE id = ?"
+)
+
+type userRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewUserRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) user.UserRepository {
+	return &userRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (u *userRepository) scanDBUser(rows any) (*models.DBUser, error) {
+	user := &models.DBUser{}
+	var err error
+	switch r := rows.(type) {
+	case *sql.Row:
+		err = r.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	case *sql.Rows:
+		err = r.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	default:
+		return nil, fmt.Errorf("unsupported row type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *userRepository) validateRowsAffected(result sql.Result, operation string, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError(operation, err)
+	}
+	if rowsAffected == 0 {
+		return u.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no user found with id %s: %w", id, sql.ErrNoRows))
+	}
+	return nil
+}
+
+func (u *userRepository) Create(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "creating user", slog.String("email", user.Email))
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	userID := uuid.NewString()
+
+	_, err = tx.ExecContext(ctx, createUserQuery, userID, user.Email, user.PasswordHash, user.FirstName, user.LastName)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
+	}
+
+	var createdUser models.DBUser
+	err = tx.QueryRowContext(ctx, getUserAfterCreateQuery, userID).Scan(&createdUser.ID, &createdUser.CreatedAt)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "created user", slog.String("user_id", createdUser.ID))
+	return &createdUser, nil
+}
+
+func (u *userRepository) GetById(ctx context.Context, id string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by id", slog.String("user_id", id))
+
+	row := u.db.QueryRowContext(ctx, getUserByIDQuery, id)
+	user, err := u.scanDBUser(row)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("GetUserByID", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user", slog.String("user_id", id))
+	return user, nil
+}
+
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by email", slog.String("email", email))
+
+	row := u.db.QueryRowContext(ctx, getUserByEmail, email)
+	user, err := u.scanDBUser(row)
+	if err != nil {
+		return nil, u.errorHandler.HandleDatabaseError("GetUserByEmail", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user by email", slog.String("user_id", user.ID))
+	return user, nil
+}
+
+func (u *userRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "delete user", slog.String("user_id", id))
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, deleteUserQuery, id)
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
+	}
+
+	if err := u.validateRowsAffected(result, "DeleteUser", id); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "deleted user", slog.String("user_id", id))
+	return nil
+}
+
+func (u *userRepository) Update(ctx context.Context, user *models.DBUser) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "update user", slog.String("user_id", user.ID))
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		updateUserQuery,
+		user.Email,
+		user.FirstName,
+		user.LastName,
+		user.ID,
+	)
+
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
+	}
+
+	if err := u.validateRowsAffected(result, "UpdateUser", user.ID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
+	}
+
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "updated user", slog.String("user_id", user.ID))
+	return nil
+}
+
+// Lock only checks that the user exists: transactions are begun
+// IMMEDIATE, so they already hold the database write lock and run one at a
+// time.
+func (u *userRepository) Lock(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "locking user", slog.String("user_id", id))
+
+	var lockedID string
+	if err := u.db.QueryRowContext(ctx, lockUserQuery, id).Scan(&lockedID); err != nil {
+		return u.errorHandler.HandleDatabaseError("LockUser", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTaskListQuery(t *testing.T) {
+	t.Run("FiltersAndOffset", func(t *testing.T) {
+		dueBefore := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{
+				UserID:     "1244ABC",
+				Statuses:   []models.TaskStatus{models.Pending, models.InProgress},
+				Priorities: []models.TaskPriority{models.High},
+				DueBefore:  &dueBefore,
+			},
+			Sort:   []models.TaskSort{{Field: models.SortByPriority, Descending: true}},
+			Limit:  10,
+			Offset: 20,
+		}
+
+		sqlQuery, args, err := buildTaskListQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, listTasksSelect+
+			" WHERE user_id = ? AND status IN (?, ?) AND priority IN (?) AND due_date < ?"+
+			" ORDER BY FIELD(priority, 'low', 'medium', 'high') DESC, id ASC LIMIT ? OFFSET ?", sqlQuery)
+		assert.Equal(t, []any{"1244ABC", models.Pending, models.InProgress, models.High, &dueBefore, 11, 20}, args)
+	})
+
+	t.Run("KeysetCursor", func(t *testing.T) {
+		createdAt := time.Date(2025, time.June, 29, 19, 10, 51, 0, time.UTC)
+		sort := []models.TaskSort{
+			{Field: models.SortByDueDate},
+			{Field: models.SortByCreatedAt, Descending: true},
+		}
+		cursor := models.NewTaskCursor(sort, models.DBTask{ID: "DSFDS23423", CreatedAt: &createdAt})
+		query := models.TaskQuery{
+			Filter: models.TaskFilter{UserID: "1244ABC"},
+			Sort:   sort,
+			Limit:  5,
+			Offset: 10,
+			Cursor: cursor,
+		}
+
+		sqlQuery, args, err := buildTaskListQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, listTasksSelect+
+			" WHERE user_id = ? AND ((COALESCE(due_date, ?) > ?)"+
+			" OR (COALESCE(due_date, ?) = ? AND created_at < ?)"+
+			" OR (COALESCE(due_date, ?) = ? AND created_at = ? AND id > ?))"+
+			" ORDER BY COALESCE(due_date, ?) ASC, created_at DESC, id ASC LIMIT ?", sqlQuery)
+		assert.Equal(t, []any{
+			"1244ABC",
+			models.NoDueDate, models.NoDueDate,
+			models.NoDueDate, models.NoDueDate, createdAt,
+			models.NoDueDate, models.NoDueDate, createdAt, "DSFDS23423",
+			models.NoDueDate, 6,
+		}, args)
+	})
+}
+
+func TestBuildTaskCountQuery(t *testing.T) {
+	sqlQuery, args := buildTaskCountQuery(models.TaskFilter{UserID: "1244ABC", Ready: true})
+	assert.Equal(t, countTasksQuery+" WHERE user_id = ? AND status <> ? AND NOT EXISTS"+
+		" (SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id WHERE d.task_id = tasks.id AND b.status <> ?)", sqlQuery)
+	assert.Equal(t, []any{"1244ABC", models.Completed, models.Completed}, args)
+}
+
+func TestTaskCursorRoundTrip(t *testing.T) {
+	sort := []models.TaskSort{{Field: models.SortByPriority}, {Field: models.SortByTitle, Descending: true}}
+	task := models.DBTask{ID: "abc", Priority: models.High, Title: "Sweep Floor"}
+
+	encoded := models.NewTaskCursor(sort, task).Encode()
+	decoded, err := models.DecodeTaskCursor(encoded, sort)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3", "Sweep Floor"}, decoded.Values)
+	assert.Equal(t, "abc", decoded.ID)
+
+	_, err = models.DecodeTaskCursor(encoded, sort[:1])
+	assert.Error(t, err)
+
+	_, err = models.DecodeTaskCursor("not a cursor", sort)
+	assert.Error(t, err)
+}
@@ -1,11 +1,25 @@
 package task
 
-import "github.com/kjj1998/task-management-system/internal/models"
+import (
+	"context"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
 
 type TaskRepository interface {
-	Create(task *models.DBTask) (*models.DBTask, error)
-	GetAllForUser(user_id string) ([]models.DBTask, error)
-	GetById(task_id string) (*models.DBTask, error)
-	Update(task *models.DBTask) error
-	Delete(task_id string) error
+	Create(ctx context.Context, task *models.DBTask) (*models.DBTask, error)
+	GetAllForUser(ctx context.Context, user_id string) ([]models.DBTask, error)
+	List(ctx context.Context, query models.TaskQuery) (*models.TaskPage, error)
+	GetById(ctx context.Context, task_id string) (*models.DBTask, error)
+	// GetByIds returns the tasks with the given IDs, oldest first. IDs
+	// without a task are left out of the result.
+	GetByIds(ctx context.Context, task_ids []string) ([]models.DBTask, error)
+	Update(ctx context.Context, task *models.DBTask) error
+	Delete(ctx context.Context, task_id string) error
+	// GetSubtree returns the tasks nested under the task at any depth,
+	// shallowest first.
+	GetSubtree(ctx context.Context, task_id string) ([]models.DBTask, error)
+	// GetSubtaskProgress counts the direct subtasks of each parent. Parents
+	// without subtasks are left out of the result.
+	GetSubtaskProgress(ctx context.Context, parent_ids []string) (map[string]models.SubtaskProgress, error)
 }
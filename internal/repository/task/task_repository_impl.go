@@ -1,32 +1,48 @@
 package task
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
 	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/models"
 )
 
 const (
-	createTaskQuery    = "INSERT INTO tasks (id, user_id, category_id, title, description, priority, status, due_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
-	getTaskByIDQuery   = "SELECT * FROM tasks WHERE id = ?"
+	taskColumns        = "id, user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, created_at, updated_at, auto_complete"
+	createTaskQuery    = "INSERT INTO tasks (id, user_id, category_id, parent_id, title, description, priority, status, due_date, completed_at, auto_complete) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	getTaskByIDQuery   = "SELECT " + taskColumns + " FROM tasks WHERE id = ?"
+	getTasksByIDsQuery = "SELECT " + taskColumns + " FROM tasks WHERE id IN (%s) ORDER BY created_at, id"
 	getTaskAfterCreate = "SELECT id, created_at FROM tasks WHERE id = ?"
-	getAllTasksForUser = "SELECT id, user_id, category_id, title, description, priority, status, due_date, completed_at, created_at, updated_at FROM tasks WHERE user_id = ?"
-	updateTaskQuery    = "UPDATE tasks SET title = ?, description = ?, priority = ?, status = ?, due_date = ?, completed_at = ?, updated_at = ? WHERE id = ?"
+	getAllTasksForUser = "SELECT " + taskColumns + " FROM tasks WHERE user_id = ?"
+	updateTaskQuery    = "UPDATE tasks SET category_id = ?, parent_id = ?, title = ?, description = ?, priority = ?, status = ?, due_date = ?, completed_at = ?, updated_at = ?, auto_complete = ? WHERE id = ?"
 	deleteTaskQuery    = "DELETE FROM tasks WHERE id = ?"
+	// getSubtreeQuery walks down from a task one level at a time, keeping
+	// the depth of each descendant so the shallowest come first. The path
+	// of IDs each row was reached through stops the walk at a task it has
+	// already seen, so it ends even if the hierarchy has become a cycle.
+	getSubtreeQuery = "WITH RECURSIVE subtree (id, depth, path) AS (" +
+		"SELECT id, 1, CAST(CONCAT(',', ?, ',', id, ',') AS CHAR(10000)) FROM tasks WHERE parent_id = ? " +
+		"UNION ALL SELECT tasks.id, subtree.depth + 1, CONCAT(subtree.path, tasks.id, ',') FROM tasks JOIN subtree ON tasks.parent_id = subtree.id" +
+		" WHERE LOCATE(CONCAT(',', tasks.id, ','), subtree.path) = 0" +
+		") SELECT " + taskColumns + " FROM tasks JOIN subtree USING (id) ORDER BY subtree.depth, created_at, id"
+	subtaskProgressQuery = "SELECT parent_id, COUNT(CASE WHEN status = 'completed' THEN 1 END), COUNT(*) FROM tasks WHERE parent_id IN (%s) GROUP BY parent_id"
 )
 
 type taskRepository struct {
-	db           *sql.DB
+	db           database.Conn
 	errorHandler *errors.DatabaseErrorHandler
 	logger       *slog.Logger
 }
 
-func NewTaskRepository(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) TaskRepository {
+func NewTaskRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) TaskRepository {
 	return &taskRepository{
 		db:           db,
 		errorHandler: errorHandler,
@@ -36,53 +52,63 @@ func NewTaskRepository(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, lo
 
 func (t *taskRepository) scanDBTask(rows any) (*models.DBTask, error) {
 	task := &models.DBTask{}
+	var categoryID, parentID, description sql.NullString
 	var err error
 	switch r := rows.(type) {
 	case *sql.Row:
-		err = r.Scan(&task.ID, &task.UserID, &task.CategoryID, &task.Title, &task.Description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt)
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
 	case *sql.Rows:
-		err = r.Scan(&task.ID, &task.UserID, &task.CategoryID, &task.Title, &task.Description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt)
+		err = r.Scan(&task.ID, &task.UserID, &categoryID, &parentID, &task.Title, &description, &task.Priority, &task.Status, &task.DueDate, &task.CompletedAt, &task.CreatedAt, &task.UpdatedAt, &task.AutoComplete)
 	default:
 		return nil, fmt.Errorf("unsupported row type")
 	}
 	if err != nil {
 		return nil, err
 	}
+	task.CategoryID = categoryID.String
+	task.ParentID = parentID.String
+	task.Description = description.String
 	return task, nil
 }
 
+// nullableID maps an empty ID to NULL so optional foreign keys are not
+// checked against a row that cannot exist.
+func nullableID(id string) sql.NullString {
+	return sql.NullString{String: id, Valid: id != ""}
+}
+
 func (t *taskRepository) validateRowsAffected(result sql.Result, operation string, id string) error {
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return t.errorHandler.HandleDatabaseError(operation, err)
 	}
 	if rowsAffected == 0 {
-		return t.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no task found with id %s", id))
+		return t.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no task found with id %s: %w", id, sql.ErrNoRows))
 	}
 	return nil
 }
 
-func (t *taskRepository) Create(task *models.DBTask) (*models.DBTask, error) {
-	t.logger.Debug("creating task", slog.String("user_id", task.UserID))
-	tx, err := t.db.Begin()
+func (t *taskRepository) Create(ctx context.Context, task *models.DBTask) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "creating task", slog.String("user_id", task.UserID))
+	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			t.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
 	task_id := uuid.NewString()
 
-	_, err = tx.Exec(createTaskQuery, task_id, task.UserID, task.CategoryID, task.Title, task.Description, task.Priority, task.Status, task.DueDate)
+	_, err = tx.ExecContext(ctx, createTaskQuery, task_id, task.UserID, nullableID(task.CategoryID), nullableID(task.ParentID), task.Title, task.Description, task.Priority, task.Status, task.DueDate, task.CompletedAt, task.AutoComplete)
 	if err != nil {
 		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
 	}
 
 	var createdTask models.DBTask
-	err = tx.QueryRow(getTaskAfterCreate, task_id).Scan(&createdTask.ID, &createdTask.CreatedAt)
+	err = tx.QueryRowContext(ctx, getTaskAfterCreate, task_id).Scan(&createdTask.ID, &createdTask.CreatedAt)
 	if err != nil {
 		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
 	}
@@ -92,13 +118,13 @@ func (t *taskRepository) Create(task *models.DBTask) (*models.DBTask, error) {
 		return nil, t.errorHandler.HandleDatabaseError("CreateTask", err)
 	}
 
-	t.logger.Info("task created", slog.String("task_id", createdTask.ID), slog.String("creation_time", createdTask.CreatedAt.Format(time.RFC3339)))
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "task created", slog.String("task_id", createdTask.ID), slog.String("creation_time", createdTask.CreatedAt.Format(time.RFC3339)))
 	return &createdTask, nil
 }
 
-func (t *taskRepository) GetAllForUser(user_id string) ([]models.DBTask, error) {
-	t.logger.Debug("getting all tasks for a user", slog.String("user_id", user_id))
-	rows, err := t.db.Query(getAllTasksForUser, user_id)
+func (t *taskRepository) GetAllForUser(ctx context.Context, user_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting all tasks for a user", slog.String("user_id", user_id))
+	rows, err := t.db.QueryContext(ctx, getAllTasksForUser, user_id)
 	if err != nil {
 		return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
 	}
@@ -116,38 +142,117 @@ func (t *taskRepository) GetAllForUser(user_id string) ([]models.DBTask, error)
 		return nil, t.errorHandler.HandleDatabaseError("GetAllTasksForUser", err)
 	}
 
-	t.logger.Info("got all tasks for user", slog.String("user_id", user_id))
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got all tasks for user", slog.String("user_id", user_id))
 	return tasks, nil
 }
 
-func (t *taskRepository) GetById(task_id string) (*models.DBTask, error) {
-	t.logger.Debug("getting task by ID", slog.String("task_id", task_id))
+func (t *taskRepository) List(ctx context.Context, query models.TaskQuery) (*models.TaskPage, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "listing tasks for a user", slog.String("user_id", query.Filter.UserID))
+
+	countQuery, countArgs := buildTaskCountQuery(query.Filter)
+	var total int
+	err := t.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	listQuery, listArgs, err := buildTaskListQuery(query)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	rows, err := t.db.QueryContext(ctx, listQuery, listArgs...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]models.DBTask, 0, query.Limit)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("ListTasks", err)
+	}
+
+	page := &models.TaskPage{Tasks: tasks, Total: total}
+	if len(tasks) > query.Limit {
+		page.Tasks = tasks[:query.Limit]
+		page.NextCursor = models.NewTaskCursor(query.Sort, page.Tasks[query.Limit-1]).Encode()
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "listed tasks for user", slog.String("user_id", query.Filter.UserID), slog.Int("count", len(page.Tasks)), slog.Int("total", total))
+	return page, nil
+}
+
+func (t *taskRepository) GetById(ctx context.Context, task_id string) (*models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting task by ID", slog.String("task_id", task_id))
 
-	row := t.db.QueryRow(getTaskByIDQuery, task_id)
+	row := t.db.QueryRowContext(ctx, getTaskByIDQuery, task_id)
 	task, err := t.scanDBTask(row)
 	if err != nil {
 		return nil, t.errorHandler.HandleDatabaseError("GetTaskByID", err)
 	}
 
-	t.logger.Info("got task", slog.String("task_id", task_id))
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got task", slog.String("task_id", task_id))
 	return task, nil
 }
 
-func (t *taskRepository) Update(task *models.DBTask) error {
-	t.logger.Debug("updating task", slog.String("task_id", task.ID))
+func (t *taskRepository) GetByIds(ctx context.Context, task_ids []string) ([]models.DBTask, error) {
+	tasks := make([]models.DBTask, 0, len(task_ids))
+	if len(task_ids) == 0 {
+		return tasks, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(task_ids)), ", ")
+	args := make([]any, len(task_ids))
+	for i, id := range task_ids {
+		args[i] = id
+	}
+
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(getTasksByIDsQuery, placeholders), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTasksByIDs", err)
+	}
+
+	return tasks, nil
+}
+
+func (t *taskRepository) Update(ctx context.Context, task *models.DBTask) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "updating task", slog.String("task_id", task.ID))
 
-	tx, err := t.db.Begin()
+	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
 		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			t.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
-	result, err := tx.Exec(
+	result, err := tx.ExecContext(ctx,
 		updateTaskQuery,
+		nullableID(task.CategoryID),
+		nullableID(task.ParentID),
 		task.Title,
 		task.Description,
 		task.Priority,
@@ -155,6 +260,7 @@ func (t *taskRepository) Update(task *models.DBTask) error {
 		task.DueDate,
 		task.CompletedAt,
 		task.UpdatedAt,
+		task.AutoComplete,
 		task.ID,
 	)
 	if err != nil {
@@ -170,24 +276,24 @@ func (t *taskRepository) Update(task *models.DBTask) error {
 		return t.errorHandler.HandleDatabaseError("UpdateTask", err)
 	}
 
-	t.logger.Info("updated task", slog.String("task_id", task.ID))
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "updated task", slog.String("task_id", task.ID))
 	return nil
 }
 
-func (t *taskRepository) Delete(id string) error {
-	t.logger.Debug("deleting task", slog.String("task_id", id))
+func (t *taskRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "deleting task", slog.String("task_id", id))
 
-	tx, err := t.db.Begin()
+	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
 		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			t.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, t.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
-	result, err := tx.Exec(deleteTaskQuery, id)
+	result, err := tx.ExecContext(ctx, deleteTaskQuery, id)
 	if err != nil {
 		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
 	}
@@ -201,6 +307,66 @@ func (t *taskRepository) Delete(id string) error {
 		return t.errorHandler.HandleDatabaseError("DeleteTask", err)
 	}
 
-	t.logger.Info("deleted task", slog.String("task_id", id))
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "deleted task", slog.String("task_id", id))
 	return nil
 }
+
+func (t *taskRepository) GetSubtree(ctx context.Context, task_id string) ([]models.DBTask, error) {
+	logger.FromContext(ctx, t.logger).DebugContext(ctx, "getting subtree of task", slog.String("task_id", task_id))
+
+	rows, err := t.db.QueryContext(ctx, getSubtreeQuery, task_id, task_id)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]models.DBTask, 0)
+	for rows.Next() {
+		task, err := t.scanDBTask(rows)
+		if err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetTaskSubtree", err)
+	}
+
+	logger.FromContext(ctx, t.logger).InfoContext(ctx, "got subtree of task", slog.String("task_id", task_id), slog.Int("count", len(tasks)))
+	return tasks, nil
+}
+
+func (t *taskRepository) GetSubtaskProgress(ctx context.Context, parent_ids []string) (map[string]models.SubtaskProgress, error) {
+	progress := make(map[string]models.SubtaskProgress)
+	if len(parent_ids) == 0 {
+		return progress, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(parent_ids)), ", ")
+	args := make([]any, len(parent_ids))
+	for i, id := range parent_ids {
+		args[i] = id
+	}
+
+	rows, err := t.db.QueryContext(ctx, fmt.Sprintf(subtaskProgressQuery, placeholders), args...)
+	if err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var counts models.SubtaskProgress
+		if err := rows.Scan(&parentID, &counts.Completed, &counts.Total); err != nil {
+			return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+		}
+		progress[parentID] = counts
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, t.errorHandler.HandleDatabaseError("GetSubtaskProgress", err)
+	}
+
+	return progress, nil
+}
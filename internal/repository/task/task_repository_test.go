@@ -3,6 +3,7 @@ package task_test
 import (
 	"context"
 	"log"
+	"net/http"
 	"testing"
 	"time"
 
@@ -40,7 +41,7 @@ func (suite *TaskRepoTestSuite) SetupSuite() {
 	suite.Require().NoError(err, "Failed to connect to test database")
 	db := database.GetDb()
 	dbErrorHandler := errors.NewDatabaseErrorHandler()
-	taskRepository := task.NewTaskRepository(db, dbErrorHandler, logger)
+	taskRepository := task.NewTaskRepository(database.NewConn(db), dbErrorHandler, logger)
 	suite.repository = taskRepository
 }
 
@@ -65,7 +66,7 @@ func (suite *TaskRepoTestSuite) TestTaskRepositoryOperations() {
 			DueDate:     &dueDate,
 		}
 
-		createdTask, err := suite.repository.Create(task)
+		createdTask, err := suite.repository.Create(suite.ctx, task)
 		assert.NoError(t, err)
 		assert.NotNil(t, createdTask)
 
@@ -75,7 +76,7 @@ func (suite *TaskRepoTestSuite) TestTaskRepositoryOperations() {
 	})
 
 	t.Run("GetAllTasksForUser", func(t *testing.T) {
-		tasks, err := suite.repository.GetAllForUser("1244ABC")
+		tasks, err := suite.repository.GetAllForUser(suite.ctx, "1244ABC")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, tasks)
@@ -98,10 +99,10 @@ func (suite *TaskRepoTestSuite) TestTaskRepositoryOperations() {
 			CompletedAt: &completedTime,
 		}
 
-		err := suite.repository.Update(task)
+		err := suite.repository.Update(suite.ctx, task)
 		assert.NoError(t, err)
 
-		updatedTask, err := suite.repository.GetById("DSFDS23423")
+		updatedTask, err := suite.repository.GetById(suite.ctx, "DSFDS23423")
 		assert.NoError(t, err)
 		assert.NotNil(t, updatedTask)
 		assert.Equal(t, "Collect Parcel", updatedTask.Title)
@@ -111,14 +112,57 @@ func (suite *TaskRepoTestSuite) TestTaskRepositoryOperations() {
 		assert.Equal(t, &completedTime, updatedTask.CompletedAt)
 	})
 
+	t.Run("ListTasks", func(t *testing.T) {
+		sort := []models.TaskSort{{Field: models.SortByCreatedAt, Descending: true}}
+		firstPage, err := suite.repository.List(suite.ctx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: "1244ABC"},
+			Sort:   sort,
+			Limit:  1,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, firstPage.Total)
+		assert.Len(t, firstPage.Tasks, 1)
+		assert.NotEmpty(t, firstPage.NextCursor)
+
+		cursor, err := models.DecodeTaskCursor(firstPage.NextCursor, sort)
+		assert.NoError(t, err)
+		secondPage, err := suite.repository.List(suite.ctx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: "1244ABC"},
+			Sort:   sort,
+			Limit:  1,
+			Cursor: cursor,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, secondPage.Tasks, 1)
+		assert.Empty(t, secondPage.NextCursor)
+		assert.NotEqual(t, firstPage.Tasks[0].ID, secondPage.Tasks[0].ID)
+
+		completed, err := suite.repository.List(suite.ctx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: "1244ABC", Statuses: []models.TaskStatus{models.Completed}},
+			Sort:   sort,
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, completed.Total)
+		assert.Equal(t, "DSFDS23423", completed.Tasks[0].ID)
+	})
+
 	t.Run("DeleteTask", func(t *testing.T) {
-		err := suite.repository.Delete("DSFDS23423")
+		err := suite.repository.Delete(suite.ctx, "DSFDS23423")
 		assert.NoError(t, err)
 
-		_, err = suite.repository.GetById("DSFDS23423")
+		_, err = suite.repository.GetById(suite.ctx, "DSFDS23423")
 		expectedErrorMessage := "Resource not found, sql: no rows in result set"
 		assert.Contains(t, err.Error(), expectedErrorMessage)
 	})
+
+	t.Run("DeleteMissingTask", func(t *testing.T) {
+		err := suite.repository.Delete(suite.ctx, "DSFDS23423")
+
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
 }
 
 func TestCategoryRepoTestSuite(t *testing.T) {
@@ -1,41 +0,0 @@
-CREATE TABLE users (
-    id CHAR(36) PRIMARY KEY,
-    email VARCHAR(255) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    first_name VARCHAR(100) NOT NULL,
-    last_name VARCHAR(100) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-);
-
-CREATE TABLE categories (
-    id CHAR(36) PRIMARY KEY,
-    user_id CHAR(36) NOT NULL,
-    name VARCHAR(100) NOT NULL,
-    color VARCHAR(7) DEFAULT '#007bff',
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_user_category (user_id, name)
-);
-
-CREATE TABLE tasks (
-    id CHAR(36) PRIMARY KEY,
-    user_id CHAR(36) NOT NULL,
-    category_id CHAR(36),
-    title VARCHAR(200) NOT NULL,
-    description TEXT,
-    priority ENUM('low', 'medium', 'high') DEFAULT 'medium',
-    status ENUM('pending', 'in_progress', 'completed') DEFAULT 'pending',
-    due_date DATETIME,
-    completed_at TIMESTAMP NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
-);
-
-INSERT INTO users (id, email, password_hash, first_name, last_name) VALUES ('1244ABC', 'john@email.com', 'DSFE32423X', 'John', 'Doe');
-
-INSERT INTO categories (id, user_id, name) VALUES ('2345SDSXAS', '1244ABC', 'routine');
-
-INSERT INTO tasks (id, user_id, category_id, title, description, priority, status, due_date) VALUES ('DSFDS23423', '1244ABC', '2345SDSXAS', 'Sweep Floor', 'Sweep the floor of my room', 'medium', 'pending', '2025-06-29 19:10:51');
\ No newline at end of file
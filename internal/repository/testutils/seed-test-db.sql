@@ -0,0 +1,5 @@
+INSERT INTO users (id, email, password_hash, first_name, last_name) VALUES ('1244ABC', 'john@email.com', 'DSFE32423X', 'John', 'Doe');
+
+INSERT INTO categories (id, user_id, name) VALUES ('2345SDSXAS', '1244ABC', 'routine');
+
+INSERT INTO tasks (id, user_id, category_id, title, description, priority, status, due_date) VALUES ('DSFDS23423', '1244ABC', '2345SDSXAS', 'Sweep Floor', 'Sweep the floor of my room', 'medium', 'pending', '2025-06-29 19:10:51');
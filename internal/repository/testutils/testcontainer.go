@@ -2,36 +2,37 @@ package testutils
 
 import (
 	"context"
+	"database/sql"
+	_ "embed"
 	"fmt"
 	"log"
-	"os"
+	"log/slog"
+	"net"
+	"net/url"
 	"path/filepath"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/migrate"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// seedTestDB is the data the MySQL suites expect on top of the schema.
+//
+//go:embed seed-test-db.sql
+var seedTestDB string
+
 type MySQLContainer struct {
 	Container testcontainers.Container
 }
 
+// CreateMySQLContainer starts a MySQL server with the embedded migrations
+// applied and the rows in seed-test-db.sql inserted.
 func CreateMySQLContainer(ctx context.Context) (*MySQLContainer, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatalf("error getting current directory: %v", err)
-	}
-	fmt.Println("Current working directory:", dir)
-	absPath, err := filepath.Abs(filepath.Join("..", "testutils", "init-test-db.sql"))
-	fmt.Println(absPath)
-	if err != nil {
-		log.Fatal(err)
-	}
-	r, err := os.Open(absPath)
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	req := testcontainers.ContainerRequest{
 		Image: "mysql:8.0",
 		Env: map[string]string{
@@ -42,14 +43,6 @@ func CreateMySQLContainer(ctx context.Context) (*MySQLContainer, error) {
 		},
 		ExposedPorts: []string{"3306/tcp"},
 		WaitingFor:   wait.ForLog("port: 3306  MySQL Community Server - GPL").WithStartupTimeout(30 * time.Second),
-		Files: []testcontainers.ContainerFile{
-			{
-				Reader:            r,
-				HostFilePath:      absPath,
-				ContainerFilePath: "/docker-entrypoint-initdb.d/init-db.sql",
-				FileMode:          0o755,
-			},
-		},
 	}
 
 	mysqlC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
@@ -60,7 +53,125 @@ func CreateMySQLContainer(ctx context.Context) (*MySQLContainer, error) {
 		log.Fatal(err)
 	}
 
+	host, err := mysqlC.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	port, err := mysqlC.MappedPort(ctx, "3306")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = "testuser"
+	cfg.Passwd = "testpass"
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(host, port.Port())
+	cfg.DBName = "taskapi"
+	// The seed file holds several statements.
+	cfg.MultiStatements = true
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err := migrateUp(ctx, db, config.DriverMySQL); err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, seedTestDB); err != nil {
+		return nil, fmt.Errorf("seeding test database: %w", err)
+	}
+
 	return &MySQLContainer{
 		Container: mysqlC,
 	}, nil
 }
+
+type PostgresContainer struct {
+	Container testcontainers.Container
+}
+
+// CreatePostgresContainer starts a PostgreSQL server with the embedded
+// migrations applied.
+func CreatePostgresContainer(ctx context.Context) (*PostgresContainer, error) {
+	req := testcontainers.ContainerRequest{
+		Image: "postgres:16-alpine",
+		Env: map[string]string{
+			"POSTGRES_DB":       "taskapi",
+			"POSTGRES_USER":     "testuser",
+			"POSTGRES_PASSWORD": "testpass",
+		},
+		ExposedPorts: []string{"5432/tcp"},
+		// The server restarts once after initialising the database.
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(30 * time.Second),
+	}
+
+	postgresC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	host, err := postgresC.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	port, err := postgresC.MappedPort(ctx, "5432")
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := (&url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword("testuser", "testpass"),
+		Host:   net.JoinHostPort(host, port.Port()),
+		Path:   "taskapi",
+	}).String()
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if err := migrateUp(ctx, db, config.DriverPostgres); err != nil {
+		return nil, err
+	}
+
+	return &PostgresContainer{
+		Container: postgresC,
+	}, nil
+}
+
+// CreateSQLiteDatabase opens a SQLite database file in dir with the
+// embedded migrations applied. It needs no container.
+func CreateSQLiteDatabase(dir string, logger *slog.Logger) (*sql.DB, error) {
+	if err := database.ConnectSQLite(filepath.Join(dir, "taskapi.db"), logger); err != nil {
+		return nil, err
+	}
+	db := database.GetDb()
+
+	if err := migrateUp(context.Background(), db, config.DriverSQLite); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrateUp applies the migrations the server itself runs, so the test
+// schema cannot drift from the real one.
+func migrateUp(ctx context.Context, db *sql.DB, driver string) error {
+	migrator, err := migrate.New(db, driver, logger.NewLogger("test"))
+	if err != nil {
+		return err
+	}
+	if err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("migrating test database: %w", err)
+	}
+
+	return nil
+}
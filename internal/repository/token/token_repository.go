@@ -0,0 +1,14 @@
+package token
+
+import (
+	"context"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.DBRefreshToken) (*models.DBRefreshToken, error)
+	GetByHash(ctx context.Context, token_hash string) (*models.DBRefreshToken, error)
+	Revoke(ctx context.Context, token_id string, replaced_by string) error
+	RevokeFamily(ctx context.Context, family_id string) error
+}
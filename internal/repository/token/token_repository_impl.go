@@ -0,0 +1,130 @@
+package token
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+const (
+	createRefreshTokenQuery       = "INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?, ?)"
+	getRefreshTokenAfterCreate    = "SELECT id, created_at FROM refresh_tokens WHERE id = ?"
+	getRefreshTokenByHashQuery    = "SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at FROM refresh_tokens WHERE token_hash = ?"
+	revokeRefreshTokenQuery       = "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP, replaced_by = ? WHERE id = ? AND revoked_at IS NULL"
+	revokeRefreshTokenFamilyQuery = "UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = ? AND revoked_at IS NULL"
+)
+
+type refreshTokenRepository struct {
+	db           database.Conn
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+}
+
+func NewRefreshTokenRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db:           db,
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+}
+
+func (r *refreshTokenRepository) scanDBRefreshToken(row *sql.Row) (*models.DBRefreshToken, error) {
+	token := &models.DBRefreshToken{}
+	var replacedBy sql.NullString
+	err := row.Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.RevokedAt, &replacedBy, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	token.ReplacedBy = replacedBy.String
+	return token, nil
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.DBRefreshToken) (*models.DBRefreshToken, error) {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "creating refresh token", slog.String("user_id", token.UserID), slog.String("family_id", token.FamilyID))
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("CreateRefreshToken", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logger.FromContext(ctx, r.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+		}
+	}()
+
+	tokenID := uuid.NewString()
+
+	_, err = tx.ExecContext(ctx, createRefreshTokenQuery, tokenID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("CreateRefreshToken", err)
+	}
+
+	var createdToken models.DBRefreshToken
+	err = tx.QueryRowContext(ctx, getRefreshTokenAfterCreate, tokenID).Scan(&createdToken.ID, &createdToken.CreatedAt)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("CreateRefreshToken", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("CreateRefreshToken", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token created", slog.String("token_id", createdToken.ID))
+	return &createdToken, nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, token_hash string) (*models.DBRefreshToken, error) {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "getting refresh token by hash")
+
+	row := r.db.QueryRowContext(ctx, getRefreshTokenByHashQuery, token_hash)
+	token, err := r.scanDBRefreshToken(row)
+	if err != nil {
+		return nil, r.errorHandler.HandleDatabaseError("GetRefreshTokenByHash", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "got refresh token", slog.String("token_id", token.ID))
+	return token, nil
+}
+
+// Revoke marks a token as used. It fails with a not found error when the
+// token is missing or was already revoked, so only one of several concurrent
+// rotations of the same token can succeed.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, token_id string, replaced_by string) error {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "revoking refresh token", slog.String("token_id", token_id))
+
+	result, err := r.db.ExecContext(ctx, revokeRefreshTokenQuery, sql.NullString{String: replaced_by, Valid: replaced_by != ""}, token_id)
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", err)
+	}
+	if rowsAffected == 0 {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshToken", fmt.Errorf("no active refresh token found with id %s: %w", token_id, sql.ErrNoRows))
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token revoked", slog.String("token_id", token_id))
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, family_id string) error {
+	logger.FromContext(ctx, r.logger).DebugContext(ctx, "revoking refresh token family", slog.String("family_id", family_id))
+
+	_, err := r.db.ExecContext(ctx, revokeRefreshTokenFamilyQuery, family_id)
+	if err != nil {
+		return r.errorHandler.HandleDatabaseError("RevokeRefreshTokenFamily", err)
+	}
+
+	logger.FromContext(ctx, r.logger).InfoContext(ctx, "refresh token family revoked", slog.String("family_id", family_id))
+	return nil
+}
@@ -0,0 +1,120 @@
+package token_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/kjj1998/task-management-system/internal/repository/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenRepoTestSuite struct {
+	suite.Suite
+	mySQLContainer *testutils.MySQLContainer
+	ctx            context.Context
+	repository     token.RefreshTokenRepository
+}
+
+func (suite *RefreshTokenRepoTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	mySQLContainer, err := testutils.CreateMySQLContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.mySQLContainer = mySQLContainer
+	host, _ := mySQLContainer.Container.Host(suite.ctx)
+	port, _ := mySQLContainer.Container.MappedPort(suite.ctx, "3306")
+
+	err = database.Connect("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	db := database.GetDb()
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+	tokenRepository := token.NewRefreshTokenRepository(database.NewConn(db), dbErrorHandler, logger)
+	suite.repository = tokenRepository
+}
+
+func (suite *RefreshTokenRepoTestSuite) TearDownSuite() {
+	if err := suite.mySQLContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating mysql container: %s", err)
+	}
+}
+
+func (suite *RefreshTokenRepoTestSuite) TestRefreshTokenRotation() {
+	t := suite.T()
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	var firstTokenID, secondTokenID string
+
+	t.Run("CreateRefreshToken", func(t *testing.T) {
+		created, err := suite.repository.Create(suite.ctx, &models.DBRefreshToken{
+			UserID:    "1244ABC",
+			FamilyID:  "family-1",
+			TokenHash: "a1",
+			ExpiresAt: expiresAt,
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, created.ID)
+		firstTokenID = created.ID
+
+		created, err = suite.repository.Create(suite.ctx, &models.DBRefreshToken{
+			UserID:    "1244ABC",
+			FamilyID:  "family-1",
+			TokenHash: "b2",
+			ExpiresAt: expiresAt,
+		})
+		assert.NoError(t, err)
+		secondTokenID = created.ID
+
+		if t.Failed() {
+			t.Fatal("CreateRefreshToken failed, stopping sequential execution")
+		}
+	})
+
+	t.Run("GetRefreshTokenByHash", func(t *testing.T) {
+		stored, err := suite.repository.GetByHash(suite.ctx, "a1")
+		assert.NoError(t, err)
+		assert.Equal(t, firstTokenID, stored.ID)
+		assert.Equal(t, "family-1", stored.FamilyID)
+		assert.Equal(t, expiresAt, stored.ExpiresAt)
+		assert.Nil(t, stored.RevokedAt)
+	})
+
+	t.Run("RevokeRefreshToken", func(t *testing.T) {
+		err := suite.repository.Revoke(suite.ctx, firstTokenID, secondTokenID)
+		assert.NoError(t, err)
+
+		stored, err := suite.repository.GetByHash(suite.ctx, "a1")
+		assert.NoError(t, err)
+		assert.NotNil(t, stored.RevokedAt)
+		assert.Equal(t, secondTokenID, stored.ReplacedBy)
+
+		err = suite.repository.Revoke(suite.ctx, firstTokenID, secondTokenID)
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("RevokeRefreshTokenFamily", func(t *testing.T) {
+		err := suite.repository.RevokeFamily(suite.ctx, "family-1")
+		assert.NoError(t, err)
+
+		stored, err := suite.repository.GetByHash(suite.ctx, "b2")
+		assert.NoError(t, err)
+		assert.NotNil(t, stored.RevokedAt)
+	})
+}
+
+func TestRefreshTokenRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepoTestSuite))
+}
@@ -1,11 +1,19 @@
 package user
 
-import "github.com/kjj1998/task-management-system/internal/models"
+import (
+	"context"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+)
 
 type UserRepository interface {
-	GetById(id string) (*models.DBUser, error)
-	GetByEmail(email string) (*models.DBUser, error)
-	Create(user *models.DBUser) (*models.DBUser, error)
-	Update(user *models.DBUser) error
-	Delete(id string) error
+	GetById(ctx context.Context, id string) (*models.DBUser, error)
+	GetByEmail(ctx context.Context, email string) (*models.DBUser, error)
+	Create(ctx context.Context, user *models.DBUser) (*models.DBUser, error)
+	Update(ctx context.Context, user *models.DBUser) error
+	Delete(ctx context.Context, id string) error
+	// Lock locks the user until the end of the transaction it runs in, so
+	// units of work that check and then change the user's tasks run one at
+	// a time.
+	Lock(ctx context.Context, id string) error
 }
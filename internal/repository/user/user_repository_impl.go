@@ -1,12 +1,15 @@
 package user
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/database"
 	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/models"
 )
 
@@ -15,17 +18,18 @@ const (
 	getUserAfterCreateQuery = "SELECT id, created_at FROM users WHERE id = ?"
 	getUserByIDQuery        = "SELECT * FROM users WHERE id = ?"
 	getUserByEmail          = "SELECT * FROM users WHERE email = ?"
+	lockUserQuery           = "SELECT id FROM users WHERE id = ? FOR UPDATE"
 	deleteUserQuery         = "DELETE FROM users WHERE id = ?"
 	updateUserQuery         = "UPDATE users SET email = ?, first_name = ?, last_name = ? WHERE id = ?"
 )
 
 type userRepository struct {
-	db           *sql.DB
+	db           database.Conn
 	errorHandler *errors.DatabaseErrorHandler
 	logger       *slog.Logger
 }
 
-func NewUserRepository(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) UserRepository {
+func NewUserRepository(db database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) UserRepository {
 	return &userRepository{
 		db:           db,
 		errorHandler: errorHandler,
@@ -56,33 +60,33 @@ func (u *userRepository) validateRowsAffected(result sql.Result, operation strin
 		return u.errorHandler.HandleDatabaseError(operation, err)
 	}
 	if rowsAffected == 0 {
-		return u.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no user found with id %s", id))
+		return u.errorHandler.HandleDatabaseError(operation, fmt.Errorf("no user found with id %s: %w", id, sql.ErrNoRows))
 	}
 	return nil
 }
 
-func (u *userRepository) Create(user *models.DBUser) (*models.DBUser, error) {
-	u.logger.Debug("creating user", slog.String("email", user.Email))
+func (u *userRepository) Create(ctx context.Context, user *models.DBUser) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "creating user", slog.String("email", user.Email))
 
-	tx, err := u.db.Begin()
+	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			u.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
 	userID := uuid.NewString()
 
-	_, err = tx.Exec(createUserQuery, userID, user.Email, user.PasswordHash, user.FirstName, user.LastName)
+	_, err = tx.ExecContext(ctx, createUserQuery, userID, user.Email, user.PasswordHash, user.FirstName, user.LastName)
 	if err != nil {
 		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
 	}
 
 	var createdUser models.DBUser
-	err = tx.QueryRow(getUserAfterCreateQuery, userID).Scan(&createdUser.ID, &createdUser.CreatedAt)
+	err = tx.QueryRowContext(ctx, getUserAfterCreateQuery, userID).Scan(&createdUser.ID, &createdUser.CreatedAt)
 	if err != nil {
 		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
 	}
@@ -92,50 +96,50 @@ func (u *userRepository) Create(user *models.DBUser) (*models.DBUser, error) {
 		return nil, u.errorHandler.HandleDatabaseError("CreateUser", err)
 	}
 
-	u.logger.Info("created user", slog.String("user_id", createdUser.ID))
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "created user", slog.String("user_id", createdUser.ID))
 	return &createdUser, nil
 }
 
-func (u *userRepository) GetById(id string) (*models.DBUser, error) {
-	u.logger.Debug("get user by id", slog.String("user_id", id))
+func (u *userRepository) GetById(ctx context.Context, id string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by id", slog.String("user_id", id))
 
-	row := u.db.QueryRow(getUserByIDQuery, id)
+	row := u.db.QueryRowContext(ctx, getUserByIDQuery, id)
 	user, err := u.scanDBUser(row)
 	if err != nil {
 		return nil, u.errorHandler.HandleDatabaseError("GetUserByID", err)
 	}
 
-	u.logger.Info("got user", slog.String("user_id", id))
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user", slog.String("user_id", id))
 	return user, nil
 }
 
-func (u *userRepository) GetByEmail(email string) (*models.DBUser, error) {
-	u.logger.Debug("get user by email", slog.String("email", email))
+func (u *userRepository) GetByEmail(ctx context.Context, email string) (*models.DBUser, error) {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "get user by email", slog.String("email", email))
 
-	row := u.db.QueryRow(getUserByEmail, email)
+	row := u.db.QueryRowContext(ctx, getUserByEmail, email)
 	user, err := u.scanDBUser(row)
 	if err != nil {
 		return nil, u.errorHandler.HandleDatabaseError("GetUserByEmail", err)
 	}
 
-	u.logger.Info("got user by email", slog.String("email", email))
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "got user by email", slog.String("user_id", user.ID))
 	return user, nil
 }
 
-func (u *userRepository) Delete(id string) error {
-	u.logger.Debug("delete user", slog.String("user_id", id))
+func (u *userRepository) Delete(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "delete user", slog.String("user_id", id))
 
-	tx, err := u.db.Begin()
+	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
 		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			u.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
-	result, err := tx.Exec(deleteUserQuery, id)
+	result, err := tx.ExecContext(ctx, deleteUserQuery, id)
 	if err != nil {
 		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
 	}
@@ -149,24 +153,24 @@ func (u *userRepository) Delete(id string) error {
 		return u.errorHandler.HandleDatabaseError("DeleteUser", err)
 	}
 
-	u.logger.Info("deleted user", slog.String("user_id", id))
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "deleted user", slog.String("user_id", id))
 	return nil
 }
 
-func (u *userRepository) Update(user *models.DBUser) error {
-	u.logger.Debug("update user", slog.String("user_id", user.ID))
+func (u *userRepository) Update(ctx context.Context, user *models.DBUser) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "update user", slog.String("user_id", user.ID))
 
-	tx, err := u.db.Begin()
+	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
 		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			u.logger.Warn("failed to rollback transaction", slog.String("error", rollbackErr.Error()))
+			logger.FromContext(ctx, u.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", rollbackErr.Error()))
 		}
 	}()
 
-	result, err := tx.Exec(
+	result, err := tx.ExecContext(ctx,
 		updateUserQuery,
 		user.Email,
 		user.FirstName,
@@ -187,6 +191,17 @@ func (u *userRepository) Update(user *models.DBUser) error {
 		return u.errorHandler.HandleDatabaseError("UpdateUser", err)
 	}
 
-	u.logger.Info("updated user", slog.String("user_id", user.ID))
+	logger.FromContext(ctx, u.logger).InfoContext(ctx, "updated user", slog.String("user_id", user.ID))
+	return nil
+}
+
+func (u *userRepository) Lock(ctx context.Context, id string) error {
+	logger.FromContext(ctx, u.logger).DebugContext(ctx, "locking user", slog.String("user_id", id))
+
+	var lockedID string
+	if err := u.db.QueryRowContext(ctx, lockUserQuery, id).Scan(&lockedID); err != nil {
+		return u.errorHandler.HandleDatabaseError("LockUser", err)
+	}
+
 	return nil
 }
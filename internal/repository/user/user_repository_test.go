@@ -39,7 +39,7 @@ func (suite *UserRepoTestSuite) SetupSuite() {
 	suite.Require().NoError(err, "Failed to connect to test database")
 	db := database.GetDb()
 	dbErrorHandler := errors.NewDatabaseErrorHandler()
-	userRepository := user.NewUserRepository(db, dbErrorHandler, logger)
+	userRepository := user.NewUserRepository(database.NewConn(db), dbErrorHandler, logger)
 	suite.repository = userRepository
 }
 
@@ -60,7 +60,7 @@ func (suite *UserRepoTestSuite) TestUserLifecycle() {
 			LastName:     "Caesar",
 		}
 
-		created_user, err := suite.repository.Create(user)
+		created_user, err := suite.repository.Create(suite.ctx, user)
 		assert.NoError(t, err)
 		assert.NotNil(t, created_user)
 
@@ -70,7 +70,7 @@ func (suite *UserRepoTestSuite) TestUserLifecycle() {
 	})
 
 	t.Run("GetUserByEmail", func(t *testing.T) {
-		user, err := suite.repository.GetByEmail("john@email.com")
+		user, err := suite.repository.GetByEmail(suite.ctx, "john@email.com")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
@@ -80,14 +80,14 @@ func (suite *UserRepoTestSuite) TestUserLifecycle() {
 	})
 
 	t.Run("UpdateUser", func(t *testing.T) {
-		user, err := suite.repository.GetByEmail("john@email.com")
+		user, err := suite.repository.GetByEmail(suite.ctx, "john@email.com")
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 
-		err = suite.repository.Update(&models.DBUser{Email: "johnathan@email.com", FirstName: "Johnathan", LastName: "Doe", ID: user.ID})
+		err = suite.repository.Update(suite.ctx, &models.DBUser{Email: "johnathan@email.com", FirstName: "Johnathan", LastName: "Doe", ID: user.ID})
 		assert.NoError(t, err)
 
-		user, err = suite.repository.GetById(user.ID)
+		user, err = suite.repository.GetById(suite.ctx, user.ID)
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, "Johnathan", user.FirstName)
@@ -96,10 +96,10 @@ func (suite *UserRepoTestSuite) TestUserLifecycle() {
 	})
 
 	t.Run("DeleteUser", func(t *testing.T) {
-		err := suite.repository.Delete("1244ABC")
+		err := suite.repository.Delete(suite.ctx, "1244ABC")
 		assert.NoError(t, err)
 
-		_, err = suite.repository.GetById("1244ABC")
+		_, err = suite.repository.GetById(suite.ctx, "1244ABC")
 		expectedErrorMessage := "Resource not found, sql: no rows in result set"
 		assert.Contains(t, err.Error(), expectedErrorMessage)
 	})
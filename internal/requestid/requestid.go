@@ -0,0 +1,41 @@
+// Package requestid carries the ID of the request being served through its
+// context. The ID is the caller's X-Request-ID when it sent a usable one,
+// so a request can be followed across services, or a fresh one otherwise.
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// Header is the header the ID is read from and echoed back in.
+const Header = "X-Request-ID"
+
+// valid matches the IDs accepted from callers. Anything else, which would
+// end up unescaped in logs, is replaced by a fresh ID.
+var valid = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type contextKey struct{}
+
+// FromHeader returns the ID sent by the caller if it is usable, or a new
+// one.
+func FromHeader(value string) string {
+	if valid.MatchString(value) {
+		return value
+	}
+
+	return uuid.NewString()
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the ID of the request ctx belongs to, or "" outside
+// a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -1,55 +1,215 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/kjj1998/task-management-system/internal/auth"
 	"github.com/kjj1998/task-management-system/internal/config"
 	"github.com/kjj1998/task-management-system/internal/database"
 	"github.com/kjj1998/task-management-system/internal/errors"
 	"github.com/kjj1998/task-management-system/internal/handlers"
+	"github.com/kjj1998/task-management-system/internal/health"
+	"github.com/kjj1998/task-management-system/internal/metrics"
 	"github.com/kjj1998/task-management-system/internal/middleware"
+	"github.com/kjj1998/task-management-system/internal/migrate"
 	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/requestid"
 	"github.com/kjj1998/task-management-system/internal/services"
 	"github.com/kjj1998/task-management-system/internal/store"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type TaskManagementSystemStore struct{}
 
 type TaskManagementSystemServer struct {
 	http.Handler
+
+	logger  *slog.Logger
+	health  *health.Checker
+	metrics *metrics.Metrics
+	// logLevel, cors and rateLimiter hold the settings Reload changes.
+	logLevel    *slog.LevelVar
+	cors        *middleware.CORS
+	rateLimiter *middleware.RateLimiter
+	// closers release what the server holds, in the order they are listed:
+	// background work first, the database connection last.
+	closers []func() error
 }
 
-func NewTaskManagementSystemServer(cfg *config.Config, logger *slog.Logger) *TaskManagementSystemServer {
-	err := database.Connect(cfg.Database.User, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name, logger)
-	if err != nil {
-		logger.Error("server startup failed due to database connection",
-			slog.String("error", err.Error()),
-			slog.String("component", "server"),
-		)
+// NewTaskManagementSystemServer wires the API together. It fails when the
+// database cannot be reached or migrated within the configured attempts,
+// rather than starting a server that cannot serve. logLevel is the level
+// logger logs at, which the admin endpoint changes.
+func NewTaskManagementSystemServer(ctx context.Context, cfg *config.Config, logger *slog.Logger, logLevel *slog.LevelVar) (*TaskManagementSystemServer, error) {
+	corsConfig := middleware.DefaultCORSConfig()
+	corsConfig.AllowedOrigins = cfg.CORS.AllowedOrigins
+	t := &TaskManagementSystemServer{
+		logger:      logger,
+		health:      health.NewChecker(cfg.Server.ReadinessTimeout),
+		metrics:     metrics.New(),
+		logLevel:    logLevel,
+		cors:        middleware.NewCORS(corsConfig),
+		rateLimiter: middleware.NewRateLimiter(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst, logger),
 	}
-	db := database.GetDb()
-	dbErrorHandler := errors.NewDatabaseErrorHandler()
 
-	store := store.NewDatabaseTaskStore(db, dbErrorHandler, logger)
-	taskService := services.NewTaskService(store)
+	store, err := t.newTaskStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	store.Instrument(t.metrics)
+	workflowConfig := services.DefaultWorkflowConfig()
+	if cfg.Workflow.AllowReopenWithoutAction {
+		workflowConfig = workflowConfig.AllowWithoutAction(services.ActionReopen)
+	}
+	taskService := services.NewTaskService(store, services.NewTaskWorkflow(workflowConfig))
 	taskHandler := handlers.NewTasksHandler(taskService, logger)
-
-	t := new(TaskManagementSystemServer)
+	categoryService := services.NewCategoryService(store)
+	categoryHandler := handlers.NewCategoriesHandler(categoryService, taskService, logger)
+	tokenManager := auth.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, cfg.Auth.AccessTokenTTL)
+	authService := services.NewAuthService(store, tokenManager, cfg.Auth.RefreshTokenTTL, logger)
+	authHandler := handlers.NewAuthHandler(authService, logger)
+	apiKeyService := services.NewAPIKeyService(store, logger)
+	apiKeyHandler := handlers.NewAPIKeysHandler(apiKeyService, logger)
+	requireAuth := middleware.AuthMiddleware(tokenManager, apiKeyService, logger)
 
 	router := http.NewServeMux()
-	router.Handle("/tasks/", http.HandlerFunc(taskHandler.HandleSingleTask))
-	router.Handle("/tasks", http.HandlerFunc(taskHandler.HandleTasks))
+	router.Handle("/auth/register", http.HandlerFunc(authHandler.Register))
+	router.Handle("/auth/login", http.HandlerFunc(authHandler.Login))
+	router.Handle("/auth/refresh", http.HandlerFunc(authHandler.Refresh))
+	router.Handle("/auth/logout", http.HandlerFunc(authHandler.Logout))
+	router.Handle("/tasks/order", requireAuth(http.HandlerFunc(taskHandler.HandleTaskOrder)))
+	router.Handle("/tasks/", requireAuth(http.HandlerFunc(taskHandler.HandleSingleTask)))
+	router.Handle("/tasks", requireAuth(http.HandlerFunc(taskHandler.HandleTasks)))
+	router.Handle("/categories/", requireAuth(http.HandlerFunc(categoryHandler.HandleSingleCategory)))
+	router.Handle("/categories", requireAuth(http.HandlerFunc(categoryHandler.HandleCategories)))
+	router.Handle("/api-keys/", requireAuth(http.HandlerFunc(apiKeyHandler.HandleSingleAPIKey)))
+	router.Handle("/api-keys", requireAuth(http.HandlerFunc(apiKeyHandler.HandleAPIKeys)))
 	router.Handle("/healthcheck", http.HandlerFunc(t.healthcheckHandler))
-	apiRouter := http.StripPrefix("/api", router)
+	apiRouter := http.StripPrefix("/api", middleware.QueryTimeoutMiddleware(cfg.Database.QueryTimeout)(middleware.RoutePatternMiddleware("/api")(router)))
 
-	corsConfig := middleware.DefaultCORSConfig()
-	handler := middleware.CORSMiddleware(corsConfig)(apiRouter)
-	t.Handler = middleware.LoggingMiddleware(logger)(handler)
+	handler := t.rateLimiter.Middleware(apiRouter)
+	handler = t.cors.Middleware(handler)
+	handler = middleware.LoggingMiddleware(logger, t.metrics)(handler)
+	handler = middleware.RequestIDMiddleware(logger)(handler)
+
+	// Probes and scrapes bypass the access log and tracing, which they would
+	// otherwise drown out.
+	root := http.NewServeMux()
+	root.Handle("/livez", http.HandlerFunc(t.health.LivenessHandler))
+	root.Handle("/readyz", http.HandlerFunc(t.health.ReadinessHandler))
+	root.Handle("/metrics", t.metrics.Handler())
+	root.Handle("/", otelhttp.NewHandler(handler, "http.server"))
+	if cfg.Admin.Token != "" {
+		adminHandler := handlers.NewAdminHandler(logLevel, logger)
+		admin := middleware.AdminMiddleware(cfg.Admin.Token, logger)(http.HandlerFunc(adminHandler.HandleLogLevel))
+		admin = middleware.LoggingMiddleware(logger, nil)(admin)
+		root.Handle("/admin/log-level", middleware.RequestIDMiddleware(logger)(admin))
+	}
+	t.Handler = root
+
+	return t, nil
+}
+
+// Reload applies the settings of cfg that can change while the server runs:
+// the log level, the allowed CORS origins and the rate limits.
+func (t *TaskManagementSystemServer) Reload(cfg *config.Config) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Logging.Level)); err != nil {
+		return fmt.Errorf("invalid log level: %w", err)
+	}
+
+	t.logLevel.Set(level)
+	t.cors.SetAllowedOrigins(cfg.CORS.AllowedOrigins)
+	t.rateLimiter.SetLimits(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+
+	return nil
+}
+
+// BeginShutdown makes the readiness probe fail from now on, while the
+// server carries on serving the requests that still reach it.
+func (t *TaskManagementSystemServer) BeginShutdown() {
+	t.health.Shutdown()
+}
+
+// Close releases everything the server holds. It is called once the HTTP
+// server has stopped handing it requests.
+func (t *TaskManagementSystemServer) Close() error {
+	var errs []error
+	for _, closer := range t.closers {
+		if err := closer(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func (t *TaskManagementSystemServer) newTaskStore(ctx context.Context, cfg *config.Config) (*store.DatabaseTaskStore, error) {
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+
+	if cfg.Storage == config.StorageMemory {
+		t.logger.Warn("using in-memory storage, data will be lost on restart", slog.String("component", "server"))
+		return store.NewMemoryTaskStore(memory.NewDatabase(), dbErrorHandler, t.logger), nil
+	}
+
+	newStore := store.NewDatabaseTaskStore
+	switch cfg.Database.Driver {
+	case config.DriverSQLite:
+		newStore = store.NewSQLiteTaskStore
+	case config.DriverPostgres:
+		newStore = store.NewPostgresTaskStore
+	}
+
+	if err := database.ConnectWithRetry(ctx, cfg.Database, t.logger); err != nil {
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	db := database.GetDb()
+	t.closers = append(t.closers, func() error { return database.Close(t.logger) })
+
+	migrator, err := migrate.New(db, cfg.Database.Driver, t.logger)
+	if err == nil && cfg.Database.MigrateOnStartup {
+		err = migrator.Up(ctx)
+	}
+	if err != nil {
+		t.Close()
+		return nil, fmt.Errorf("failed to migrate the database: %w", err)
+	}
+
+	if err := t.metrics.RegisterDB(db, cfg.Database.Name); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
+	t.health.Add("database", db.PingContext)
+	t.health.Add("migrations", func(ctx context.Context) error {
+		return checkSchemaVersion(ctx, migrator)
+	})
+
+	return newStore(db, dbErrorHandler, t.logger), nil
+}
+
+// checkSchemaVersion fails while the database lacks migrations this binary
+// expects. A newer schema passes: migrations are written to keep working
+// with the previous release, which is still serving during a rolling deploy.
+func checkSchemaVersion(ctx context.Context, migrator *migrate.Migrator) error {
+	version, dirty, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("schema is dirty at version %d", version)
+	}
+	if version < migrator.Latest() {
+		return fmt.Errorf("schema is at version %d, expected %d", version, migrator.Latest())
+	}
 
-	return t
+	return nil
 }
 
 func (t *TaskManagementSystemServer) healthcheckHandler(w http.ResponseWriter, r *http.Request) {
@@ -57,6 +217,7 @@ func (t *TaskManagementSystemServer) healthcheckHandler(w http.ResponseWriter, r
 
 	w.Header().Set("Content-Type", "application/json")
 	response := models.NewSuccessResponse("Service is healthy", health)
+	response.RequestID = requestid.FromContext(r.Context())
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
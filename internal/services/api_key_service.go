@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/store"
+)
+
+const (
+	maxAPIKeyNameLength = 100
+	// lastUsedResolution limits how often an API key's last used timestamp
+	// is written, so a busy key does not cost a write per request.
+	lastUsedResolution = time.Minute
+)
+
+type CreateAPIKeyRequest struct {
+	Name  string             `json:"name"`
+	Scope models.APIKeyScope `json:"scope"`
+}
+
+// CreatedAPIKey is returned once when a key is minted. The plaintext Key is
+// never stored and cannot be retrieved again.
+type CreatedAPIKey struct {
+	models.DBAPIKey
+	Key string `json:"key"`
+}
+
+type APIKeyService struct {
+	taskStore *store.DatabaseTaskStore
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewAPIKeyService(taskStore *store.DatabaseTaskStore, logger *slog.Logger) *APIKeyService {
+	return &APIKeyService{
+		taskStore: taskStore,
+		logger:    logger,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// CreateAPIKey mints a key for the caller. Keys can only be managed from a
+// login session, so a leaked key cannot be used to mint more keys.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, request CreateAPIKeyRequest) (_ *CreatedAPIKey, err error) {
+	ctx, end := startSpan(ctx, "APIKeyService.CreateAPIKey")
+	defer end(&err)
+
+	userID, err := requireSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return nil, errors.NewValidationError("API key name is required", models.FieldError{Field: "name", Message: "must not be empty"})
+	}
+	if utf8.RuneCountInString(request.Name) > maxAPIKeyNameLength {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("API key name must be at most %d characters", maxAPIKeyNameLength),
+			models.FieldError{Field: "name", Message: fmt.Sprintf("must be at most %d characters", maxAPIKeyNameLength)},
+		)
+	}
+	if request.Scope == "" {
+		request.Scope = models.ScopeRead
+	}
+	if !request.Scope.IsValid() {
+		message := fmt.Sprintf("must be one of %s, %s", models.ScopeRead, models.ScopeReadWrite)
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("Invalid scope %q, %s", request.Scope, message),
+			models.FieldError{Field: "scope", Message: message},
+		)
+	}
+
+	key, prefix, keyHash, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate API key", err)
+	}
+
+	apiKey := models.DBAPIKey{
+		UserID:  userID,
+		Name:    request.Name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scope:   request.Scope,
+	}
+
+	createdKey, err := s.taskStore.APIKeyRepository.Create(ctx, &apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.ID = createdKey.ID
+	apiKey.CreatedAt = createdKey.CreatedAt
+	return &CreatedAPIKey{DBAPIKey: apiKey, Key: key}, nil
+}
+
+// ListAPIKeys lists the caller's keys, including revoked ones.
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) (_ []models.DBAPIKey, err error) {
+	ctx, end := startSpan(ctx, "APIKeyService.ListAPIKeys")
+	defer end(&err)
+
+	userID, err := requireSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.taskStore.APIKeyRepository.GetAllForUser(ctx, userID)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, key_id string) (err error) {
+	ctx, end := startSpan(ctx, "APIKeyService.RevokeAPIKey")
+	defer end(&err)
+
+	if _, err := requireSession(ctx); err != nil {
+		return err
+	}
+
+	apiKey, err := s.taskStore.APIKeyRepository.GetById(ctx, key_id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeOwner(ctx, apiKey.UserID, "api key "+key_id, errors.CodeAPIKeyNotFound); err != nil {
+		return err
+	}
+
+	return s.taskStore.APIKeyRepository.Revoke(ctx, key_id)
+}
+
+// AuthenticateAPIKey resolves a bearer API key to the identity of its owner.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (_ *auth.Identity, err error) {
+	ctx, end := startSpan(ctx, "APIKeyService.AuthenticateAPIKey")
+	defer end(&err)
+
+	apiKey, err := s.taskStore.APIKeyRepository.GetByHash(ctx, auth.HashToken(key))
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			return nil, errors.NewUnauthorizedError("Invalid API key", err).WithCode(errors.CodeInvalidAPIKey)
+		}
+		return nil, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, errors.NewUnauthorizedError("Invalid API key", fmt.Errorf("api key %s was revoked", apiKey.ID)).WithCode(errors.CodeInvalidAPIKey)
+	}
+
+	now := s.now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := s.taskStore.APIKeyRepository.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			logger.FromContext(ctx, s.logger).WarnContext(ctx, "failed to record api key usage",
+				slog.String("api_key_id", apiKey.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return &auth.Identity{
+		UserID:   apiKey.UserID,
+		Method:   auth.MethodAPIKey,
+		APIKeyID: apiKey.ID,
+		ReadOnly: apiKey.Scope != models.ScopeReadWrite,
+	}, nil
+}
+
+// requireSession returns the caller's user ID, refusing callers that
+// authenticated with an API key.
+func requireSession(ctx context.Context) (string, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return "", errors.NewUnauthorizedError("Authentication required", fmt.Errorf("no identity in request context"))
+	}
+	if identity.Method == auth.MethodAPIKey {
+		return "", errors.NewForbiddenError("API keys cannot be managed with an API key", fmt.Errorf("api key %s used to manage api keys", identity.APIKeyID))
+	}
+
+	return identity.UserID, nil
+}
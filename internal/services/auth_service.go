@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/store"
+)
+
+// dummyPasswordHash is compared against when a login names an unknown email,
+// so both cases take the same time.
+const dummyPasswordHash = "$2a$10$33yI6Pp/jc3JEewfEXxWnePKrX52jY6WQd16MFhIo/rqvAZ35VO2S"
+
+type RegisterRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type UserProfile struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AuthService struct {
+	taskStore       *store.DatabaseTaskStore
+	tokens          *auth.TokenManager
+	refreshTokenTTL time.Duration
+	logger          *slog.Logger
+	now             func() time.Time
+}
+
+func NewAuthService(taskStore *store.DatabaseTaskStore, tokens *auth.TokenManager, refreshTokenTTL time.Duration, logger *slog.Logger) *AuthService {
+	return &AuthService{
+		taskStore:       taskStore,
+		tokens:          tokens,
+		refreshTokenTTL: refreshTokenTTL,
+		logger:          logger,
+		now:             func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *AuthService) Register(ctx context.Context, request RegisterRequest) (_ *UserProfile, err error) {
+	ctx, end := startSpan(ctx, "AuthService.Register")
+	defer end(&err)
+
+	if err := validateRegistration(&request); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := auth.HashPassword(request.Password)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to hash password", err)
+	}
+
+	user := models.DBUser{
+		Email:        request.Email,
+		PasswordHash: passwordHash,
+		FirstName:    request.FirstName,
+		LastName:     request.LastName,
+	}
+
+	createdUser, err := s.taskStore.UserRepository.Create(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserProfile{
+		ID:        createdUser.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: createdUser.CreatedAt,
+	}, nil
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (_ *TokenPair, err error) {
+	ctx, end := startSpan(ctx, "AuthService.Login")
+	defer end(&err)
+
+	invalidCredentials := errors.NewUnauthorizedError("Invalid email or password", fmt.Errorf("login failed")).WithCode(errors.CodeInvalidCredentials)
+
+	user, err := s.taskStore.UserRepository.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			_ = auth.CheckPassword(dummyPasswordHash, password)
+			return nil, invalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := auth.CheckPassword(user.PasswordHash, password); err != nil {
+		return nil, invalidCredentials
+	}
+
+	pair, _, err := s.issueTokenPair(ctx, s.taskStore, user.ID, user.Email, uuid.NewString())
+	return pair, err
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// can be used once; presenting a token that was already rotated revokes the
+// whole family, since either the client or an attacker holds a stolen copy.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (_ *TokenPair, err error) {
+	ctx, end := startSpan(ctx, "AuthService.Refresh")
+	defer end(&err)
+
+	invalidToken := errors.NewUnauthorizedError("Invalid or expired refresh token", fmt.Errorf("refresh failed")).WithCode(errors.CodeInvalidRefreshToken)
+
+	storedToken, err := s.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if storedToken == nil || !storedToken.ExpiresAt.After(s.now()) {
+		return nil, invalidToken
+	}
+	if storedToken.RevokedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, storedToken, invalidToken)
+	}
+
+	user, err := s.taskStore.UserRepository.GetById(ctx, storedToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new token only survives if the old one is revoked, so a lost race
+	// against a concurrent rotation leaves no orphaned token behind.
+	var pair *TokenPair
+	err = s.taskStore.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		var newTokenID string
+		var err error
+		pair, newTokenID, err = s.issueTokenPair(ctx, txStore, user.ID, user.Email, storedToken.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		return txStore.TokenRepository.Revoke(ctx, storedToken.ID, newTokenID)
+	})
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			return nil, s.revokeReusedFamily(ctx, storedToken, invalidToken)
+		}
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Logout revokes the refresh token family the token belongs to.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) (err error) {
+	ctx, end := startSpan(ctx, "AuthService.Logout")
+	defer end(&err)
+
+	storedToken, err := s.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+	if storedToken == nil {
+		return nil
+	}
+
+	return s.taskStore.TokenRepository.RevokeFamily(ctx, storedToken.FamilyID)
+}
+
+func (s *AuthService) findRefreshToken(ctx context.Context, refreshToken string) (*models.DBRefreshToken, error) {
+	if refreshToken == "" {
+		return nil, errors.NewValidationError("Refresh token is required", models.FieldError{Field: "refreshToken", Message: "is required"})
+	}
+
+	storedToken, err := s.taskStore.TokenRepository.GetByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Type == errors.ErrorTypeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return storedToken, nil
+}
+
+func (s *AuthService) revokeReusedFamily(ctx context.Context, storedToken *models.DBRefreshToken, invalidToken error) error {
+	logger.FromContext(ctx, s.logger).WarnContext(ctx, "refresh token reuse detected, revoking token family",
+		slog.String("user_id", storedToken.UserID),
+		slog.String("family_id", storedToken.FamilyID),
+	)
+	if err := s.taskStore.TokenRepository.RevokeFamily(ctx, storedToken.FamilyID); err != nil {
+		return err
+	}
+	return invalidToken
+}
+
+func (s *AuthService) issueTokenPair(ctx context.Context, taskStore *store.DatabaseTaskStore, userID, email, familyID string) (*TokenPair, string, error) {
+	accessToken, expiresAt, err := s.tokens.IssueAccessToken(userID, email)
+	if err != nil {
+		return nil, "", errors.NewInternalError("Failed to issue access token", err)
+	}
+
+	refreshToken, refreshTokenHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, "", errors.NewInternalError("Failed to issue refresh token", err)
+	}
+
+	createdToken, err := taskStore.TokenRepository.Create(ctx, &models.DBRefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: refreshTokenHash,
+		ExpiresAt: s.now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(expiresAt.Sub(s.now()).Round(time.Second).Seconds()),
+	}, createdToken.ID, nil
+}
+
+func validateRegistration(request *RegisterRequest) error {
+	request.Email = strings.ToLower(strings.TrimSpace(request.Email))
+	request.FirstName = strings.TrimSpace(request.FirstName)
+	request.LastName = strings.TrimSpace(request.LastName)
+
+	if address, err := mail.ParseAddress(request.Email); err != nil || address.Address != request.Email {
+		return errors.NewValidationError("A valid email address is required", models.FieldError{Field: "email", Message: "must be a valid email address"})
+	}
+	if len(request.Password) < auth.MinPasswordLength {
+		return errors.NewValidationError(
+			fmt.Sprintf("Password must be at least %d characters", auth.MinPasswordLength),
+			models.FieldError{Field: "password", Message: fmt.Sprintf("must be at least %d characters", auth.MinPasswordLength)},
+		)
+	}
+	if len(request.Password) > auth.MaxPasswordLength {
+		return errors.NewValidationError(
+			fmt.Sprintf("Password must be at most %d bytes", auth.MaxPasswordLength),
+			models.FieldError{Field: "password", Message: fmt.Sprintf("must be at most %d bytes", auth.MaxPasswordLength)},
+		)
+	}
+	var missing []models.FieldError
+	if request.FirstName == "" {
+		missing = append(missing, models.FieldError{Field: "firstName", Message: "must not be empty"})
+	}
+	if request.LastName == "" {
+		missing = append(missing, models.FieldError{Field: "lastName", Message: "must not be empty"})
+	}
+	if len(missing) > 0 {
+		return errors.NewValidationError("First and last name are required", missing...)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/errors"
+)
+
+// callerID returns the authenticated user stored in ctx by the auth
+// middleware.
+func callerID(ctx context.Context) (string, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return "", errors.NewUnauthorizedError("Authentication required", fmt.Errorf("no identity in request context"))
+	}
+
+	return identity.UserID, nil
+}
+
+// authorizeOwner checks that the caller owns a resource. Resources owned by
+// someone else are reported as not found, exactly like IDs that do not exist,
+// so callers cannot probe for other users' IDs. notFoundCode is the code a
+// missing resource of the same kind gets.
+func authorizeOwner(ctx context.Context, ownerID string, resource string, notFoundCode string) error {
+	userID, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ownerID != userID {
+		return errors.NewNotFoundError("Resource not found", fmt.Errorf("%s is not owned by user %s", resource, userID)).WithCode(notFoundCode)
+	}
+
+	return nil
+}
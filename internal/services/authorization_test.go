@@ -0,0 +1,193 @@
+package services_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/auth"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/kjj1998/task-management-system/internal/services"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	ownerID         = "1244ABC"
+	ownedTaskID     = "DSFDS23423"
+	ownedCategoryID = "2345SDSXAS"
+)
+
+type AuthorizationTestSuite struct {
+	suite.Suite
+	mySQLContainer  *testutils.MySQLContainer
+	ctx             context.Context
+	store           *store.DatabaseTaskStore
+	taskService     *services.TaskService
+	categoryService *services.CategoryService
+	ownerCtx        context.Context
+	intruderCtx     context.Context
+}
+
+func (suite *AuthorizationTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	mySQLContainer, err := testutils.CreateMySQLContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.mySQLContainer = mySQLContainer
+	host, _ := mySQLContainer.Container.Host(suite.ctx)
+	port, _ := mySQLContainer.Container.MappedPort(suite.ctx, "3306")
+
+	err = database.Connect("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	db := database.GetDb()
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+
+	suite.store = store.NewDatabaseTaskStore(db, dbErrorHandler, logger)
+	suite.taskService = services.NewTaskService(suite.store, services.NewTaskWorkflow(services.DefaultWorkflowConfig()))
+	suite.categoryService = services.NewCategoryService(suite.store)
+
+	intruder, err := suite.store.UserRepository.Create(suite.ctx, &models.DBUser{
+		Email:        "mallory@email.com",
+		PasswordHash: "not-a-real-hash",
+		FirstName:    "Mallory",
+		LastName:     "Intruder",
+	})
+	suite.Require().NoError(err, "Failed to create second user")
+
+	suite.ownerCtx = auth.WithIdentity(suite.ctx, &auth.Identity{UserID: ownerID})
+	suite.intruderCtx = auth.WithIdentity(suite.ctx, &auth.Identity{UserID: intruder.ID})
+}
+
+func (suite *AuthorizationTestSuite) TearDownSuite() {
+	if err := suite.mySQLContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating mysql container: %s", err)
+	}
+}
+
+func assertStatus(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, statusCode, appErr.StatusCode)
+	}
+}
+
+func (suite *AuthorizationTestSuite) TestTaskOwnership() {
+	t := suite.T()
+
+	t.Run("OwnerCanReadTask", func(t *testing.T) {
+		task, err := suite.taskService.GetTask(suite.ownerCtx, ownedTaskID)
+		assert.NoError(t, err)
+		assert.Equal(t, ownerID, task.UserID)
+	})
+
+	t.Run("ForeignTaskIsNotFound", func(t *testing.T) {
+		_, err := suite.taskService.GetTask(suite.intruderCtx, ownedTaskID)
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = suite.taskService.UpdateTask(suite.intruderCtx, ownedTaskID, services.TaskRequest{
+			Title:    "Hijacked",
+			Priority: models.High,
+			Status:   models.Pending,
+		})
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = suite.taskService.PatchTask(suite.intruderCtx, ownedTaskID, []byte(`{"title":"Hijacked"}`))
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = suite.taskService.TransitionTask(suite.intruderCtx, ownedTaskID, services.ActionComplete)
+		assertStatus(t, err, http.StatusNotFound)
+
+		err = suite.taskService.DeleteTask(suite.intruderCtx, ownedTaskID, services.DeleteSubtasks)
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = suite.taskService.GetTaskDependencies(suite.intruderCtx, ownedTaskID)
+		assertStatus(t, err, http.StatusNotFound)
+
+		err = suite.taskService.RemoveDependency(suite.intruderCtx, ownedTaskID, ownedTaskID)
+		assertStatus(t, err, http.StatusNotFound)
+
+		task, err := suite.store.TaskRepository.GetById(suite.ctx, ownedTaskID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Sweep Floor", task.Title)
+	})
+
+	t.Run("ListingIsScopedToCaller", func(t *testing.T) {
+		page, err := suite.taskService.ListTasks(suite.intruderCtx, models.TaskQuery{
+			Filter: models.TaskFilter{UserID: ownerID},
+			Sort:   []models.TaskSort{{Field: models.SortByCreatedAt}},
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, page.Tasks)
+	})
+
+	t.Run("CreateIsOwnedByCaller", func(t *testing.T) {
+		created, err := suite.taskService.CreateTask(suite.intruderCtx, services.TaskRequest{
+			Title:    "Planted task",
+			Priority: models.Low,
+		})
+		assert.NoError(t, err)
+
+		task, err := suite.store.TaskRepository.GetById(suite.ctx, created.ID)
+		assert.NoError(t, err)
+		assert.NotEqual(t, ownerID, task.UserID)
+	})
+
+	t.Run("CreateInForeignCategoryIsNotFound", func(t *testing.T) {
+		_, err := suite.taskService.CreateTask(suite.intruderCtx, services.TaskRequest{
+			CategoryID: ownedCategoryID,
+			Title:      "Planted task",
+			Priority:   models.Low,
+		})
+		assertStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("MissingIdentityIsUnauthorized", func(t *testing.T) {
+		_, err := suite.taskService.GetTask(suite.ctx, ownedTaskID)
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+}
+
+func (suite *AuthorizationTestSuite) TestCategoryOwnership() {
+	t := suite.T()
+
+	t.Run("OwnerCanReadCategory", func(t *testing.T) {
+		category, err := suite.categoryService.GetCategory(suite.ownerCtx, ownedCategoryID)
+		assert.NoError(t, err)
+		assert.Equal(t, "routine", category.Name)
+	})
+
+	t.Run("ForeignCategoryIsNotFound", func(t *testing.T) {
+		_, err := suite.categoryService.GetCategory(suite.intruderCtx, ownedCategoryID)
+		assertStatus(t, err, http.StatusNotFound)
+
+		_, err = suite.categoryService.UpdateCategory(suite.intruderCtx, ownedCategoryID, services.CategoryRequest{Name: "Hijacked", Color: "#000000"})
+		assertStatus(t, err, http.StatusNotFound)
+
+		err = suite.categoryService.DeleteCategory(suite.intruderCtx, ownedCategoryID)
+		assertStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("ListingIsScopedToCaller", func(t *testing.T) {
+		categories, err := suite.categoryService.ListCategories(suite.intruderCtx)
+		assert.NoError(t, err)
+		assert.Empty(t, categories)
+	})
+}
+
+func TestAuthorizationTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationTestSuite))
+}
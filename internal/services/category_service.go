@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/kjj1998/task-management-system/internal/validation"
+)
+
+const (
+	defaultCategoryColor  = "#007bff"
+	maxCategoryNameLength = 100
+)
+
+// CategoryRequest holds the fields of a category that clients set.
+type CategoryRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// category returns the category described by the request. Categories
+// without a colour get the default one.
+func (r CategoryRequest) category() models.DBCategory {
+	color := r.Color
+	if color == "" {
+		color = defaultCategoryColor
+	}
+
+	return models.DBCategory{Name: r.Name, Color: color}
+}
+
+type CategoryService struct {
+	taskStore *store.DatabaseTaskStore
+}
+
+func NewCategoryService(taskStore *store.DatabaseTaskStore) *CategoryService {
+	return &CategoryService{
+		taskStore: taskStore,
+	}
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, category_id string) (_ *models.DBCategory, err error) {
+	ctx, end := startSpan(ctx, "CategoryService.GetCategory")
+	defer end(&err)
+
+	category, err := s.taskStore.CategoryRepository.GetById(ctx, category_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOwner(ctx, category.UserID, "category "+category_id, errors.CodeCategoryNotFound); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// ListCategories lists the caller's categories.
+func (s *CategoryService) ListCategories(ctx context.Context) (_ []models.DBCategory, err error) {
+	ctx, end := startSpan(ctx, "CategoryService.ListCategories")
+	defer end(&err)
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.taskStore.CategoryRepository.GetAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, request CategoryRequest) (_ *models.DBCategory, err error) {
+	ctx, end := startSpan(ctx, "CategoryService.CreateCategory")
+	defer end(&err)
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	category := request.category()
+	category.UserID = userID
+
+	if err := validateCategory(&category); err != nil {
+		return nil, err
+	}
+
+	createdCategory, err := s.taskStore.CategoryRepository.Create(ctx, &category)
+	if err != nil {
+		return nil, err
+	}
+
+	category.ID = createdCategory.ID
+	category.CreatedAt = createdCategory.CreatedAt
+	return &category, nil
+}
+
+// UpdateCategory replaces the name and colour of the category.
+func (s *CategoryService) UpdateCategory(ctx context.Context, category_id string, request CategoryRequest) (_ *models.DBCategory, err error) {
+	ctx, end := startSpan(ctx, "CategoryService.UpdateCategory")
+	defer end(&err)
+
+	existingCategory, err := s.GetCategory(ctx, category_id)
+	if err != nil {
+		return nil, err
+	}
+
+	category := request.category()
+	category.ID = existingCategory.ID
+	category.UserID = existingCategory.UserID
+	category.CreatedAt = existingCategory.CreatedAt
+	if err := validateCategory(&category); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskStore.CategoryRepository.Update(ctx, &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// DeleteCategory removes the category. Its tasks are kept and become
+// uncategorised through the ON DELETE SET NULL foreign key.
+func (s *CategoryService) DeleteCategory(ctx context.Context, category_id string) (err error) {
+	ctx, end := startSpan(ctx, "CategoryService.DeleteCategory")
+	defer end(&err)
+
+	if _, err := s.GetCategory(ctx, category_id); err != nil {
+		return err
+	}
+
+	return s.taskStore.CategoryRepository.Delete(ctx, category_id)
+}
+
+func validateCategory(category *models.DBCategory) error {
+	category.Name = strings.TrimSpace(category.Name)
+
+	v := validation.New()
+	validation.Check(v, "name", category.Name, validation.Required[string](), validation.MaxLength[string](maxCategoryNameLength))
+	validation.Check(v, "color", category.Color, validation.HexColor[string]())
+
+	return v.Err()
+}
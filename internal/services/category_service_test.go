@@ -0,0 +1,37 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCategory(t *testing.T) {
+	cases := []struct {
+		name     string
+		category models.DBCategory
+		valid    bool
+	}{
+		{"six digit colour", models.DBCategory{Name: "urgent", Color: "#ff0000"}, true},
+		{"three digit colour", models.DBCategory{Name: "urgent", Color: "#F00"}, true},
+		{"missing hash", models.DBCategory{Name: "urgent", Color: "ff0000"}, false},
+		{"named colour", models.DBCategory{Name: "urgent", Color: "red"}, false},
+		{"non hex digit", models.DBCategory{Name: "urgent", Color: "#ff00zz"}, false},
+		{"too long", models.DBCategory{Name: "urgent", Color: "#ff00000"}, false},
+		{"blank name", models.DBCategory{Name: "   ", Color: "#ff0000"}, false},
+		{"name too long", models.DBCategory{Name: strings.Repeat("a", 101), Color: "#ff0000"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCategory(&tc.category)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// applyMergePatch applies an RFC 7396 JSON Merge Patch document to original
+// and returns the patched document.
+func applyMergePatch(original, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(original, &target); err != nil {
+		return nil, fmt.Errorf("invalid merge patch target: %w", err)
+	}
+
+	var patchDoc any
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, fmt.Errorf("invalid merge patch document: %w", err)
+	}
+
+	return json.Marshal(mergeValue(target, patchDoc))
+}
+
+func mergeValue(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any)
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
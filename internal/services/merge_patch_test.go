@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	// Cases taken from RFC 7396 Appendix A.
+	cases := []struct {
+		name     string
+		original string
+		patch    string
+		expected string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"remove member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"remove one of many", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"array replaces array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"value replaces array", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"nested merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"array of objects", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"non object patch", `{"a":"foo"}`, `"bar"`, `"bar"`},
+		{"null patch", `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{"non object target", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{"nested null cleanup", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			patched, err := applyMergePatch([]byte(tc.original), []byte(tc.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(patched))
+		})
+	}
+
+	t.Run("invalid patch", func(t *testing.T) {
+		_, err := applyMergePatch([]byte(`{}`), []byte(`{"a":`))
+		assert.Error(t, err)
+	})
+}
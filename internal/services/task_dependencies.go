@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/kjj1998/task-management-system/internal/validation"
+)
+
+// DependencyRequest names the task that a task is blocked by.
+type DependencyRequest struct {
+	BlockedBy string `json:"blockedBy"`
+}
+
+// GetTaskDependencies returns the tasks the task is blocked by and the tasks
+// it blocks.
+func (s *TaskService) GetTaskDependencies(ctx context.Context, task_id string) (_ *models.TaskDependencies, err error) {
+	ctx, end := startSpan(ctx, "TaskService.GetTaskDependencies")
+	defer end(&err)
+
+	if _, err := s.getTask(ctx, task_id); err != nil {
+		return nil, err
+	}
+
+	edges, err := s.taskStore.DependencyRepository.GetForTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	blockerIDs := make(map[string]bool)
+	relatedIDs := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		if edge.TaskID == task_id {
+			blockerIDs[edge.BlockedByID] = true
+			relatedIDs = append(relatedIDs, edge.BlockedByID)
+		} else {
+			relatedIDs = append(relatedIDs, edge.TaskID)
+		}
+	}
+	related, err := s.taskStore.TaskRepository.GetByIds(ctx, relatedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := &models.TaskDependencies{
+		BlockedBy: make([]models.DBTask, 0),
+		Blocks:    make([]models.DBTask, 0),
+	}
+	for _, task := range related {
+		if blockerIDs[task.ID] {
+			dependencies.BlockedBy = append(dependencies.BlockedBy, task)
+		} else {
+			dependencies.Blocks = append(dependencies.Blocks, task)
+		}
+	}
+
+	return dependencies, nil
+}
+
+// AddDependency records that the task cannot start until the task named in
+// request is completed. Both tasks must belong to the caller, and the new
+// edge must not close a cycle in the dependency graph.
+func (s *TaskService) AddDependency(ctx context.Context, task_id string, request DependencyRequest) (_ *models.DBTaskDependency, err error) {
+	ctx, end := startSpan(ctx, "TaskService.AddDependency")
+	defer end(&err)
+
+	v := validation.New()
+	validation.Check(v, "blockedBy", request.BlockedBy, validation.Required[string]())
+	if request.BlockedBy == task_id {
+		v.Add("blockedBy", "must not be the task itself")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	existingTask, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getTask(ctx, request.BlockedBy); err != nil {
+		return nil, err
+	}
+
+	var createdDependency *models.DBTaskDependency
+	err = s.taskStore.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		// Locking the owner serialises dependency changes, so two edges
+		// that together close a cycle cannot each pass the check.
+		if err := txStore.UserRepository.Lock(ctx, existingTask.UserID); err != nil {
+			return err
+		}
+
+		edges, err := txStore.DependencyRepository.GetAllForUser(ctx, existingTask.UserID)
+		if err != nil {
+			return err
+		}
+		if blockedBy(edges, request.BlockedBy, task_id) {
+			return errors.NewConflictError(
+				"The task cannot be blocked by a task that it blocks, directly or indirectly",
+				fmt.Errorf("dependency of %s on %s would create a cycle", task_id, request.BlockedBy),
+			).WithCode(errors.CodeDependencyCycle)
+		}
+
+		createdDependency, err = txStore.DependencyRepository.Create(ctx, &models.DBTaskDependency{TaskID: task_id, BlockedByID: request.BlockedBy})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return createdDependency, nil
+}
+
+// RemoveDependency removes the edge that has the task blocked by the task
+// with ID blocked_by_id.
+func (s *TaskService) RemoveDependency(ctx context.Context, task_id string, blocked_by_id string) (err error) {
+	ctx, end := startSpan(ctx, "TaskService.RemoveDependency")
+	defer end(&err)
+
+	if _, err := s.getTask(ctx, task_id); err != nil {
+		return err
+	}
+
+	return s.taskStore.DependencyRepository.Delete(ctx, task_id, blocked_by_id)
+}
+
+// OrderTasks returns the caller's open tasks in an order in which they can be
+// worked on: every task comes after the open tasks it is blocked by. Among
+// the tasks that are free to go next, higher priority, earlier due dates and
+// older tasks come first.
+func (s *TaskService) OrderTasks(ctx context.Context) (_ []models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.OrderTasks")
+	defer end(&err)
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskStore.TaskRepository.GetAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := s.taskStore.DependencyRepository.GetAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[string]models.DBTask)
+	for _, task := range tasks {
+		if task.Status != models.Completed {
+			open[task.ID] = task
+		}
+	}
+
+	// Kahn's algorithm over the open tasks. Completed blockers no longer
+	// block anything, so only edges between open tasks count.
+	blockerCount := make(map[string]int)
+	blocks := make(map[string][]string)
+	for _, edge := range edges {
+		_, taskOpen := open[edge.TaskID]
+		_, blockerOpen := open[edge.BlockedByID]
+		if taskOpen && blockerOpen {
+			blockerCount[edge.TaskID]++
+			blocks[edge.BlockedByID] = append(blocks[edge.BlockedByID], edge.TaskID)
+		}
+	}
+
+	var ready []models.DBTask
+	for id, task := range open {
+		if blockerCount[id] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	ordered := make([]models.DBTask, 0, len(open))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return workFirst(ready[i], ready[j]) })
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, next)
+
+		for _, id := range blocks[next.ID] {
+			blockerCount[id]--
+			if blockerCount[id] == 0 {
+				ready = append(ready, open[id])
+			}
+		}
+	}
+
+	if len(ordered) != len(open) {
+		return nil, errors.NewInternalError("Failed to order tasks", fmt.Errorf("dependencies of user %s contain a cycle", userID))
+	}
+
+	return ordered, nil
+}
+
+// checkNotBlocked fails with TASK_BLOCKED if any task the task is blocked by
+// is still open. It must run in the unit of work that starts the task, with
+// the owner locked, so that no blocker can be added after the check.
+func checkNotBlocked(ctx context.Context, txStore *store.DatabaseTaskStore, task_id string) error {
+	edges, err := txStore.DependencyRepository.GetForTask(ctx, task_id)
+	if err != nil {
+		return err
+	}
+
+	var blockerIDs []string
+	for _, edge := range edges {
+		if edge.TaskID == task_id {
+			blockerIDs = append(blockerIDs, edge.BlockedByID)
+		}
+	}
+	if len(blockerIDs) == 0 {
+		return nil
+	}
+	blockers, err := txStore.TaskRepository.GetByIds(ctx, blockerIDs)
+	if err != nil {
+		return err
+	}
+
+	var openBlockers []string
+	for _, blocker := range blockers {
+		if blocker.Status != models.Completed {
+			openBlockers = append(openBlockers, blocker.ID)
+		}
+	}
+
+	if len(openBlockers) > 0 {
+		return errors.NewInvalidTransitionError(
+			fmt.Sprintf("Task is blocked by %d open task(s) and cannot be started", len(openBlockers)),
+			fmt.Errorf("task %s is blocked by %v", task_id, openBlockers),
+		).WithCode(errors.CodeTaskBlocked)
+	}
+
+	return nil
+}
+
+// blockedBy reports whether following the edges from the task with ID from
+// to the tasks it is blocked by reaches the task with ID to.
+func blockedBy(edges []models.DBTaskDependency, from string, to string) bool {
+	blockers := make(map[string][]string)
+	for _, edge := range edges {
+		blockers[edge.TaskID] = append(blockers[edge.TaskID], edge.BlockedByID)
+	}
+
+	visited := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, blocker := range blockers[id] {
+			if !visited[blocker] {
+				visited[blocker] = true
+				stack = append(stack, blocker)
+			}
+		}
+	}
+
+	return false
+}
+
+// workFirst orders tasks that are free to be worked on: higher priority
+// first, then earlier due dates with undated tasks last, then older tasks.
+func workFirst(a, b models.DBTask) bool {
+	if rankA, rankB := models.PriorityRank(a.Priority), models.PriorityRank(b.Priority); rankA != rankB {
+		return rankA > rankB
+	}
+
+	dueA, dueB := models.NoDueDate, models.NoDueDate
+	if a.DueDate != nil {
+		dueA = *a.DueDate
+	}
+	if b.DueDate != nil {
+		dueB = *b.DueDate
+	}
+	if !dueA.Equal(dueB) {
+		return dueA.Before(dueB)
+	}
+
+	if a.CreatedAt != nil && b.CreatedAt != nil && !a.CreatedAt.Equal(*b.CreatedAt) {
+		return a.CreatedAt.Before(*b.CreatedAt)
+	}
+
+	return a.ID < b.ID
+}
@@ -1,45 +1,596 @@
 package services
 
 import (
-	"net/http"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	"github.com/kjj1998/task-management-system/internal/errors"
 	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
 	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/kjj1998/task-management-system/internal/validation"
 )
 
+const (
+	maxTaskTitleLength       = 200
+	maxTaskDescriptionLength = 10000
+)
+
+// SubtaskPolicy decides what happens to the subtasks of a deleted task.
+type SubtaskPolicy string
+
+const (
+	// DeleteSubtasks deletes the whole subtree along with the task.
+	DeleteSubtasks SubtaskPolicy = "delete"
+	// PromoteSubtasks moves the direct subtasks up to the parent of the
+	// deleted task, keeping their own subtasks under them.
+	PromoteSubtasks SubtaskPolicy = "promote"
+)
+
+// TaskRequest holds the fields of a task that clients set. The ID, owner and
+// timestamps of a task are owned by the server.
+type TaskRequest struct {
+	CategoryID   string              `json:"categoryID"`
+	ParentID     string              `json:"parentID"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Priority     models.TaskPriority `json:"priority"`
+	Status       models.TaskStatus   `json:"status"`
+	DueDate      *time.Time          `json:"dueDate"`
+	AutoComplete bool                `json:"autoComplete"`
+}
+
+// task returns the task described by the request. Tasks without a priority
+// get medium, like the column default.
+func (r TaskRequest) task() models.DBTask {
+	priority := r.Priority
+	if priority == "" {
+		priority = models.Medium
+	}
+
+	return models.DBTask{
+		CategoryID:   r.CategoryID,
+		ParentID:     r.ParentID,
+		Title:        r.Title,
+		Description:  r.Description,
+		Priority:     priority,
+		Status:       r.Status,
+		DueDate:      r.DueDate,
+		AutoComplete: r.AutoComplete,
+	}
+}
+
+func newTaskRequest(task *models.DBTask) TaskRequest {
+	return TaskRequest{
+		CategoryID:   task.CategoryID,
+		ParentID:     task.ParentID,
+		Title:        task.Title,
+		Description:  task.Description,
+		Priority:     task.Priority,
+		Status:       task.Status,
+		DueDate:      task.DueDate,
+		AutoComplete: task.AutoComplete,
+	}
+}
+
 type TaskService struct {
 	taskStore *store.DatabaseTaskStore
+	workflow  *TaskWorkflow
 }
 
-func NewTaskService(taskStore *store.DatabaseTaskStore) *TaskService {
+func NewTaskService(taskStore *store.DatabaseTaskStore, workflow *TaskWorkflow) *TaskService {
 	return &TaskService{
 		taskStore: taskStore,
+		workflow:  workflow,
 	}
 }
 
-func (s *TaskService) GetTask(w http.ResponseWriter, task_id string) (*models.DBTask, error) {
-	task, err := s.taskStore.TaskRepository.GetById(task_id)
+func (s *TaskService) GetTask(ctx context.Context, task_id string) (_ *models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.GetTask")
+	defer end(&err)
+
+	task, err := s.getTask(ctx, task_id)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.addProgress(ctx, task); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
-func (s *TaskService) GetTasksByUserID(user_id string) ([]models.DBTask, error) {
-	tasks, err := s.taskStore.TaskRepository.GetAllForUser(user_id)
+// GetTaskSubtree returns the task with its subtasks nested under it at every
+// depth.
+func (s *TaskService) GetTaskSubtree(ctx context.Context, task_id string) (_ *models.TaskTree, err error) {
+	ctx, end := startSpan(ctx, "TaskService.GetTaskSubtree")
+	defer end(&err)
+
+	root, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.taskStore.TaskRepository.GetSubtree(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*models.DBTask{root}
+	subtasks := make(map[string][]*models.DBTask)
+	for i := range descendants {
+		subtask := &descendants[i]
+		tasks = append(tasks, subtask)
+		subtasks[subtask.ParentID] = append(subtasks[subtask.ParentID], subtask)
+	}
+	if err := s.addProgress(ctx, tasks...); err != nil {
+		return nil, err
+	}
+
+	var build func(task *models.DBTask) models.TaskTree
+	build = func(task *models.DBTask) models.TaskTree {
+		node := models.TaskTree{DBTask: *task, Subtasks: make([]models.TaskTree, 0, len(subtasks[task.ID]))}
+		for _, subtask := range subtasks[task.ID] {
+			node.Subtasks = append(node.Subtasks, build(subtask))
+		}
+		return node
+	}
+
+	tree := build(root)
+	return &tree, nil
+}
+
+// ListTasks lists the caller's tasks. Any user ID in the query filter is
+// replaced by the caller's.
+func (s *TaskService) ListTasks(ctx context.Context, query models.TaskQuery) (_ *models.TaskPage, err error) {
+	ctx, end := startSpan(ctx, "TaskService.ListTasks")
+	defer end(&err)
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.Filter.UserID = userID
+
+	page, err := s.taskStore.TaskRepository.List(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	tasks := make([]*models.DBTask, len(page.Tasks))
+	for i := range page.Tasks {
+		tasks[i] = &page.Tasks[i]
+	}
+	if err := s.addProgress(ctx, tasks...); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
-func (s *TaskService) CreateTask(task models.DBTask) (*models.DBTask, error) {
-	createdTask, err := s.taskStore.TaskRepository.Create(&task)
+func (s *TaskService) CreateTask(ctx context.Context, request TaskRequest) (_ *models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.CreateTask")
+	defer end(&err)
+
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	task := request.task()
+	task.UserID = userID
+
+	s.workflow.Initialize(&task)
+	if err := validateTask(&task, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeCategory(ctx, task.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeParent(ctx, &task); err != nil {
+		return nil, err
+	}
+
+	var createdTask *models.DBTask
+	err = s.taskStore.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		var err error
+		createdTask, err = txStore.TaskRepository.Create(ctx, &task)
+		if err != nil {
+			return err
+		}
+
+		return s.rollUp(ctx, txStore.TaskRepository, task.ParentID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return createdTask, nil
 }
+
+// UpdateTask replaces every field of the task clients set with the values in
+// request.
+func (s *TaskService) UpdateTask(ctx context.Context, task_id string, request TaskRequest) (_ *models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.UpdateTask")
+	defer end(&err)
+
+	existingTask, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.saveTask(ctx, existingTask, request.task())
+}
+
+// PatchTask applies a JSON Merge Patch (RFC 7396) document to the task.
+func (s *TaskService) PatchTask(ctx context.Context, task_id string, patch []byte) (_ *models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.PatchTask")
+	defer end(&err)
+
+	if !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+		return nil, errors.NewBadRequestError("Merge patch must be a JSON object", fmt.Errorf("patch is not a JSON object")).WithCode(errors.CodeMalformedBody)
+	}
+
+	existingTask, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := json.Marshal(newTaskRequest(existingTask))
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to encode task", err)
+	}
+
+	patched, err := applyMergePatch(original, patch)
+	if err != nil {
+		return nil, errors.NewBadRequestError("Invalid merge patch document", err).WithCode(errors.CodeMalformedBody)
+	}
+
+	var request TaskRequest
+	if err := validation.Decode(patched, &request); err != nil {
+		return nil, err
+	}
+
+	return s.saveTask(ctx, existingTask, request.task())
+}
+
+// TransitionTask moves the task through the workflow using an explicit action
+// such as reopening a completed task.
+func (s *TaskService) TransitionTask(ctx context.Context, task_id string, action TaskAction) (_ *models.DBTask, err error) {
+	ctx, end := startSpan(ctx, "TaskService.TransitionTask")
+	defer end(&err)
+
+	existingTask, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.workflow.Perform(existingTask, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateTask(ctx, existingTask, task)
+}
+
+// DeleteTask deletes the task and, depending on policy, either its whole
+// subtree or nothing but the task itself, promoting its subtasks a level.
+func (s *TaskService) DeleteTask(ctx context.Context, task_id string, policy SubtaskPolicy) (err error) {
+	ctx, end := startSpan(ctx, "TaskService.DeleteTask")
+	defer end(&err)
+
+	existingTask, err := s.getTask(ctx, task_id)
+	if err != nil {
+		return err
+	}
+
+	return s.taskStore.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		tasks := txStore.TaskRepository
+
+		// Promoting subtasks moves them, so it is serialised with other
+		// moves like updateTask does.
+		if policy == PromoteSubtasks {
+			if err := txStore.UserRepository.Lock(ctx, existingTask.UserID); err != nil {
+				return err
+			}
+		}
+
+		subtree, err := tasks.GetSubtree(ctx, task_id)
+		if err != nil {
+			return err
+		}
+
+		if policy == PromoteSubtasks {
+			for i := range subtree {
+				subtask := &subtree[i]
+				if subtask.ParentID != task_id {
+					continue
+				}
+
+				promoted := *subtask
+				promoted.ParentID = existingTask.ParentID
+				if err := s.workflow.Apply(subtask, &promoted, ""); err != nil {
+					return err
+				}
+				if err := tasks.Update(ctx, &promoted); err != nil {
+					return err
+				}
+			}
+		} else {
+			// The subtree is ordered shallowest first, so deleting it
+			// backwards removes every subtask before its parent.
+			for i := len(subtree) - 1; i >= 0; i-- {
+				if err := tasks.Delete(ctx, subtree[i].ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tasks.Delete(ctx, task_id); err != nil {
+			return err
+		}
+
+		return s.rollUp(ctx, tasks, existingTask.ParentID)
+	})
+}
+
+// saveTask validates task and persists it over existingTask. Fields owned by
+// the server are always taken from existingTask, and the status change is
+// checked against the workflow.
+func (s *TaskService) saveTask(ctx context.Context, existingTask *models.DBTask, task models.DBTask) (*models.DBTask, error) {
+	task.ID = existingTask.ID
+	task.UserID = existingTask.UserID
+	task.CreatedAt = existingTask.CreatedAt
+
+	if err := validateTask(&task, existingTask); err != nil {
+		return nil, err
+	}
+
+	if task.CategoryID != existingTask.CategoryID {
+		if err := s.authorizeCategory(ctx, task.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.ParentID != existingTask.ParentID {
+		if err := s.authorizeParent(ctx, &task); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.workflow.Apply(existingTask, &task, ""); err != nil {
+		return nil, err
+	}
+
+	return s.updateTask(ctx, existingTask, &task)
+}
+
+// updateTask persists task over existingTask and rolls the change up the
+// hierarchy. A task moved to another parent takes its subtree with it. A task
+// cannot be started while a task it is blocked by is still open.
+func (s *TaskService) updateTask(ctx context.Context, existingTask *models.DBTask, task *models.DBTask) (*models.DBTask, error) {
+	// Only changes that can complete a task are rolled up, so that editing a
+	// subtask does not complete a parent that was reopened on purpose.
+	var rollUps []string
+	if task.AutoComplete && !existingTask.AutoComplete {
+		rollUps = append(rollUps, task.ID)
+	}
+	if task.Status != existingTask.Status || task.ParentID != existingTask.ParentID {
+		rollUps = append(rollUps, task.ParentID)
+	}
+	if task.ParentID != existingTask.ParentID {
+		rollUps = append(rollUps, existingTask.ParentID)
+	}
+
+	moved := task.ParentID != existingTask.ParentID
+	started := task.Status == models.InProgress && existingTask.Status != models.InProgress
+
+	err := s.taskStore.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		if moved || started {
+			if err := txStore.UserRepository.Lock(ctx, task.UserID); err != nil {
+				return err
+			}
+		}
+		if moved {
+			if err := checkParentCycle(ctx, txStore.TaskRepository, task); err != nil {
+				return err
+			}
+		}
+		if started {
+			if err := checkNotBlocked(ctx, txStore, task.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := txStore.TaskRepository.Update(ctx, task); err != nil {
+			return err
+		}
+
+		for _, id := range rollUps {
+			if err := s.rollUp(ctx, txStore.TaskRepository, id); err != nil {
+				return err
+			}
+		}
+
+		if len(rollUps) > 0 && rollUps[0] == task.ID {
+			updatedTask, err := txStore.TaskRepository.GetById(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+			task = updatedTask
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addProgress(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// rollUp completes the task with the given ID once all of its subtasks are
+// completed, if it asked to be, and carries on with its parent. It stops at
+// the first task that stays as it is.
+func (s *TaskService) rollUp(ctx context.Context, tasks task.TaskRepository, task_id string) error {
+	for task_id != "" {
+		existingTask, err := tasks.GetById(ctx, task_id)
+		if err != nil {
+			return err
+		}
+		if !existingTask.AutoComplete || existingTask.Status == models.Completed {
+			return nil
+		}
+
+		progress, err := tasks.GetSubtaskProgress(ctx, []string{task_id})
+		if err != nil {
+			return err
+		}
+		if counts, ok := progress[task_id]; !ok || counts.Completed < counts.Total {
+			return nil
+		}
+
+		// Auto-completion follows the workflow like any other status
+		// change; where the workflow does not allow it the task is left
+		// for its owner to complete.
+		completedTask := *existingTask
+		completedTask.Status = models.Completed
+		if err := s.workflow.Apply(existingTask, &completedTask, ActionComplete); err != nil {
+			return nil
+		}
+		if err := tasks.Update(ctx, &completedTask); err != nil {
+			return err
+		}
+
+		task_id = existingTask.ParentID
+	}
+
+	return nil
+}
+
+// getTask returns the task with the given ID if it belongs to the caller.
+func (s *TaskService) getTask(ctx context.Context, task_id string) (*models.DBTask, error) {
+	task, err := s.taskStore.TaskRepository.GetById(ctx, task_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeOwner(ctx, task.UserID, "task "+task_id, errors.CodeTaskNotFound); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// addProgress fills in the subtask progress of those of tasks that have
+// subtasks.
+func (s *TaskService) addProgress(ctx context.Context, tasks ...*models.DBTask) error {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	progress, err := s.taskStore.TaskRepository.GetSubtaskProgress(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if counts, ok := progress[task.ID]; ok {
+			task.Progress = &counts
+		}
+	}
+
+	return nil
+}
+
+// authorizeCategory checks that a category a task is being filed under
+// belongs to the caller. An empty ID leaves the task uncategorised.
+func (s *TaskService) authorizeCategory(ctx context.Context, category_id string) error {
+	if category_id == "" {
+		return nil
+	}
+
+	category, err := s.taskStore.CategoryRepository.GetById(ctx, category_id)
+	if err != nil {
+		return err
+	}
+
+	return authorizeOwner(ctx, category.UserID, "category "+category_id, errors.CodeCategoryNotFound)
+}
+
+// authorizeParent checks that the parent a task is being filed under
+// belongs to the caller. An empty ID makes the task a top-level one.
+func (s *TaskService) authorizeParent(ctx context.Context, task *models.DBTask) error {
+	if task.ParentID == "" {
+		return nil
+	}
+
+	_, err := s.getTask(ctx, task.ParentID)
+	return err
+}
+
+// checkParentCycle checks that the parent of an existing task is not the
+// task itself or one of its subtasks, which would make the hierarchy a
+// cycle. It must run in the unit of work that moves the task, with the
+// owner locked, so that two moves cannot each pass the check against the
+// hierarchy before the other.
+func checkParentCycle(ctx context.Context, tasks task.TaskRepository, task *models.DBTask) error {
+	if task.ParentID == "" {
+		return nil
+	}
+
+	cycle := task.ParentID == task.ID
+	if !cycle {
+		subtree, err := tasks.GetSubtree(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		for _, subtask := range subtree {
+			if subtask.ID == task.ParentID {
+				cycle = true
+				break
+			}
+		}
+	}
+
+	if cycle {
+		v := validation.New()
+		v.Add("parentID", "must not be the task itself or one of its subtasks")
+		return v.Err()
+	}
+
+	return nil
+}
+
+// validateTask checks the fields of task that clients set. The due date only
+// has to be in the future when it is set or moved, so that overdue tasks can
+// still be edited.
+func validateTask(task *models.DBTask, existingTask *models.DBTask) error {
+	v := validation.New()
+	validation.Check(v, "title", task.Title, validation.Required[string](), validation.MaxLength[string](maxTaskTitleLength))
+	validation.Check(v, "description", task.Description, validation.MaxLength[string](maxTaskDescriptionLength))
+	validation.Check(v, "priority", task.Priority, validation.OneOf(models.Low, models.Medium, models.High))
+	validation.Check(v, "status", task.Status, validation.OneOf(models.Pending, models.InProgress, models.Completed))
+	if existingTask == nil || !sameTime(task.DueDate, existingTask.DueDate) {
+		validation.Check(v, "dueDate", task.DueDate, validation.Future(time.Now()))
+	}
+
+	return v.Err()
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
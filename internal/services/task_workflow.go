@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+type TaskAction string
+
+const (
+	ActionStart    TaskAction = "start"
+	ActionComplete TaskAction = "complete"
+	ActionReopen   TaskAction = "reopen"
+)
+
+// actionTargets maps each explicit action to the status it moves a task to.
+var actionTargets = map[TaskAction]models.TaskStatus{
+	ActionStart:    models.InProgress,
+	ActionComplete: models.Completed,
+	ActionReopen:   models.Pending,
+}
+
+// Transition allows a task to move From one status To another. When Action
+// is set the move is only allowed through an explicit request for that
+// action; plain status edits through PUT or PATCH are rejected.
+type Transition struct {
+	From   models.TaskStatus
+	To     models.TaskStatus
+	Action TaskAction
+}
+
+type WorkflowConfig struct {
+	Transitions []Transition
+}
+
+func DefaultWorkflowConfig() WorkflowConfig {
+	return WorkflowConfig{
+		Transitions: []Transition{
+			{From: models.Pending, To: models.InProgress},
+			{From: models.Pending, To: models.Completed},
+			{From: models.InProgress, To: models.Pending},
+			{From: models.InProgress, To: models.Completed},
+			{From: models.Completed, To: models.Pending, Action: ActionReopen},
+		},
+	}
+}
+
+// AllowWithoutAction returns c with the transitions that require action
+// also open to plain status edits.
+func (c WorkflowConfig) AllowWithoutAction(action TaskAction) WorkflowConfig {
+	transitions := make([]Transition, len(c.Transitions))
+	for i, transition := range c.Transitions {
+		if transition.Action == action {
+			transition.Action = ""
+		}
+		transitions[i] = transition
+	}
+
+	return WorkflowConfig{Transitions: transitions}
+}
+
+// TaskWorkflow enforces the allowed status transitions and owns the
+// completedAt and updatedAt timestamps of a task.
+type TaskWorkflow struct {
+	transitions map[models.TaskStatus]map[models.TaskStatus]TaskAction
+	now         func() time.Time
+}
+
+func NewTaskWorkflow(config WorkflowConfig) *TaskWorkflow {
+	transitions := make(map[models.TaskStatus]map[models.TaskStatus]TaskAction)
+	for _, transition := range config.Transitions {
+		if transitions[transition.From] == nil {
+			transitions[transition.From] = make(map[models.TaskStatus]TaskAction)
+		}
+		transitions[transition.From][transition.To] = transition.Action
+	}
+
+	return &TaskWorkflow{
+		transitions: transitions,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Initialize stamps the timestamps of a task that is about to be created.
+func (w *TaskWorkflow) Initialize(task *models.DBTask) {
+	if task.Status == "" {
+		task.Status = models.Pending
+	}
+
+	task.CompletedAt = nil
+	if task.Status == models.Completed {
+		now := w.now()
+		task.CompletedAt = &now
+	}
+}
+
+// Apply checks that task may move from existingTask's status to its own and
+// sets completedAt and updatedAt accordingly. An empty action means the status
+// was edited directly rather than through an explicit action.
+func (w *TaskWorkflow) Apply(existingTask *models.DBTask, task *models.DBTask, action TaskAction) error {
+	from := existingTask.Status
+	to := task.Status
+
+	if from != to {
+		required, allowed := w.transitions[from][to]
+		if !allowed || (required != "" && required != action) {
+			return errors.NewInvalidTransitionError(
+				transitionMessage(from, to, required, allowed),
+				fmt.Errorf("transition %s -> %s not allowed for action %q", from, to, action),
+			)
+		}
+	}
+
+	now := w.now()
+	switch {
+	case to == models.Completed && from != models.Completed:
+		task.CompletedAt = &now
+	case to != models.Completed:
+		task.CompletedAt = nil
+	default:
+		task.CompletedAt = existingTask.CompletedAt
+	}
+	task.UpdatedAt = &now
+
+	return nil
+}
+
+// Perform moves task to the status targeted by action.
+func (w *TaskWorkflow) Perform(existingTask *models.DBTask, action TaskAction) (*models.DBTask, error) {
+	to, ok := actionTargets[action]
+	if !ok {
+		return nil, errors.NewBadRequestError(
+			fmt.Sprintf("Unknown action %q, must be one of %s, %s, %s", action, ActionStart, ActionComplete, ActionReopen),
+			fmt.Errorf("unknown action %q", action),
+		)
+	}
+
+	task := *existingTask
+	task.Status = to
+	if existingTask.Status == to {
+		return nil, errors.NewInvalidTransitionError(
+			fmt.Sprintf("Task is already %s", to),
+			fmt.Errorf("action %q on task already %s", action, to),
+		)
+	}
+
+	if err := w.Apply(existingTask, &task, action); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func transitionMessage(from, to models.TaskStatus, required TaskAction, allowed bool) string {
+	if allowed {
+		return fmt.Sprintf("Moving a task from %s to %s requires the %s action", from, to, required)
+	}
+	return fmt.Sprintf("Cannot move a task from %s to %s", from, to)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWorkflow(now time.Time) *TaskWorkflow {
+	workflow := NewTaskWorkflow(DefaultWorkflowConfig())
+	workflow.now = func() time.Time { return now }
+	return workflow
+}
+
+func TestTaskWorkflow(t *testing.T) {
+	now := time.Date(2025, time.July, 10, 9, 0, 0, 0, time.UTC)
+	earlier := now.Add(-48 * time.Hour)
+
+	t.Run("CompletingStampsCompletedAt", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{Status: models.InProgress}
+		task := &models.DBTask{Status: models.Completed}
+
+		err := workflow.Apply(existing, task, "")
+		assert.NoError(t, err)
+		assert.Equal(t, &now, task.CompletedAt)
+		assert.Equal(t, &now, task.UpdatedAt)
+	})
+
+	t.Run("StayingCompletedKeepsCompletedAt", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{Status: models.Completed, CompletedAt: &earlier}
+		task := &models.DBTask{Status: models.Completed, CompletedAt: &now}
+
+		err := workflow.Apply(existing, task, "")
+		assert.NoError(t, err)
+		assert.Equal(t, &earlier, task.CompletedAt)
+	})
+
+	t.Run("ClientCannotSetCompletedAt", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{Status: models.Pending}
+		task := &models.DBTask{Status: models.InProgress, CompletedAt: &earlier}
+
+		err := workflow.Apply(existing, task, "")
+		assert.NoError(t, err)
+		assert.Nil(t, task.CompletedAt)
+	})
+
+	t.Run("ReopenRequiresAction", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{Status: models.Completed, CompletedAt: &earlier}
+		task := &models.DBTask{Status: models.Pending}
+
+		err := workflow.Apply(existing, task, "")
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errors.ErrorTypeInvalidTransition, appErr.Type)
+		assert.Equal(t, http.StatusConflict, appErr.StatusCode)
+	})
+
+	t.Run("ReopenWithoutActionWhenAllowed", func(t *testing.T) {
+		workflow := NewTaskWorkflow(DefaultWorkflowConfig().AllowWithoutAction(ActionReopen))
+		existing := &models.DBTask{Status: models.Completed, CompletedAt: &earlier}
+		task := &models.DBTask{Status: models.Pending}
+
+		err := workflow.Apply(existing, task, "")
+		assert.NoError(t, err)
+		assert.Nil(t, task.CompletedAt)
+
+		_, err = workflow.Perform(existing, ActionReopen)
+		assert.NoError(t, err, "the reopen action still works")
+	})
+
+	t.Run("ReopenClearsCompletedAt", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{ID: "1", Status: models.Completed, CompletedAt: &earlier}
+
+		task, err := workflow.Perform(existing, ActionReopen)
+		assert.NoError(t, err)
+		assert.Equal(t, models.Pending, task.Status)
+		assert.Nil(t, task.CompletedAt)
+		assert.Equal(t, models.Completed, existing.Status)
+	})
+
+	t.Run("UnknownAction", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		existing := &models.DBTask{Status: models.Pending}
+
+		_, err := workflow.Perform(existing, "archive")
+		var appErr *errors.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	})
+
+	t.Run("ConfiguredTransitionsOnly", func(t *testing.T) {
+		workflow := NewTaskWorkflow(WorkflowConfig{
+			Transitions: []Transition{{From: models.Pending, To: models.InProgress}},
+		})
+		existing := &models.DBTask{Status: models.Pending}
+
+		err := workflow.Apply(existing, &models.DBTask{Status: models.Completed}, "")
+		assert.Error(t, err)
+
+		_, err = workflow.Perform(existing, ActionStart)
+		assert.NoError(t, err)
+	})
+
+	t.Run("InitializeCompletedTask", func(t *testing.T) {
+		workflow := newTestWorkflow(now)
+		task := &models.DBTask{Status: models.Completed}
+
+		workflow.Initialize(task)
+		assert.Equal(t, &now, task.CompletedAt)
+
+		task = &models.DBTask{CompletedAt: &earlier}
+		workflow.Initialize(task)
+		assert.Equal(t, models.Pending, task.Status)
+		assert.Nil(t, task.CompletedAt)
+	})
+}
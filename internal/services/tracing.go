@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/tracing"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/kjj1998/task-management-system/internal/services")
+
+// startSpan starts the span of a service call. The function it returns ends
+// the span; it marks the span failed when the call returned a server error,
+// but not when it turned down a bad request.
+func startSpan(ctx context.Context, name string) (context.Context, func(err *error)) {
+	ctx, span := tracer.Start(ctx, name)
+
+	return ctx, func(err *error) {
+		var appErr *errors.AppError
+		if *err != nil && stderrors.As(*err, &appErr) && appErr.StatusCode < http.StatusInternalServerError {
+			span.End()
+			return
+		}
+		tracing.End(span, *err)
+	}
+}
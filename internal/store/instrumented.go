@@ -0,0 +1,241 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/apikey"
+	"github.com/kjj1998/task-management-system/internal/repository/category"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+	"github.com/kjj1998/task-management-system/internal/repository/task"
+	"github.com/kjj1998/task-management-system/internal/repository/token"
+	"github.com/kjj1998/task-management-system/internal/repository/user"
+)
+
+// OperationObserver is told how long each repository operation took and
+// what it returned. Operations are named as they are for
+// HandleDatabaseError, whichever backend runs them.
+type OperationObserver interface {
+	ObserveOperation(operation string, duration time.Duration, err error)
+}
+
+// instrument wraps the repositories of s so each operation is reported to
+// observer.
+func (s *DatabaseTaskStore) instrument(observer OperationObserver) {
+	if observer == nil {
+		return
+	}
+
+	s.observer = observer
+	s.UserRepository = &instrumentedUsers{next: s.UserRepository, observer: observer}
+	s.CategoryRepository = &instrumentedCategories{next: s.CategoryRepository, observer: observer}
+	s.TaskRepository = &instrumentedTasks{next: s.TaskRepository, observer: observer}
+	s.TokenRepository = &instrumentedTokens{next: s.TokenRepository, observer: observer}
+	s.APIKeyRepository = &instrumentedAPIKeys{next: s.APIKeyRepository, observer: observer}
+	s.DependencyRepository = &instrumentedDependencies{next: s.DependencyRepository, observer: observer}
+}
+
+func observe(observer OperationObserver, operation string, start time.Time, err error) {
+	observer.ObserveOperation(operation, time.Since(start), err)
+}
+
+type instrumentedUsers struct {
+	next     user.UserRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedUsers) GetById(ctx context.Context, id string) (u *models.DBUser, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetUserByID", start, err) }(time.Now())
+	return r.next.GetById(ctx, id)
+}
+
+func (r *instrumentedUsers) GetByEmail(ctx context.Context, email string) (u *models.DBUser, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetUserByEmail", start, err) }(time.Now())
+	return r.next.GetByEmail(ctx, email)
+}
+
+func (r *instrumentedUsers) Create(ctx context.Context, user *models.DBUser) (u *models.DBUser, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateUser", start, err) }(time.Now())
+	return r.next.Create(ctx, user)
+}
+
+func (r *instrumentedUsers) Update(ctx context.Context, user *models.DBUser) (err error) {
+	defer func(start time.Time) { observe(r.observer, "UpdateUser", start, err) }(time.Now())
+	return r.next.Update(ctx, user)
+}
+
+func (r *instrumentedUsers) Delete(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "DeleteUser", start, err) }(time.Now())
+	return r.next.Delete(ctx, id)
+}
+
+func (r *instrumentedUsers) Lock(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "LockUser", start, err) }(time.Now())
+	return r.next.Lock(ctx, id)
+}
+
+type instrumentedCategories struct {
+	next     category.CategoryRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedCategories) GetAllForUser(ctx context.Context, userID string) (c []models.DBCategory, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAllCategoriesForUser", start, err) }(time.Now())
+	return r.next.GetAllForUser(ctx, userID)
+}
+
+func (r *instrumentedCategories) GetById(ctx context.Context, categoryID string) (c *models.DBCategory, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetCategoryByID", start, err) }(time.Now())
+	return r.next.GetById(ctx, categoryID)
+}
+
+func (r *instrumentedCategories) Create(ctx context.Context, category *models.DBCategory) (c *models.DBCategory, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateCategory", start, err) }(time.Now())
+	return r.next.Create(ctx, category)
+}
+
+func (r *instrumentedCategories) Update(ctx context.Context, category *models.DBCategory) (err error) {
+	defer func(start time.Time) { observe(r.observer, "UpdateCategory", start, err) }(time.Now())
+	return r.next.Update(ctx, category)
+}
+
+func (r *instrumentedCategories) Delete(ctx context.Context, categoryID string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "DeleteCategory", start, err) }(time.Now())
+	return r.next.Delete(ctx, categoryID)
+}
+
+type instrumentedTasks struct {
+	next     task.TaskRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedTasks) Create(ctx context.Context, task *models.DBTask) (t *models.DBTask, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateTask", start, err) }(time.Now())
+	return r.next.Create(ctx, task)
+}
+
+func (r *instrumentedTasks) GetAllForUser(ctx context.Context, userID string) (t []models.DBTask, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAllTasksForUser", start, err) }(time.Now())
+	return r.next.GetAllForUser(ctx, userID)
+}
+
+func (r *instrumentedTasks) List(ctx context.Context, query models.TaskQuery) (p *models.TaskPage, err error) {
+	defer func(start time.Time) { observe(r.observer, "ListTasks", start, err) }(time.Now())
+	return r.next.List(ctx, query)
+}
+
+func (r *instrumentedTasks) GetById(ctx context.Context, taskID string) (t *models.DBTask, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetTaskByID", start, err) }(time.Now())
+	return r.next.GetById(ctx, taskID)
+}
+
+func (r *instrumentedTasks) GetByIds(ctx context.Context, taskIDs []string) (t []models.DBTask, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetTasksByIDs", start, err) }(time.Now())
+	return r.next.GetByIds(ctx, taskIDs)
+}
+
+func (r *instrumentedTasks) Update(ctx context.Context, task *models.DBTask) (err error) {
+	defer func(start time.Time) { observe(r.observer, "UpdateTask", start, err) }(time.Now())
+	return r.next.Update(ctx, task)
+}
+
+func (r *instrumentedTasks) Delete(ctx context.Context, taskID string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "DeleteTask", start, err) }(time.Now())
+	return r.next.Delete(ctx, taskID)
+}
+
+func (r *instrumentedTasks) GetSubtree(ctx context.Context, taskID string) (t []models.DBTask, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetTaskSubtree", start, err) }(time.Now())
+	return r.next.GetSubtree(ctx, taskID)
+}
+
+func (r *instrumentedTasks) GetSubtaskProgress(ctx context.Context, parentIDs []string) (p map[string]models.SubtaskProgress, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetSubtaskProgress", start, err) }(time.Now())
+	return r.next.GetSubtaskProgress(ctx, parentIDs)
+}
+
+type instrumentedTokens struct {
+	next     token.RefreshTokenRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedTokens) Create(ctx context.Context, token *models.DBRefreshToken) (t *models.DBRefreshToken, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateRefreshToken", start, err) }(time.Now())
+	return r.next.Create(ctx, token)
+}
+
+func (r *instrumentedTokens) GetByHash(ctx context.Context, tokenHash string) (t *models.DBRefreshToken, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetRefreshTokenByHash", start, err) }(time.Now())
+	return r.next.GetByHash(ctx, tokenHash)
+}
+
+func (r *instrumentedTokens) Revoke(ctx context.Context, tokenID string, replacedBy string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "RevokeRefreshToken", start, err) }(time.Now())
+	return r.next.Revoke(ctx, tokenID, replacedBy)
+}
+
+func (r *instrumentedTokens) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "RevokeRefreshTokenFamily", start, err) }(time.Now())
+	return r.next.RevokeFamily(ctx, familyID)
+}
+
+type instrumentedAPIKeys struct {
+	next     apikey.APIKeyRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedAPIKeys) Create(ctx context.Context, key *models.DBAPIKey) (k *models.DBAPIKey, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateAPIKey", start, err) }(time.Now())
+	return r.next.Create(ctx, key)
+}
+
+func (r *instrumentedAPIKeys) GetAllForUser(ctx context.Context, userID string) (k []models.DBAPIKey, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAllAPIKeysForUser", start, err) }(time.Now())
+	return r.next.GetAllForUser(ctx, userID)
+}
+
+func (r *instrumentedAPIKeys) GetById(ctx context.Context, keyID string) (k *models.DBAPIKey, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAPIKeyByID", start, err) }(time.Now())
+	return r.next.GetById(ctx, keyID)
+}
+
+func (r *instrumentedAPIKeys) GetByHash(ctx context.Context, keyHash string) (k *models.DBAPIKey, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAPIKeyByHash", start, err) }(time.Now())
+	return r.next.GetByHash(ctx, keyHash)
+}
+
+func (r *instrumentedAPIKeys) Revoke(ctx context.Context, keyID string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "RevokeAPIKey", start, err) }(time.Now())
+	return r.next.Revoke(ctx, keyID)
+}
+
+func (r *instrumentedAPIKeys) TouchLastUsed(ctx context.Context, keyID string, usedAt time.Time) (err error) {
+	defer func(start time.Time) { observe(r.observer, "TouchAPIKeyLastUsed", start, err) }(time.Now())
+	return r.next.TouchLastUsed(ctx, keyID, usedAt)
+}
+
+type instrumentedDependencies struct {
+	next     dependency.DependencyRepository
+	observer OperationObserver
+}
+
+func (r *instrumentedDependencies) Create(ctx context.Context, dependency *models.DBTaskDependency) (d *models.DBTaskDependency, err error) {
+	defer func(start time.Time) { observe(r.observer, "CreateDependency", start, err) }(time.Now())
+	return r.next.Create(ctx, dependency)
+}
+
+func (r *instrumentedDependencies) GetAllForUser(ctx context.Context, userID string) (d []models.DBTaskDependency, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetAllDependenciesForUser", start, err) }(time.Now())
+	return r.next.GetAllForUser(ctx, userID)
+}
+
+func (r *instrumentedDependencies) GetForTask(ctx context.Context, taskID string) (d []models.DBTaskDependency, err error) {
+	defer func(start time.Time) { observe(r.observer, "GetDependenciesForTask", start, err) }(time.Now())
+	return r.next.GetForTask(ctx, taskID)
+}
+
+func (r *instrumentedDependencies) Delete(ctx context.Context, taskID string, blockedByID string) (err error) {
+	defer func(start time.Time) { observe(r.observer, "DeleteDependency", start, err) }(time.Now())
+	return r.next.Delete(ctx, taskID, blockedByID)
+}
@@ -1,34 +1,238 @@
 package store
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
 
+	"github.com/kjj1998/task-management-system/internal/database"
 	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
 	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/apikey"
 	"github.com/kjj1998/task-management-system/internal/repository/category"
+	"github.com/kjj1998/task-management-system/internal/repository/dependency"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/repository/postgres"
+	"github.com/kjj1998/task-management-system/internal/repository/sqlite"
 	"github.com/kjj1998/task-management-system/internal/repository/task"
+	"github.com/kjj1998/task-management-system/internal/repository/token"
 	"github.com/kjj1998/task-management-system/internal/repository/user"
 )
 
+// maxTxAttempts is how many times WithTx runs a unit of work that keeps
+// being chosen as a deadlock victim.
+const maxTxAttempts = 3
+
 type DatabaseTaskStore struct {
-	UserRepository     user.UserRepository
-	CategoryRepository category.CategoryRepository
-	TaskRepository     task.TaskRepository
+	UserRepository       user.UserRepository
+	CategoryRepository   category.CategoryRepository
+	TaskRepository       task.TaskRepository
+	TokenRepository      token.RefreshTokenRepository
+	APIKeyRepository     apikey.APIKeyRepository
+	DependencyRepository dependency.DependencyRepository
+
+	db           *sql.DB
+	errorHandler *errors.DatabaseErrorHandler
+	logger       *slog.Logger
+	// newSQLStore builds the repositories of a SQL backend on a connection.
+	newSQLStore func(conn database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore
+	// unitOfWork runs fn with repositories bound to one transaction. It is
+	// nil for stores that are already inside a unit of work.
+	unitOfWork func(ctx context.Context, fn func(txStore *DatabaseTaskStore) error) error
+	// observer is told about every repository operation, including those
+	// of units of work. It is nil unless the store is instrumented.
+	observer OperationObserver
 }
 
 func NewDatabaseTaskStore(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
-	store := &DatabaseTaskStore{}
+	return newDatabaseStore(db, newMySQLStore, errorHandler, logger)
+}
+
+// NewPostgresTaskStore returns a store backed by a PostgreSQL database with
+// the schema in migrations/postgres.
+func NewPostgresTaskStore(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	return newDatabaseStore(db, newPostgresStore, errorHandler, logger)
+}
+
+// NewSQLiteTaskStore returns a store backed by a SQLite database file with
+// the schema in migrations/sqlite.
+func NewSQLiteTaskStore(db *sql.DB, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	return newDatabaseStore(db, newSQLiteStore, errorHandler, logger)
+}
+
+func newDatabaseStore(
+	db *sql.DB,
+	newSQLStore func(conn database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore,
+	errorHandler *errors.DatabaseErrorHandler,
+	logger *slog.Logger,
+) *DatabaseTaskStore {
+	store := newSQLStore(database.NewConn(db), errorHandler, logger)
+	store.db = db
+	store.newSQLStore = newSQLStore
+	store.unitOfWork = store.withSQLTx
 
-	store.UserRepository = user.NewUserRepository(db, errorHandler, logger)
-	store.CategoryRepository = category.NewCategoryRepository(db, errorHandler, logger)
-	store.TaskRepository = task.NewTaskRepository(db, errorHandler, logger)
+	return store
+}
+
+// NewMemoryTaskStore returns a store backed by an in-memory database, for
+// tests and demo mode.
+func NewMemoryTaskStore(db *memory.Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	store := newMemoryStore(db, errorHandler, logger)
+	store.unitOfWork = func(ctx context.Context, fn func(txStore *DatabaseTaskStore) error) error {
+		return db.Atomically(func(tx *memory.Database) error {
+			txStore := newMemoryStore(tx, errorHandler, logger)
+			txStore.instrument(store.observer)
+			return fn(txStore)
+		})
+	}
 
 	return store
 }
 
-func (s *DatabaseTaskStore) GetTask(taskID string) (*models.DBTask, error) {
-	task, err := s.TaskRepository.GetById(taskID)
+func newMySQLStore(conn database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	store := &DatabaseTaskStore{
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+
+	store.UserRepository = user.NewUserRepository(conn, errorHandler, logger)
+	store.CategoryRepository = category.NewCategoryRepository(conn, errorHandler, logger)
+	store.TaskRepository = task.NewTaskRepository(conn, errorHandler, logger)
+	store.TokenRepository = token.NewRefreshTokenRepository(conn, errorHandler, logger)
+	store.APIKeyRepository = apikey.NewAPIKeyRepository(conn, errorHandler, logger)
+	store.DependencyRepository = dependency.NewDependencyRepository(conn, errorHandler, logger)
+
+	return store
+}
+
+func newPostgresStore(conn database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	store := &DatabaseTaskStore{
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+
+	store.UserRepository = postgres.NewUserRepository(conn, errorHandler, logger)
+	store.CategoryRepository = postgres.NewCategoryRepository(conn, errorHandler, logger)
+	store.TaskRepository = postgres.NewTaskRepository(conn, errorHandler, logger)
+	store.TokenRepository = postgres.NewRefreshTokenRepository(conn, errorHandler, logger)
+	store.APIKeyRepository = postgres.NewAPIKeyRepository(conn, errorHandler, logger)
+	store.DependencyRepository = postgres.NewDependencyRepository(conn, errorHandler, logger)
+
+	return store
+}
+
+func newSQLiteStore(conn database.Conn, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	store := &DatabaseTaskStore{
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+
+	store.UserRepository = sqlite.NewUserRepository(conn, errorHandler, logger)
+	store.CategoryRepository = sqlite.NewCategoryRepository(conn, errorHandler, logger)
+	store.TaskRepository = sqlite.NewTaskRepository(conn, errorHandler, logger)
+	store.TokenRepository = sqlite.NewRefreshTokenRepository(conn, errorHandler, logger)
+	store.APIKeyRepository = sqlite.NewAPIKeyRepository(conn, errorHandler, logger)
+	store.DependencyRepository = sqlite.NewDependencyRepository(conn, errorHandler, logger)
+
+	return store
+}
+
+func newMemoryStore(db *memory.Database, errorHandler *errors.DatabaseErrorHandler, logger *slog.Logger) *DatabaseTaskStore {
+	store := &DatabaseTaskStore{
+		errorHandler: errorHandler,
+		logger:       logger,
+	}
+
+	store.UserRepository = memory.NewUserRepository(db, errorHandler, logger)
+	store.CategoryRepository = memory.NewCategoryRepository(db, errorHandler, logger)
+	store.TaskRepository = memory.NewTaskRepository(db, errorHandler, logger)
+	store.TokenRepository = memory.NewRefreshTokenRepository(db, errorHandler, logger)
+	store.APIKeyRepository = memory.NewAPIKeyRepository(db, errorHandler, logger)
+	store.DependencyRepository = memory.NewDependencyRepository(db, errorHandler, logger)
+
+	return store
+}
+
+// Instrument reports every repository operation of the store to observer,
+// which must not be nil.
+func (s *DatabaseTaskStore) Instrument(observer OperationObserver) {
+	s.instrument(observer)
+}
+
+// WithTx runs fn as a single unit of work. The store passed to fn has
+// repositories bound to one transaction, which is committed when fn returns
+// nil and rolled back when it returns an error or panics. If the database
+// aborts the transaction to break a deadlock, fn is run again in a new
+// transaction, so it must not have side effects outside the store.
+//
+// Calling WithTx on a store that is already inside a unit of work runs fn
+// in the existing transaction. The in-memory store serialises units of work
+// instead of retrying them.
+func (s *DatabaseTaskStore) WithTx(ctx context.Context, fn func(txStore *DatabaseTaskStore) error) error {
+	if s.unitOfWork == nil {
+		return fn(s)
+	}
+
+	return s.unitOfWork(ctx, fn)
+}
+
+func (s *DatabaseTaskStore) withSQLTx(ctx context.Context, fn func(txStore *DatabaseTaskStore) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = s.runTx(ctx, fn)
+		if err == nil || !errors.IsDeadlock(err) {
+			return err
+		}
+
+		logger.FromContext(ctx, s.logger).WarnContext(ctx, "transaction deadlocked, retrying",
+			slog.Int("attempt", attempt),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return err
+}
+
+func (s *DatabaseTaskStore) runTx(ctx context.Context, fn func(txStore *DatabaseTaskStore) error) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return s.errorHandler.HandleDatabaseError("BeginTransaction", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			s.rollback(ctx, tx)
+			panic(p)
+		}
+		if err != nil {
+			s.rollback(ctx, tx)
+		}
+	}()
+
+	txStore := s.newSQLStore(database.NewTxConn(tx), s.errorHandler, s.logger)
+	txStore.instrument(s.observer)
+
+	if err := fn(txStore); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return s.errorHandler.HandleDatabaseError("CommitTransaction", fmt.Errorf("failed to commit transaction: %w", err))
+	}
+
+	return nil
+}
+
+func (s *DatabaseTaskStore) rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		logger.FromContext(ctx, s.logger).WarnContext(ctx, "failed to rollback transaction", slog.String("error", err.Error()))
+	}
+}
+
+func (s *DatabaseTaskStore) GetTask(ctx context.Context, taskID string) (*models.DBTask, error) {
+	task, err := s.TaskRepository.GetById(ctx, taskID)
 	if err != nil {
 		return task, err
 	}
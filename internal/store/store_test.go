@@ -0,0 +1,249 @@
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/kjj1998/task-management-system/internal/database"
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/logger"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/repository/memory"
+	"github.com/kjj1998/task-management-system/internal/repository/repositorytest"
+	"github.com/kjj1998/task-management-system/internal/repository/testutils"
+	"github.com/kjj1998/task-management-system/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// seededOwnerID is the user the MySQL test database is seeded with.
+const seededOwnerID = "1244ABC"
+
+type StoreTestSuite struct {
+	suite.Suite
+	mySQLContainer *testutils.MySQLContainer
+	ctx            context.Context
+	store          *store.DatabaseTaskStore
+	ownerID        string
+}
+
+func (suite *StoreTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	mySQLContainer, err := testutils.CreateMySQLContainer(suite.ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suite.mySQLContainer = mySQLContainer
+	host, _ := mySQLContainer.Container.Host(suite.ctx)
+	port, _ := mySQLContainer.Container.MappedPort(suite.ctx, "3306")
+
+	err = database.Connect("testuser", "testpass", host, port.Port(), "taskapi", logger)
+	suite.Require().NoError(err, "Failed to connect to test database")
+	db := database.GetDb()
+	dbErrorHandler := errors.NewDatabaseErrorHandler()
+
+	suite.store = store.NewDatabaseTaskStore(db, dbErrorHandler, logger)
+	suite.ownerID = seededOwnerID
+}
+
+func (suite *StoreTestSuite) TearDownSuite() {
+	if err := suite.mySQLContainer.Container.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating mysql container: %s", err)
+	}
+}
+
+// createCategoryWithTask creates a category and files a new task under it in
+// the same unit of work.
+func createCategoryWithTask(ctx context.Context, txStore *store.DatabaseTaskStore, ownerID string, name string) (string, string, error) {
+	category, err := txStore.CategoryRepository.Create(ctx, &models.DBCategory{UserID: ownerID, Name: name, Color: "#007bff"})
+	if err != nil {
+		return "", "", err
+	}
+
+	task, err := txStore.TaskRepository.Create(ctx, &models.DBTask{
+		UserID:     ownerID,
+		CategoryID: category.ID,
+		Title:      "Filed task",
+		Priority:   models.Low,
+		Status:     models.Pending,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return category.ID, task.ID, nil
+}
+
+func (suite *StoreTestSuite) assertNotFound(t *testing.T, err error) {
+	t.Helper()
+
+	var appErr *errors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	}
+}
+
+func (suite *StoreTestSuite) TestWithTx() {
+	t := suite.T()
+
+	t.Run("CommitsOnSuccess", func(t *testing.T) {
+		var categoryID, taskID string
+		err := suite.store.WithTx(suite.ctx, func(txStore *store.DatabaseTaskStore) error {
+			var err error
+			categoryID, taskID, err = createCategoryWithTask(suite.ctx, txStore, suite.ownerID, "committed")
+			return err
+		})
+		assert.NoError(t, err)
+
+		_, err = suite.store.CategoryRepository.GetById(suite.ctx, categoryID)
+		assert.NoError(t, err)
+		task, err := suite.store.TaskRepository.GetById(suite.ctx, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, categoryID, task.CategoryID)
+	})
+
+	t.Run("RollsBackOnError", func(t *testing.T) {
+		var categoryID, taskID string
+		err := suite.store.WithTx(suite.ctx, func(txStore *store.DatabaseTaskStore) error {
+			var err error
+			categoryID, taskID, err = createCategoryWithTask(suite.ctx, txStore, suite.ownerID, "rolled back")
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("abort unit of work")
+		})
+		assert.EqualError(t, err, "abort unit of work")
+
+		_, err = suite.store.CategoryRepository.GetById(suite.ctx, categoryID)
+		suite.assertNotFound(t, err)
+		_, err = suite.store.TaskRepository.GetById(suite.ctx, taskID)
+		suite.assertNotFound(t, err)
+	})
+
+	t.Run("RollsBackOnPanic", func(t *testing.T) {
+		var categoryID string
+		assert.Panics(t, func() {
+			_ = suite.store.WithTx(suite.ctx, func(txStore *store.DatabaseTaskStore) error {
+				var err error
+				categoryID, _, err = createCategoryWithTask(suite.ctx, txStore, suite.ownerID, "panicked")
+				if err != nil {
+					return err
+				}
+				panic("unit of work panicked")
+			})
+		})
+
+		_, err := suite.store.CategoryRepository.GetById(suite.ctx, categoryID)
+		suite.assertNotFound(t, err)
+	})
+
+	t.Run("NestedUnitOfWorkJoinsTransaction", func(t *testing.T) {
+		var categoryID string
+		err := suite.store.WithTx(suite.ctx, func(txStore *store.DatabaseTaskStore) error {
+			err := txStore.WithTx(suite.ctx, func(nested *store.DatabaseTaskStore) error {
+				var err error
+				categoryID, _, err = createCategoryWithTask(suite.ctx, nested, suite.ownerID, "nested")
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("abort outer unit of work")
+		})
+		assert.Error(t, err)
+
+		_, err = suite.store.CategoryRepository.GetById(suite.ctx, categoryID)
+		suite.assertNotFound(t, err)
+	})
+
+	t.Run("RetriesDeadlocks", func(t *testing.T) {
+		attempts := 0
+		err := suite.store.WithTx(suite.ctx, func(txStore *store.DatabaseTaskStore) error {
+			attempts++
+			if attempts < 2 {
+				return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+}
+
+func (suite *StoreTestSuite) TestRepositoryConformance() {
+	repositorytest.Run(suite.T(), repositorytest.Repositories{
+		Users:        suite.store.UserRepository,
+		Categories:   suite.store.CategoryRepository,
+		Tasks:        suite.store.TaskRepository,
+		Dependencies: suite.store.DependencyRepository,
+	})
+}
+
+func TestStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(StoreTestSuite))
+}
+
+// SQLiteStoreTestSuite runs the store tests against a SQLite database file,
+// which needs no container.
+type SQLiteStoreTestSuite struct {
+	StoreTestSuite
+	db *sql.DB
+}
+
+func (suite *SQLiteStoreTestSuite) SetupSuite() {
+	logger := logger.NewLogger("test")
+	suite.ctx = context.Background()
+
+	db, err := testutils.CreateSQLiteDatabase(suite.T().TempDir(), logger)
+	suite.Require().NoError(err, "Failed to create test database")
+	suite.db = db
+	suite.store = store.NewSQLiteTaskStore(db, errors.NewDatabaseErrorHandler(), logger)
+
+	owner, err := suite.store.UserRepository.Create(suite.ctx, &models.DBUser{Email: "john@email.com", PasswordHash: "DSFE32423X", FirstName: "John", LastName: "Doe"})
+	suite.Require().NoError(err)
+	suite.ownerID = owner.ID
+}
+
+func (suite *SQLiteStoreTestSuite) TearDownSuite() {
+	suite.db.Close()
+}
+
+func TestSQLiteStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLiteStoreTestSuite))
+}
+
+type operationLog []string
+
+func (o *operationLog) ObserveOperation(operation string, duration time.Duration, err error) {
+	*o = append(*o, operation)
+}
+
+func TestInstrument(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryTaskStore(memory.NewDatabase(), errors.NewDatabaseErrorHandler(), logger.NewLogger("test"))
+	observed := &operationLog{}
+	s.Instrument(observed)
+
+	owner, err := s.UserRepository.Create(ctx, &models.DBUser{Email: "john@email.com", FirstName: "John", LastName: "Doe"})
+	assert.NoError(t, err)
+
+	err = s.WithTx(ctx, func(txStore *store.DatabaseTaskStore) error {
+		_, _, err := createCategoryWithTask(ctx, txStore, owner.ID, "routine")
+		return err
+	})
+	assert.NoError(t, err)
+
+	_, err = s.TaskRepository.GetById(ctx, "missing")
+	assert.Error(t, err)
+
+	assert.Equal(t, operationLog{"CreateUser", "CreateCategory", "CreateTask", "GetTaskByID"}, *observed)
+}
@@ -0,0 +1,46 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kjj1998/task-management-system/internal/config"
+	"github.com/kjj1998/task-management-system/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+func TestTraceID(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+		Exporter:    config.TracingExporterNone,
+		ServiceName: "test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { shutdown(context.Background()) })
+
+	var traceID string
+	handler := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = tracing.TraceID(r.Context())
+	}), "test")
+
+	t.Run("NewTrace", func(t *testing.T) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Len(t, traceID, 32, "requests get a trace ID even when no exporter is configured")
+	})
+
+	t.Run("PropagatedTrace", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	})
+
+	t.Run("OutsideTrace", func(t *testing.T) {
+		assert.Empty(t, tracing.TraceID(context.Background()))
+	})
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+// Decode unmarshals the JSON object in data into dst. Unlike json.Unmarshal
+// it rejects fields dst does not have, so clients cannot set fields owned by
+// the server, and anything after the object.
+func Decode(data []byte, dst any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.NewBadRequestError("Request body must contain a single JSON object", fmt.Errorf("data after the json object")).WithCode(errors.CodeMalformedBody)
+	}
+
+	return nil
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) && typeErr.Field != "" {
+		message := "must be " + jsonType(typeErr.Type.Kind())
+		return errors.NewValidationError(
+			fmt.Sprintf("Invalid request: %s %s", typeErr.Field, message),
+			models.FieldError{Field: typeErr.Field, Message: message},
+		)
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field = strings.Trim(field, `"`)
+		return errors.NewValidationError(
+			fmt.Sprintf("Invalid request: %s is not a known field", field),
+			models.FieldError{Field: field, Message: "is not a known field"},
+		)
+	}
+
+	return errors.NewBadRequestError("Error parsing json body", err).WithCode(errors.CodeMalformedBody)
+}
+
+// jsonType names the JSON type a value of kind is decoded from.
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a number"
+	}
+}
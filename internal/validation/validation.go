@@ -0,0 +1,123 @@
+// Package validation checks request payloads field by field, so that a
+// rejected request reports every invalid field at once rather than only the
+// first one found.
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+)
+
+// Rule checks a value, returning why it is invalid or "" when it is valid.
+type Rule[T any] func(value T) string
+
+// Validator collects the invalid fields of a request.
+type Validator struct {
+	fields []models.FieldError
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Check applies rules to the value of field in order, recording the first
+// one that fails.
+func Check[T any](v *Validator, field string, value T, rules ...Rule[T]) {
+	for _, rule := range rules {
+		if message := rule(value); message != "" {
+			v.Add(field, message)
+			return
+		}
+	}
+}
+
+// Add records that field is invalid.
+func (v *Validator) Add(field string, message string) {
+	v.fields = append(v.fields, models.FieldError{Field: field, Message: message})
+}
+
+// Valid reports whether no invalid field has been recorded.
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err returns a validation error listing every invalid field, or nil when
+// there is none.
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+
+	reasons := make([]string, len(v.fields))
+	for i, field := range v.fields {
+		reasons[i] = field.Field + " " + field.Message
+	}
+
+	return errors.NewValidationError("Invalid request: "+strings.Join(reasons, "; "), v.fields...)
+}
+
+// Required rejects empty and blank strings.
+func Required[T ~string]() Rule[T] {
+	return func(value T) string {
+		if strings.TrimSpace(string(value)) == "" {
+			return "is required"
+		}
+		return ""
+	}
+}
+
+// MaxLength rejects strings longer than max characters.
+func MaxLength[T ~string](max int) Rule[T] {
+	return func(value T) string {
+		if utf8.RuneCountInString(string(value)) > max {
+			return fmt.Sprintf("must be at most %d characters", max)
+		}
+		return ""
+	}
+}
+
+// OneOf rejects values that are not one of allowed.
+func OneOf[T ~string](allowed ...T) Rule[T] {
+	names := make([]string, len(allowed))
+	for i, value := range allowed {
+		names[i] = string(value)
+	}
+	message := "must be one of " + strings.Join(names, ", ")
+
+	return func(value T) string {
+		for _, candidate := range allowed {
+			if value == candidate {
+				return ""
+			}
+		}
+		return message
+	}
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// HexColor rejects strings that are not a #rgb or #rrggbb colour.
+func HexColor[T ~string]() Rule[T] {
+	return func(value T) string {
+		if !hexColorPattern.MatchString(string(value)) {
+			return "must be a hex colour such as #007bff"
+		}
+		return ""
+	}
+}
+
+// Future rejects times that are not after now. A missing time is accepted.
+func Future(now time.Time) Rule[*time.Time] {
+	return func(value *time.Time) string {
+		if value != nil && !value.After(now) {
+			return "must be in the future"
+		}
+		return ""
+	}
+}
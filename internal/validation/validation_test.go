@@ -0,0 +1,98 @@
+package validation_test
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjj1998/task-management-system/internal/errors"
+	"github.com/kjj1998/task-management-system/internal/models"
+	"github.com/kjj1998/task-management-system/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appError(t *testing.T, err error) *errors.AppError {
+	t.Helper()
+
+	var appErr *errors.AppError
+	require.True(t, stderrors.As(err, &appErr), "expected an AppError, got %v", err)
+	return appErr
+}
+
+func TestRules(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.Equal(t, "is required", validation.Required[string]()("  "))
+	assert.Empty(t, validation.Required[string]()("title"))
+
+	assert.Equal(t, "must be at most 3 characters", validation.MaxLength[string](3)("abcd"))
+	assert.Empty(t, validation.MaxLength[string](3)("äöü"), "length is counted in characters")
+
+	oneOf := validation.OneOf(models.Low, models.Medium, models.High)
+	assert.Equal(t, "must be one of low, medium, high", oneOf("urgent"))
+	assert.Empty(t, oneOf(models.High))
+
+	assert.NotEmpty(t, validation.HexColor[string]()("red"))
+	assert.Empty(t, validation.HexColor[string]()("#F00"))
+
+	assert.Equal(t, "must be in the future", validation.Future(now)(&past))
+	assert.Empty(t, validation.Future(now)(&future))
+	assert.Empty(t, validation.Future(now)(nil))
+}
+
+func TestValidatorAggregatesFields(t *testing.T) {
+	v := validation.New()
+	validation.Check(v, "title", "", validation.Required[string](), validation.MaxLength[string](3))
+	validation.Check(v, "priority", models.TaskPriority("urgent"), validation.OneOf(models.Low, models.High))
+	validation.Check(v, "color", "#fff", validation.HexColor[string]())
+
+	require.False(t, v.Valid())
+	appErr := appError(t, v.Err())
+	assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+	assert.Equal(t, errors.CodeValidationFailed, appErr.Code)
+	assert.Equal(t, []models.FieldError{
+		{Field: "title", Message: "is required"},
+		{Field: "priority", Message: "must be one of low, high"},
+	}, appErr.Fields, "only the first failing rule of a field is reported")
+
+	assert.NoError(t, validation.New().Err())
+}
+
+func TestDecode(t *testing.T) {
+	type request struct {
+		Title    string `json:"title"`
+		Priority string `json:"priority"`
+	}
+
+	var decoded request
+	require.NoError(t, validation.Decode([]byte(`{"title":"Sweep Floor"}`), &decoded))
+	assert.Equal(t, "Sweep Floor", decoded.Title)
+
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		fields []models.FieldError
+	}{
+		{"UnknownField", `{"title":"x","id":"42"}`, errors.CodeValidationFailed, []models.FieldError{{Field: "id", Message: "is not a known field"}}},
+		{"WrongType", `{"title":42}`, errors.CodeValidationFailed, []models.FieldError{{Field: "title", Message: "must be a string"}}},
+		{"Malformed", `{"title":`, errors.CodeMalformedBody, nil},
+		{"TrailingData", `{"title":"x"} {"title":"y"}`, errors.CodeMalformedBody, nil},
+		{"NotAnObject", strings.Repeat(" ", 3), errors.CodeMalformedBody, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded request
+			appErr := appError(t, validation.Decode([]byte(tt.body), &decoded))
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+			assert.Equal(t, tt.code, appErr.Code)
+			assert.Equal(t, tt.fields, appErr.Fields)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Package migrations embeds the schema migrations of every supported
+// database, so the binary can apply them without the SQL files on disk.
+//
+// Each driver has its own directory of numbered pairs of files named
+// NNNNNN_description.up.sql and NNNNNN_description.down.sql. The drivers
+// share version numbers: a version describes the same schema change in
+// every directory.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// For returns the migrations of driver, which names one of the
+// directories in this package.
+func For(driver string) (fs.FS, error) {
+	if _, err := fs.Stat(files, driver); err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+
+	return fs.Sub(files, driver)
+}
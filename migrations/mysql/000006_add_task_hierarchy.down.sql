@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    DROP FOREIGN KEY fk_tasks_parent,
+    DROP COLUMN auto_complete,
+    DROP COLUMN parent_id;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id CHAR(36) NULL,
+    ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD CONSTRAINT fk_tasks_parent FOREIGN KEY (parent_id) REFERENCES tasks(id) ON DELETE SET NULL;
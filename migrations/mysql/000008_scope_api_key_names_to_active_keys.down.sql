@@ -0,0 +1,4 @@
+ALTER TABLE api_keys
+    DROP INDEX unique_user_api_key_name,
+    ADD UNIQUE KEY unique_user_api_key_name (user_id, name),
+    DROP COLUMN active_name;
@@ -0,0 +1,6 @@
+-- Revoked keys keep their name but no longer reserve it: active_name is NULL
+-- once a key is revoked, and NULLs never collide in a unique key.
+ALTER TABLE api_keys
+    ADD COLUMN active_name VARCHAR(100) AS (IF(revoked_at IS NULL, name, NULL)) VIRTUAL,
+    DROP INDEX unique_user_api_key_name,
+    ADD UNIQUE KEY unique_user_api_key_name (user_id, active_name);
@@ -0,0 +1,3 @@
+DROP TABLE tasks;
+DROP TYPE task_status;
+DROP TYPE task_priority;
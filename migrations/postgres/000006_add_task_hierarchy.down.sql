@@ -0,0 +1,5 @@
+DROP INDEX idx_tasks_parent;
+
+ALTER TABLE tasks
+    DROP COLUMN auto_complete,
+    DROP COLUMN parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+    ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_tasks_parent ON tasks (parent_id);
@@ -0,0 +1,3 @@
+DROP INDEX unique_user_api_key_name;
+
+CREATE UNIQUE INDEX unique_user_api_key_name ON api_keys (user_id, LOWER(name));
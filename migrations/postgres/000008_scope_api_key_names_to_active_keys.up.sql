@@ -0,0 +1,4 @@
+-- Revoked keys keep their name but no longer reserve it.
+DROP INDEX unique_user_api_key_name;
+
+CREATE UNIQUE INDEX unique_user_api_key_name ON api_keys (user_id, LOWER(name)) WHERE revoked_at IS NULL;
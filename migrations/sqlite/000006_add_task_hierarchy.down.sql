@@ -0,0 +1,24 @@
+-- SQLite cannot drop a column that takes part in a foreign key, so the
+-- table is rebuilt without the hierarchy columns.
+CREATE TABLE tasks_without_hierarchy (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id TEXT REFERENCES categories(id) ON DELETE SET NULL,
+    title TEXT NOT NULL CHECK (length(title) <= 200),
+    description TEXT,
+    priority TEXT DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high')),
+    status TEXT DEFAULT 'pending' CHECK (status IN ('pending', 'in_progress', 'completed')),
+    due_date DATETIME,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tasks_without_hierarchy (id, user_id, category_id, title, description, priority, status, due_date, completed_at, created_at, updated_at)
+SELECT id, user_id, category_id, title, description, priority, status, due_date, completed_at, created_at, updated_at FROM tasks;
+
+DROP TABLE tasks;
+ALTER TABLE tasks_without_hierarchy RENAME TO tasks;
+
+CREATE INDEX idx_tasks_user ON tasks (user_id);
+CREATE INDEX idx_tasks_category ON tasks (category_id);
@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN parent_id TEXT REFERENCES tasks(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT FALSE CHECK (auto_complete IN (0, 1));
+
+CREATE INDEX idx_tasks_parent ON tasks (parent_id);